@@ -11,7 +11,8 @@ func main() {
 	viperConfig := config.NewViper()
 	log := config.NewLogger(viperConfig)
 	db := config.NewDatabase(viperConfig, log)
-	validator := config.NewValidator(db)
+	hasher := config.NewHasher(viperConfig)
+	validator := config.NewValidator(db, viperConfig, hasher)
 	app := config.NewFiber(viperConfig)
 	redis := config.NewRedis(viperConfig, log)
 
@@ -22,6 +23,7 @@ func main() {
 		Config:    viperConfig,
 		Log:       log,
 		Redis:     redis,
+		Hasher:    hasher,
 	})
 
 	baseUrl := viper.GetString("app.base_url")
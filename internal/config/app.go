@@ -1,10 +1,21 @@
 package config
 
 import (
+	"context"
+	"time"
+
 	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
 	"github.com/alfianyulianto/pds-service/pkg/auth"
+	"github.com/alfianyulianto/pds-service/pkg/clientip"
 	"github.com/alfianyulianto/pds-service/pkg/email"
+	"github.com/alfianyulianto/pds-service/pkg/encryption"
+	"github.com/alfianyulianto/pds-service/pkg/oidc"
+	"github.com/alfianyulianto/pds-service/pkg/password"
+	"github.com/alfianyulianto/pds-service/pkg/sms"
 	storage2 "github.com/alfianyulianto/pds-service/pkg/storage"
+	"github.com/alfianyulianto/pds-service/pkg/telegram"
+	"github.com/alfianyulianto/pds-service/pkg/throttle"
+	"github.com/alfianyulianto/pds-service/pkg/webauthn"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/redis/go-redis/v9"
@@ -14,6 +25,7 @@ import (
 
 	"github.com/alfianyulianto/pds-service/internal/delivery/http"
 	"github.com/alfianyulianto/pds-service/internal/delivery/http/router"
+	"github.com/alfianyulianto/pds-service/internal/delivery/worker"
 	"github.com/alfianyulianto/pds-service/internal/repository"
 	"github.com/alfianyulianto/pds-service/internal/usecase"
 )
@@ -25,6 +37,7 @@ type BootstrapConfig struct {
 	Config    *viper.Viper
 	Log       *logrus.Entry
 	Redis     *redis.Client
+	Hasher    password.Hasher
 }
 
 func Boostrap(config *BootstrapConfig) {
@@ -35,14 +48,29 @@ func Boostrap(config *BootstrapConfig) {
 	}
 
 	// token
+	var keyConfigs []auth.KeyConfig
+	if err := config.Config.UnmarshalKey("jwt.keys", &keyConfigs); err != nil {
+		config.Log.WithField("action", "bootstrap").WithError(err).Fatal("Failed to read jwt keys config")
+	}
+	keySet, err := auth.NewKeySet(keyConfigs, config.Config.GetString("jwt.active_key_id"), config.Config.GetString("jwt.secret_key"), config.Config.GetString("jwt.secret_key_retire_at"))
+	if err != nil {
+		config.Log.WithField("action", "bootstrap").WithError(err).Fatal("Failed to load jwt signing keys")
+	}
+
 	jwtConfig := &auth.JWTConfig{
 		AppName:               config.Config.GetString("app.name"),
 		ExpireDuration:        config.Config.GetInt("jwt.expire_duration"),
 		RefreshExpireDuration: config.Config.GetInt("jwt.refresh_expire_duration"),
-		SecreteKey:            config.Config.GetString("jwt.secret_key"),
+		Keys:                  keySet,
 	}
 	jwtService := auth.NewJWTService(jwtConfig, config.Redis)
 
+	// encryption
+	cipher, err := encryption.NewCipher(config.Config.GetString("app.encryption_key"))
+	if err != nil {
+		config.Log.WithField("action", "bootstrap").WithError(err).Fatal("Failed to read app encryption key config")
+	}
+
 	// smtp
 	smtpConfig := email.SMTPConfig{
 		Host:     config.Config.GetString("mail.host"),
@@ -53,30 +81,143 @@ func Boostrap(config *BootstrapConfig) {
 	}
 	emailService := email.NewEmailService(&smtpConfig)
 
+	// throttle
+	var throttleRules map[string]throttle.Rule
+	if err = config.Config.UnmarshalKey("throttle.rules", &throttleRules); err != nil {
+		config.Log.WithField("action", "bootstrap").WithError(err).Fatal("Failed to read throttle rules config")
+	}
+	authThrottle := throttle.NewThrottle(config.Redis, throttleRules)
+
+	// telegram
+	telegramClient := telegram.NewTelegramClient(config.Config.GetString("telegram.bot_token"), config.Config.GetString("telegram.chat_id"))
+
+	// oidc
+	var oidcConfigs map[string]oidc.ProviderConfig
+	if err = config.Config.UnmarshalKey("oidc.providers", &oidcConfigs); err != nil {
+		config.Log.WithField("action", "bootstrap").WithError(err).Fatal("Failed to read oidc providers config")
+	}
+	oidcProviders := oidc.NewProviders(oidcConfigs)
+
+	// webauthn
+	var webAuthnConfig webauthn.Config
+	if err = config.Config.UnmarshalKey("webauthn", &webAuthnConfig); err != nil {
+		config.Log.WithField("action", "bootstrap").WithError(err).Fatal("Failed to read webauthn config")
+	}
+	webAuthn := webauthn.New(webAuthnConfig)
+
+	// client ip
+	clientIPResolver, err := clientip.New(config.Config.GetStringSlice("app.trusted_proxies"))
+	if err != nil {
+		config.Log.WithField("action", "bootstrap").WithError(err).Fatal("Failed to read trusted proxies config")
+	}
+
+	// sms
+	var smsProvider sms.SMSProvider = sms.NewLogProvider(config.Log)
+	if config.Config.GetString("sms.driver") == "http" {
+		var httpConfig sms.HTTPConfig
+		if err = config.Config.UnmarshalKey("sms.http", &httpConfig); err != nil {
+			config.Log.WithField("action", "bootstrap").WithError(err).Fatal("Failed to read sms http config")
+		}
+		if smsProvider, err = sms.NewHTTPProvider(httpConfig); err != nil {
+			config.Log.WithField("action", "bootstrap").WithError(err).Fatal("Failed to create sms http provider")
+		}
+	}
+
+	// registration
+	switch mode := config.Config.GetString("registration.mode"); mode {
+	case "", "open", "invite_code", "approval":
+	default:
+		config.Log.WithField("action", "bootstrap").WithField("mode", mode).Fatal("Invalid registration mode config, use open, invite_code or approval")
+	}
+
 	// repositories
 	userRepository := repository.NewUserRepository(config.Log)
+	recoveryCodeRepository := repository.NewRecoveryCodeRepository(config.Log)
+	identityRepository := repository.NewIdentityRepository(config.Log)
+	apiKeyRepository := repository.NewApiKeyRepository(config.Log)
+	roleRepository := repository.NewRoleRepository(config.Log)
+	permissionRepository := repository.NewPermissionRepository(config.Log)
+	passwordHistoryRepository := repository.NewPasswordHistoryRepository(config.Log)
+	impersonationRepository := repository.NewImpersonationRepository(config.Log)
+	webAuthnCredentialRepository := repository.NewWebAuthnCredentialRepository(config.Log)
+	oauthClientRepository := repository.NewOAuthClientRepository(config.Log)
+	loginEventRepository := repository.NewLoginEventRepository(config.Log)
+	registrationCodeRepository := repository.NewRegistrationCodeRepository(config.Log)
 
 	// useCases (service)
-	baseUseCase := usecase.NewBaseUseCase(config.DB, config.Validator, storageProvider, config.Config, config.Log)
-	authUseCase := usecase.NewAuthUseCase(baseUseCase, userRepository, jwtService, emailService, config.Redis)
-	accountUseCase := usecase.NewAccountUseCase(baseUseCase, userRepository, emailService)
-	userUseCase := usecase.NewUserUseCase(baseUseCase, userRepository)
+	baseUseCase := usecase.NewBaseUseCase(config.DB, config.Validator, storageProvider, config.Config, config.Log, config.Hasher)
+	twoFactorUseCase := usecase.NewTwoFactorUseCase(baseUseCase, userRepository, recoveryCodeRepository, config.Redis, cipher)
+	identityUseCase := usecase.NewIdentityUseCase(baseUseCase, userRepository, identityRepository, emailService, config.Redis, oidcProviders)
+	passkeyUseCase := usecase.NewPasskeyUseCase(baseUseCase, userRepository, webAuthnCredentialRepository, config.Redis, webAuthn)
+	loginEventUseCase := usecase.NewLoginEventUseCase(baseUseCase, loginEventRepository)
+	phoneUseCase := usecase.NewPhoneUseCase(baseUseCase, userRepository, config.Redis, authThrottle, smsProvider)
+	authUseCase := usecase.NewAuthUseCase(baseUseCase, userRepository, jwtService, emailService, config.Redis, twoFactorUseCase, authThrottle, telegramClient, identityUseCase, passwordHistoryRepository, passkeyUseCase, loginEventUseCase, registrationCodeRepository, phoneUseCase)
+	accountUseCase := usecase.NewAccountUseCase(baseUseCase, userRepository, emailService, jwtService, passwordHistoryRepository, config.Redis)
+	roleUseCase := usecase.NewRoleUseCase(baseUseCase, roleRepository, permissionRepository, userRepository, config.Redis, jwtService)
+	userUseCase := usecase.NewUserUseCase(baseUseCase, userRepository, authThrottle, jwtService, passwordHistoryRepository, emailService, roleUseCase)
+	userImportUseCase := usecase.NewUserImportUseCase(baseUseCase, userUseCase, config.Redis)
+	userExportUseCase := usecase.NewUserExportUseCase(baseUseCase, userRepository, config.Redis)
+	sessionUseCase := usecase.NewSessionUseCase(baseUseCase, jwtService)
+	apiKeyUseCase := usecase.NewApiKeyUseCase(baseUseCase, userRepository, apiKeyRepository)
+	impersonationUseCase := usecase.NewImpersonationUseCase(baseUseCase, userRepository, impersonationRepository, roleUseCase, jwtService)
+	oauthClientUseCase := usecase.NewOAuthClientUseCase(baseUseCase, oauthClientRepository, jwtService)
+	registrationCodeUseCase := usecase.NewRegistrationCodeUseCase(baseUseCase, registrationCodeRepository)
 
 	// controller
 	authController := http.NewAuthController(authUseCase, config.Log)
 	accountController := http.NewAccountController(accountUseCase, config.Log, jwtService)
 	userController := http.NewUserController(userUseCase, config.Log)
+	twoFactorController := http.NewTwoFactorController(twoFactorUseCase, config.Log)
+	sessionController := http.NewSessionController(sessionUseCase, config.Log)
+	wellKnownController := http.NewWellKnownController(config.Log, jwtService)
+	identityController := http.NewIdentityController(identityUseCase, authUseCase, config.Log)
+	apiKeyController := http.NewApiKeyController(apiKeyUseCase, config.Log)
+	roleController := http.NewRoleController(roleUseCase, config.Log)
+	impersonationController := http.NewImpersonationController(impersonationUseCase, config.Log)
+	passkeyController := http.NewPasskeyController(passkeyUseCase, authUseCase, config.Log)
+	oauthController := http.NewOAuthController(oauthClientUseCase, config.Log)
+	loginEventController := http.NewLoginEventController(loginEventUseCase, config.Log)
+	registrationCodeController := http.NewRegistrationCodeController(registrationCodeUseCase, config.Log)
+	phoneController := http.NewPhoneController(phoneUseCase, authUseCase, config.Log)
+	userImportController := http.NewUserImportController(userImportUseCase, config.Log)
+	userExportController := http.NewUserExportController(userExportUseCase, config.Log)
 
 	// middleware
-	httpMiddleware := middleware.NewMiddleware(config.Log, jwtService)
+	httpMiddleware := middleware.NewMiddleware(config.Log, jwtService, accountUseCase, apiKeyUseCase, roleUseCase, oauthClientUseCase, clientIPResolver)
 
 	routerConfig := router.RouterConfig{
-		App:               config.App,
-		Middleware:        httpMiddleware,
-		AuthController:    authController,
-		AccountController: accountController,
-		UserController:    userController,
+		App:                        config.App,
+		Middleware:                 httpMiddleware,
+		AuthController:             authController,
+		AccountController:          accountController,
+		UserController:             userController,
+		TwoFactorController:        twoFactorController,
+		SessionController:          sessionController,
+		WellKnownController:        wellKnownController,
+		IdentityController:         identityController,
+		ApiKeyController:           apiKeyController,
+		RoleController:             roleController,
+		ImpersonationController:    impersonationController,
+		PasskeyController:          passkeyController,
+		OAuthController:            oauthController,
+		LoginEventController:       loginEventController,
+		RegistrationCodeController: registrationCodeController,
+		PhoneController:            phoneController,
+		UserImportController:       userImportController,
+		UserExportController:       userExportController,
+		VerifiedGroups:             config.Config.GetStringSlice("auth.verified_route_groups"),
+		ClientGroups:               config.Config.GetStringSlice("oauth.client_route_groups"),
 	}
 
 	routerConfig.Setup()
+
+	// worker
+	accountDeletionWorker := worker.NewAccountDeletionWorker(accountUseCase, config.Log, time.Duration(config.Config.GetInt("account_deletion.purge_interval"))*time.Second)
+	go accountDeletionWorker.Start(context.Background())
+
+	loginHistoryWorker := worker.NewLoginHistoryWorker(loginEventUseCase, config.Log, time.Duration(config.Config.GetInt("login_history.purge_interval"))*time.Second)
+	go loginHistoryWorker.Start(context.Background())
+
+	userExportWorker := worker.NewUserExportWorker(userExportUseCase, config.Log, time.Duration(config.Config.GetInt("export.purge_interval"))*time.Second)
+	go userExportWorker.Start(context.Background())
 }
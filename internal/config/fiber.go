@@ -1,7 +1,11 @@
 package config
 
 import (
+	"errors"
+	"strconv"
+
 	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/alfianyulianto/pds-service/pkg/throttle"
 	"github.com/alfianyulianto/pds-service/pkg/validators"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -24,6 +28,16 @@ func newErrorHandler(ctx *fiber.Ctx, err error) error {
 		code = e.Code
 	}
 
+	var lockedError *throttle.LockedError
+	if errors.As(err, &lockedError) {
+		ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(lockedError.Seconds()))
+		return ctx.Status(fiber.StatusTooManyRequests).JSON(response.Response[any]{
+			Success: false,
+			Message: fiber.NewError(fiber.StatusTooManyRequests).Message,
+			Error:   lockedError.Error(),
+		})
+	}
+
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		return ctx.Status(400).JSON(response.Response[any]{
 			Success: false,
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+	"github.com/alfianyulianto/pds-service/pkg/password"
+	"github.com/spf13/viper"
+)
+
+func NewHasher(config *viper.Viper) password.Hasher {
+	var hasherConfig password.HasherConfig
+	if err := config.UnmarshalKey("password_hashing", &hasherConfig); err != nil {
+		panic(fmt.Errorf("Fatal error password hashing config: %w \n", err))
+	}
+
+	hasher, err := password.NewHasher(hasherConfig)
+	if err != nil {
+		panic(fmt.Errorf("Fatal error password hashing: %w \n", err))
+	}
+
+	return hasher
+}
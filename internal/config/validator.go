@@ -1,19 +1,35 @@
 package config
 
 import (
+	"fmt"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/pkg/password"
 	validators2 "github.com/alfianyulianto/pds-service/pkg/validators"
 	"github.com/go-playground/validator/v10"
+	"github.com/spf13/viper"
 	"gorm.io/gorm"
 )
 
-func NewValidator(db *gorm.DB) *validator.Validate {
+func NewValidator(db *gorm.DB, config *viper.Viper, hasher password.Hasher) *validator.Validate {
 	validate := validator.New()
 
+	var policyConfig password.PolicyConfig
+	if err := config.UnmarshalKey("password_policy", &policyConfig); err != nil {
+		panic(fmt.Errorf("Fatal error password policy config: %w \n", err))
+	}
+	passwordPolicy, err := password.NewPolicy(policyConfig)
+	if err != nil {
+		panic(fmt.Errorf("Fatal error password policy: %w \n", err))
+	}
+
 	validate.RegisterValidation("exists", validators2.Exists(db))
 	validate.RegisterValidation("image", validators2.Image(db))
 	validate.RegisterValidation("unique", validators2.Unique(db))
 	validate.RegisterValidation("size", validators2.Size(db))
-	validate.RegisterValidation("match_password", validators2.MatchPassword(db))
+	validate.RegisterValidation("match_password", validators2.MatchPassword(db, hasher))
+	validate.RegisterStructValidation(validators2.PasswordPolicy(db, passwordPolicy, hasher),
+		model.RegisterUserRequest{}, model.UpdatePasswordRequest{}, model.ResetPasswordRequest{},
+		model.CreateUserRequest{}, model.UpdateUserRequest{}, model.AcceptInvitationRequest{})
 
 	return validate
 }
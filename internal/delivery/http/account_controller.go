@@ -14,6 +14,10 @@ type AccountController interface {
 	Current(ctx *fiber.Ctx) error
 	Logout(ctx *fiber.Ctx) error
 	UpdatePassword(ctx *fiber.Ctx) error
+	ChangeEmail(ctx *fiber.Ctx) error
+	ConfirmEmailChange(ctx *fiber.Ctx) error
+	CancelEmailChange(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
 }
 type accountController struct {
 	UseCase    usecase.AccountUseCase
@@ -28,7 +32,8 @@ func NewAccountController(useCase usecase.AccountUseCase, log *logrus.Entry, jwt
 func (c *accountController) Current(ctx *fiber.Ctx) error {
 	auth := middleware.GetUser(ctx)
 	request := &model.GetUserRequest{
-		ID: auth.ID,
+		ID:           auth.ID,
+		Impersonator: auth.Impersonator,
 	}
 
 	user, err := c.UseCase.Current(ctx.Context(), request)
@@ -67,6 +72,7 @@ func (c *accountController) UpdatePassword(ctx *fiber.Ctx) error {
 
 	auth := middleware.GetUser(ctx)
 	request.ID = auth.ID
+	request.SessionID = auth.SessionID
 
 	user, err := c.UseCase.UpdatePassword(ctx.Context(), request)
 	if err != nil {
@@ -79,3 +85,79 @@ func (c *accountController) UpdatePassword(ctx *fiber.Ctx) error {
 		Data:    user,
 	})
 }
+
+func (c *accountController) ChangeEmail(ctx *fiber.Ctx) error {
+	request := new(model.ChangeEmailRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "change email").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	request.ID = middleware.GetUser(ctx).ID
+
+	if err := c.UseCase.ChangeEmail(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Confirmation link has been sent to the new email address",
+	})
+}
+
+func (c *accountController) ConfirmEmailChange(ctx *fiber.Ctx) error {
+	request := new(model.ConfirmEmailChangeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "confirm email change").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := c.UseCase.ConfirmEmailChange(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "Email changed successfully",
+		Data:    user,
+	})
+}
+
+func (c *accountController) CancelEmailChange(ctx *fiber.Ctx) error {
+	request := new(model.CancelEmailChangeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "cancel email change").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := c.UseCase.CancelEmailChange(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Email change cancelled successfully",
+	})
+}
+
+func (c *accountController) Delete(ctx *fiber.Ctx) error {
+	request := new(model.DeleteAccountRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "delete account").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	request.ID = middleware.GetUser(ctx).ID
+
+	user, err := c.UseCase.Delete(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "Account deletion scheduled successfully, log in before the scheduled time to cancel it",
+		Data:    user,
+	})
+}
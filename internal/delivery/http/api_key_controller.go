@@ -0,0 +1,77 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type ApiKeyController interface {
+	List(ctx *fiber.Ctx) error
+	Create(ctx *fiber.Ctx) error
+	Revoke(ctx *fiber.Ctx) error
+}
+
+type apiKeyController struct {
+	UseCase usecase.ApiKeyUseCase
+	Log     *logrus.Entry
+}
+
+func NewApiKeyController(useCase usecase.ApiKeyUseCase, log *logrus.Entry) ApiKeyController {
+	return &apiKeyController{UseCase: useCase, Log: log}
+}
+
+func (c *apiKeyController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.ListApiKeyRequest{UserID: auth.ID}
+
+	apiKeys, err := c.UseCase.List(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.ApiKeyResponse]{
+		Success: true,
+		Message: "API keys retrieved successfully",
+		Data:    apiKeys,
+	})
+}
+
+func (c *apiKeyController) Create(ctx *fiber.Ctx) error {
+	request := new(model.CreateApiKeyRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "create api key").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	auth := middleware.GetUser(ctx)
+	request.UserID = auth.ID
+
+	apiKey, err := c.UseCase.Create(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.CreateApiKeyResponse]{
+		Success: true,
+		Message: "API key created successfully, copy the key now because it will not be shown again",
+		Data:    apiKey,
+	})
+}
+
+func (c *apiKeyController) Revoke(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.RevokeApiKeyRequest{UserID: auth.ID, ID: ctx.Params("id")}
+
+	if err := c.UseCase.Revoke(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "API key revoked successfully",
+	})
+}
@@ -2,12 +2,13 @@ package http
 
 import (
 	"context"
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
 	"github.com/alfianyulianto/pds-service/internal/model"
 	"github.com/alfianyulianto/pds-service/internal/usecase"
 	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/alfianyulianto/pds-service/pkg/useragent"
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
-	"strings"
 )
 
 type AuthController interface {
@@ -16,6 +17,11 @@ type AuthController interface {
 	RefreshToken(ctx *fiber.Ctx) error
 	RequestResetPassword(ctx *fiber.Ctx) error
 	ResetPassword(ctx *fiber.Ctx) error
+	VerifyTwoFactor(ctx *fiber.Ctx) error
+	VerifyEmail(ctx *fiber.Ctx) error
+	ResendVerification(ctx *fiber.Ctx) error
+	RequestMagicLink(ctx *fiber.Ctx) error
+	ConsumeMagicLink(ctx *fiber.Ctx) error
 }
 
 type authController struct {
@@ -39,9 +45,14 @@ func (c *authController) Register(ctx *fiber.Ctx) error {
 		return err
 	}
 
+	message := "User registered successfully"
+	if user.ApprovalStatus != nil {
+		message = "User registered successfully, your account can be used after it is approved by an administrator"
+	}
+
 	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
 		Success: true,
-		Message: "User registered successfully",
+		Message: message,
 		Data:    user,
 	})
 }
@@ -53,16 +64,30 @@ func (c *authController) Login(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	ua := strings.ToLower(ctx.Get("User-Agent"))
-	device := "desktop"
-	if strings.Contains(ua, "mobile") {
-		device = "mobile"
+	setClientInfo(ctx)
+
+	token, err := c.UseCase.Login(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.AuthResponse]{
+		Success: true,
+		Message: "User logged in successfully",
+		Data:    token,
+	})
+}
+
+func (c *authController) VerifyTwoFactor(ctx *fiber.Ctx) error {
+	request := new(model.TwoFactorLoginRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "verify two factor").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	deviceContext := context.WithValue(ctx.UserContext(), "DeviceTypeKey", device)
-	ctx.SetUserContext(deviceContext)
+	setClientInfo(ctx)
 
-	token, err := c.UseCase.Login(ctx.UserContext(), request)
+	token, err := c.UseCase.VerifyTwoFactor(ctx.UserContext(), request)
 	if err != nil {
 		return err
 	}
@@ -81,7 +106,9 @@ func (c *authController) RefreshToken(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	token, err := c.UseCase.RefreshToken(ctx.Context(), request)
+	setClientInfo(ctx)
+
+	token, err := c.UseCase.RefreshToken(ctx.UserContext(), request)
 	if err != nil {
 		return err
 	}
@@ -100,7 +127,9 @@ func (c *authController) RequestResetPassword(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	if err := c.UseCase.RequestResetPassword(ctx.Context(), request); err != nil {
+	setClientInfo(ctx)
+
+	if err := c.UseCase.RequestResetPassword(ctx.UserContext(), request); err != nil {
 		return err
 	}
 
@@ -117,7 +146,9 @@ func (c *authController) ResetPassword(ctx *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	if err := c.UseCase.ResetPassword(ctx.Context(), request); err != nil {
+	setClientInfo(ctx)
+
+	if err := c.UseCase.ResetPassword(ctx.UserContext(), request); err != nil {
 		return err
 	}
 
@@ -126,3 +157,98 @@ func (c *authController) ResetPassword(ctx *fiber.Ctx) error {
 		Message: "Password has been reset successfully.",
 	})
 }
+
+func (c *authController) VerifyEmail(ctx *fiber.Ctx) error {
+	request := new(model.VerifyEmailRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "verify email").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := c.UseCase.VerifyEmail(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Email has been verified successfully.",
+	})
+}
+
+func (c *authController) ResendVerification(ctx *fiber.Ctx) error {
+	request := new(model.ResendVerificationRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "resend verification").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	setClientInfo(ctx)
+
+	if err := c.UseCase.ResendVerification(ctx.UserContext(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "If the email is registered and not yet verified, a verification email has been sent. Please check your email.",
+	})
+}
+
+func (c *authController) RequestMagicLink(ctx *fiber.Ctx) error {
+	request := new(model.MagicLinkRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "request magic link").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	setClientInfo(ctx)
+
+	magicLink, err := c.UseCase.RequestMagicLink(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.MagicLinkResponse]{
+		Success: true,
+		Message: "If the email is registered, a magic link has been sent to your email",
+		Data:    magicLink,
+	})
+}
+
+func (c *authController) ConsumeMagicLink(ctx *fiber.Ctx) error {
+	request := new(model.ConsumeMagicLinkRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "consume magic link").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	setClientInfo(ctx)
+
+	token, err := c.UseCase.ConsumeMagicLink(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.AuthResponse]{
+		Success: true,
+		Message: "User logged in successfully",
+		Data:    token,
+	})
+}
+
+// setClientInfo menyimpan informasi perangkat (jenis perangkat, browser, sistem operasi, IP, User-Agent) ke context untuk session dan notifikasi login
+func setClientInfo(ctx *fiber.Ctx) {
+	userAgent := ctx.Get("User-Agent")
+	agent := useragent.Parse(userAgent)
+
+	clientContext := context.WithValue(ctx.UserContext(), "ClientInfoKey", &model.ClientInfo{
+		Device:         agent.Device,
+		IPAddress:      middleware.GetClientIP(ctx),
+		UserAgent:      userAgent,
+		Browser:        agent.Browser,
+		BrowserVersion: agent.BrowserVersion,
+		OS:             agent.OS,
+		OSVersion:      agent.OSVersion,
+	})
+	ctx.SetUserContext(clientContext)
+}
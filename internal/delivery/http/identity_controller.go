@@ -0,0 +1,134 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type IdentityController interface {
+	Authorize(ctx *fiber.Ctx) error
+	Callback(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	LinkAuthorize(ctx *fiber.Ctx) error
+	LinkCallback(ctx *fiber.Ctx) error
+	Unlink(ctx *fiber.Ctx) error
+}
+
+type identityController struct {
+	UseCase     usecase.IdentityUseCase
+	AuthUseCase usecase.AuthUseCase
+	Log         *logrus.Entry
+}
+
+func NewIdentityController(useCase usecase.IdentityUseCase, authUseCase usecase.AuthUseCase, log *logrus.Entry) IdentityController {
+	return &identityController{UseCase: useCase, AuthUseCase: authUseCase, Log: log}
+}
+
+func (c *identityController) Authorize(ctx *fiber.Ctx) error {
+	request := &model.OIDCAuthorizeRequest{Provider: ctx.Params("provider")}
+
+	authorize, err := c.UseCase.Authorize(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.OIDCAuthorizeResponse]{
+		Success: true,
+		Message: "Authorization url created successfully",
+		Data:    authorize,
+	})
+}
+
+func (c *identityController) Callback(ctx *fiber.Ctx) error {
+	request := new(model.OIDCCallbackRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "oidc login").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.Provider = ctx.Params("provider")
+
+	setClientInfo(ctx)
+
+	token, err := c.AuthUseCase.OIDCLogin(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.AuthResponse]{
+		Success: true,
+		Message: "User logged in successfully",
+		Data:    token,
+	})
+}
+
+func (c *identityController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.ListIdentityRequest{UserID: auth.ID}
+
+	identities, err := c.UseCase.List(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.IdentityResponse]{
+		Success: true,
+		Message: "Identities retrieved successfully",
+		Data:    identities,
+	})
+}
+
+func (c *identityController) LinkAuthorize(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.OIDCAuthorizeRequest{Provider: ctx.Params("provider"), UserID: auth.ID}
+
+	authorize, err := c.UseCase.Authorize(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.OIDCAuthorizeResponse]{
+		Success: true,
+		Message: "Authorization url created successfully",
+		Data:    authorize,
+	})
+}
+
+func (c *identityController) LinkCallback(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := new(model.OIDCCallbackRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "link identity").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.Provider = ctx.Params("provider")
+	request.UserID = auth.ID
+
+	identity, err := c.UseCase.Link(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.IdentityResponse]{
+		Success: true,
+		Message: "Identity linked successfully",
+		Data:    identity,
+	})
+}
+
+func (c *identityController) Unlink(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.UnlinkIdentityRequest{UserID: auth.ID, ID: ctx.Params("id")}
+
+	if err := c.UseCase.Unlink(ctx.UserContext(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Identity unlinked successfully",
+	})
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ImpersonationController interface {
+	Start(ctx *fiber.Ctx) error
+	Stop(ctx *fiber.Ctx) error
+}
+
+type impersonationController struct {
+	UseCase usecase.ImpersonationUseCase
+	Log     *logrus.Entry
+}
+
+func NewImpersonationController(useCase usecase.ImpersonationUseCase, log *logrus.Entry) ImpersonationController {
+	return &impersonationController{UseCase: useCase, Log: log}
+}
+
+func (c *impersonationController) Start(ctx *fiber.Ctx) error {
+	request := new(model.ImpersonateRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "start impersonation").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var err error
+	request.ID, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		c.Log.WithError(err).Warn("Failed to parse id")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.ImpersonatorID = middleware.GetUser(ctx).ID
+	request.IPAddress = middleware.GetClientIP(ctx)
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+
+	token, err := c.UseCase.Start(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.AuthResponse]{
+		Success: true,
+		Message: "Impersonation started successfully",
+		Data:    token,
+	})
+}
+
+func (c *impersonationController) Stop(ctx *fiber.Ctx) error {
+	if err := c.UseCase.Stop(ctx.Context(), middleware.GetUser(ctx)); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.AuthResponse]{
+		Success: true,
+		Message: "Impersonation stopped successfully",
+	})
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type LoginEventController interface {
+	History(ctx *fiber.Ctx) error
+	Search(ctx *fiber.Ctx) error
+}
+
+type loginEventController struct {
+	UseCase usecase.LoginEventUseCase
+	Log     *logrus.Entry
+}
+
+func NewLoginEventController(useCase usecase.LoginEventUseCase, log *logrus.Entry) LoginEventController {
+	return &loginEventController{UseCase: useCase, Log: log}
+}
+
+func (c *loginEventController) History(ctx *fiber.Ctx) error {
+	request := &model.ListLoginHistoryRequest{UserID: middleware.GetUser(ctx).ID}
+	request.Page = ctx.QueryInt("page", 1)
+	request.PageSize = ctx.QueryInt("page_size", 10)
+
+	events, pagination, err := c.UseCase.History(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.LoginEventResponse]{
+		Success:    true,
+		Message:    "Login history retrieved successfully",
+		Data:       events,
+		Pagination: pagination,
+	})
+}
+
+func (c *loginEventController) Search(ctx *fiber.Ctx) error {
+	request := new(model.SearchLoginEventRequest)
+
+	request.UserID = ctx.Query("user_id")
+	request.Email = ctx.Query("email")
+	request.IPAddress = ctx.Query("ip_address")
+	request.Method = ctx.Query("method")
+	request.Success = ctx.Query("success")
+	request.FailureReason = ctx.Query("failure_reason")
+	request.Page = ctx.QueryInt("page", 1)
+	request.PageSize = ctx.QueryInt("page_size", 10)
+
+	// from dan to dalam format RFC 3339
+	for key, target := range map[string]**time.Time{"from": &request.From, "to": &request.To} {
+		if value := ctx.Query(key); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.Log.WithField("action", "search login event").WithError(err).Warn("Failed to parse " + key)
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid "+key+", use RFC 3339 format")
+			}
+			*target = &parsed
+		}
+	}
+
+	events, pagination, err := c.UseCase.Search(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.LoginEventResponse]{
+		Success:    true,
+		Message:    "Login events retrieved successfully",
+		Data:       events,
+		Pagination: pagination,
+	})
+}
@@ -1,11 +1,19 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
 	"github.com/gofiber/fiber/v2"
 )
 
 func (m *Middleware) AuthMiddleware(ctx *fiber.Ctx) error {
+	// API key bisa dikirim lewat header X-API-Key atau sebagai bearer token dengan prefix usecase.ApiKeyPrefix
+	if apiKey := ctx.Get("X-API-Key", ""); apiKey != "" {
+		return m.authenticateApiKey(ctx, apiKey)
+	}
+
 	authHeader := ctx.Get("Authorization", "")
 	if authHeader == "" {
 		m.Log.WithField("action", "authentication middleware").Warn("Authorization header is missing")
@@ -20,20 +28,68 @@ func (m *Middleware) AuthMiddleware(ctx *fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
+	if strings.HasPrefix(token, usecase.ApiKeyPrefix) {
+		return m.authenticateApiKey(ctx, token)
+	}
+
 	request := &model.VerifyUserRequest{Token: token}
 	m.Log.WithField("action", "authentication middleware").Debugf("Authorization : %s", request.Token)
 
 	userClaim, err := m.Jwt.ParseAccessToken(ctx.Context(), request.Token)
+	if err != nil && clientsAllowed(ctx) {
+		if clientClaim, clientErr := m.Jwt.ParseClientToken(ctx.Context(), request.Token); clientErr == nil {
+			return m.authenticateClient(ctx, clientClaim)
+		}
+	}
 	if err != nil {
-		m.Log.WithField("action", "authentication middleware").WithError(err).Warn("Failed find user by token:", err)
+		m.Log.WithField("action", "authentication middleware").WithError(err).Warn("Failed find user by token")
 		return fiber.ErrUnauthorized
 	}
 
-	m.Log.Debugf("Auth:", userClaim)
+	// token user yang dinonaktifkan atau di suspend sudah di revoke, tapi status akun tetap dicek di setiap request
+	if err = m.AccountUseCase.CheckStatus(ctx.Context(), userClaim.ID); err != nil {
+		m.Log.WithField("action", "authentication middleware").WithField("user_id", userClaim.ID).WithError(err).Warn("Account is not active")
+		return err
+	}
+
+	m.Log.Debugf("Auth: %+v", userClaim)
 	ctx.Locals("auth", userClaim)
 	return ctx.Next()
 }
 
+func (m *Middleware) authenticateApiKey(ctx *fiber.Ctx, apiKey string) error {
+	userClaim, err := m.ApiKeyUseCase.Authenticate(ctx.Context(), apiKey, GetClientIP(ctx))
+	if err != nil {
+		return err
+	}
+
+	m.Log.Debugf("Auth: %+v", userClaim)
+	ctx.Locals("auth", userClaim)
+	return ctx.Next()
+}
+
+func (m *Middleware) authenticateClient(ctx *fiber.Ctx, clientClaim *model.UserClaimToken) error {
+	if err := m.OAuthClientUseCase.CheckStatus(ctx.Context(), clientClaim.ClientID); err != nil {
+		m.Log.WithField("action", "authentication middleware").WithField("client_id", clientClaim.ClientID).WithError(err).Warn("OAuth client is not active")
+		return err
+	}
+
+	m.Log.Debugf("Auth: %+v", clientClaim)
+	ctx.Locals("auth", clientClaim)
+	return ctx.Next()
+}
+
+// AllowClients menandai route yang boleh diakses token OAuth client (grant client_credentials), harus dipasang sebelum AuthMiddleware
+func (m *Middleware) AllowClients(ctx *fiber.Ctx) error {
+	ctx.Locals("allow_clients", true)
+	return ctx.Next()
+}
+
+func clientsAllowed(ctx *fiber.Ctx) bool {
+	allowed, _ := ctx.Locals("allow_clients").(bool)
+	return allowed
+}
+
 func GetUser(ctx *fiber.Ctx) *model.UserClaimToken {
 	return ctx.Locals("auth").(*model.UserClaimToken)
 }
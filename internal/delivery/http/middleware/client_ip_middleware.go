@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// RealIP menentukan IP asli client dari X-Forwarded-For yang dikirim trusted proxy, dipasang untuk semua route
+func (m *Middleware) RealIP(ctx *fiber.Ctx) error {
+	ctx.Locals("client_ip", m.ClientIP.Resolve(ctx.IP(), ctx.Get(fiber.HeaderXForwardedFor)))
+	return ctx.Next()
+}
+
+// GetClientIP mengembalikan IP client hasil RealIP, atau IP koneksi jika RealIP tidak dipasang
+func GetClientIP(ctx *fiber.Ctx) string {
+	if ip, ok := ctx.Locals("client_ip").(string); ok {
+		return ip
+	}
+
+	return ctx.IP()
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// EmailVerifiedMiddleware menolak akses akun yang belum memverifikasi email, harus dipasang setelah AuthMiddleware.
+// Token OAuth client tidak terikat ke akun sehingga dilewati
+func (m *Middleware) EmailVerifiedMiddleware(ctx *fiber.Ctx) error {
+	auth := GetUser(ctx)
+	if auth.Type == "client" {
+		return ctx.Next()
+	}
+
+	user, err := m.AccountUseCase.Current(ctx.Context(), &model.GetUserRequest{ID: auth.ID})
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		m.Log.WithField("action", "email verified middleware").WithField("user_id", auth.ID).Warn("Email address has not been verified")
+		return fiber.NewError(fiber.StatusForbidden, "Email address has not been verified")
+	}
+
+	return ctx.Next()
+}
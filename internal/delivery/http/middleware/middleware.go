@@ -1,15 +1,22 @@
 package middleware
 
 import (
+	"github.com/alfianyulianto/pds-service/internal/usecase"
 	"github.com/alfianyulianto/pds-service/pkg/auth"
+	"github.com/alfianyulianto/pds-service/pkg/clientip"
 	"github.com/sirupsen/logrus"
 )
 
 type Middleware struct {
-	Log *logrus.Entry
-	Jwt *auth.JWTService
+	Log                *logrus.Entry
+	Jwt                *auth.JWTService
+	AccountUseCase     usecase.AccountUseCase
+	ApiKeyUseCase      usecase.ApiKeyUseCase
+	RoleUseCase        usecase.RoleUseCase
+	OAuthClientUseCase usecase.OAuthClientUseCase
+	ClientIP           *clientip.Resolver
 }
 
-func NewMiddleware(log *logrus.Entry, jwt *auth.JWTService) *Middleware {
-	return &Middleware{Log: log, Jwt: jwt}
+func NewMiddleware(log *logrus.Entry, jwt *auth.JWTService, accountUseCase usecase.AccountUseCase, apiKeyUseCase usecase.ApiKeyUseCase, roleUseCase usecase.RoleUseCase, oauthClientUseCase usecase.OAuthClientUseCase, clientIP *clientip.Resolver) *Middleware {
+	return &Middleware{Log: log, Jwt: jwt, AccountUseCase: accountUseCase, ApiKeyUseCase: apiKeyUseCase, RoleUseCase: roleUseCase, OAuthClientUseCase: oauthClientUseCase, ClientIP: clientIP}
 }
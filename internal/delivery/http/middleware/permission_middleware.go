@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequirePermission menolak request jika role user tidak memiliki permission tersebut, harus dipasang setelah AuthMiddleware.
+// Token OAuth client tidak punya role, scope-nya berupa nama permission
+func (m *Middleware) RequirePermission(permission string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		auth := GetUser(ctx)
+
+		if auth.Type == "client" {
+			if !slices.Contains(auth.Scopes, permission) {
+				m.Log.WithField("action", "permission middleware").WithField("client_id", auth.ClientID).WithField("permission", permission).Warn("OAuth client does not have the required scope")
+				return fiber.NewError(fiber.StatusForbidden, "OAuth client does not have the required scope")
+			}
+			return ctx.Next()
+		}
+
+		permissions, err := m.RoleUseCase.Permissions(ctx.Context(), auth.Role)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(permissions, permission) {
+			m.Log.WithField("action", "permission middleware").WithField("user_id", auth.ID).WithField("permission", permission).Warn("User does not have the required permission")
+			return fiber.NewError(fiber.StatusForbidden, "You do not have permission to perform this action")
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireScope membatasi akses API key ke group route: GET membutuhkan scope "<group>:read", method lain "<group>:write".
+// Request dengan access token (login biasa) tidak dibatasi, token OAuth client dibatasi di RequirePermission. Harus dipasang setelah AuthMiddleware
+func (m *Middleware) RequireScope(group string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		auth := GetUser(ctx)
+		if auth.Type != "api_key" {
+			return ctx.Next()
+		}
+
+		scope := group + ":write"
+		if ctx.Method() == fiber.MethodGet || ctx.Method() == fiber.MethodHead {
+			scope = group + ":read"
+		}
+
+		if !slices.Contains(auth.Scopes, scope) && !slices.Contains(auth.Scopes, "*") {
+			m.Log.WithField("action", "scope middleware").WithField("user_id", auth.ID).WithField("scope", scope).Warn("API key does not have the required scope")
+			return fiber.NewError(fiber.StatusForbidden, "API key does not have the required scope")
+		}
+
+		return ctx.Next()
+	}
+}
+
+// SessionOnly menolak API key dan token OAuth client untuk endpoint yang hanya boleh dipakai dari login interaktif (password, 2FA, session, API key)
+func (m *Middleware) SessionOnly(ctx *fiber.Ctx) error {
+	switch GetUser(ctx).Type {
+	case "api_key":
+		return fiber.NewError(fiber.StatusForbidden, "This endpoint cannot be accessed with an API key")
+	case "client":
+		return fiber.NewError(fiber.StatusForbidden, "This endpoint cannot be accessed with an OAuth client token")
+	}
+
+	return ctx.Next()
+}
+
+// RefuseImpersonation menolak aksi sensitif (password, 2FA, session, API key, dll) ketika admin sedang impersonate user
+func (m *Middleware) RefuseImpersonation(ctx *fiber.Ctx) error {
+	if auth := GetUser(ctx); auth.Impersonator != nil {
+		m.Log.WithField("action", "impersonation middleware").WithField("user_id", auth.ID).WithField("impersonator_id", auth.Impersonator).Warn("Sensitive action refused while impersonating")
+		return fiber.NewError(fiber.StatusForbidden, "This action is not allowed while impersonating a user")
+	}
+
+	return ctx.Next()
+}
+
+// RefuseClient menolak token OAuth client untuk endpoint yang membutuhkan user sebagai pelaku (misalnya untuk audit)
+func (m *Middleware) RefuseClient(ctx *fiber.Ctx) error {
+	if auth := GetUser(ctx); auth.Type == "client" {
+		m.Log.WithField("action", "client middleware").WithField("client_id", auth.ClientID).Warn("OAuth client refused on user only endpoint")
+		return fiber.NewError(fiber.StatusForbidden, "This endpoint cannot be accessed with an OAuth client token")
+	}
+
+	return ctx.Next()
+}
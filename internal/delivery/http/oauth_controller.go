@@ -0,0 +1,138 @@
+package http
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type OAuthController interface {
+	Token(ctx *fiber.Ctx) error
+	ListClients(ctx *fiber.Ctx) error
+	CreateClient(ctx *fiber.Ctx) error
+	RevokeClient(ctx *fiber.Ctx) error
+}
+
+type oauthController struct {
+	UseCase usecase.OAuthClientUseCase
+	Log     *logrus.Entry
+}
+
+func NewOAuthController(useCase usecase.OAuthClientUseCase, log *logrus.Entry) OAuthController {
+	return &oauthController{UseCase: useCase, Log: log}
+}
+
+// Token mengikuti format RFC 6749 (bukan response.Response) supaya bisa langsung dipakai library OAuth2 di service lain
+func (c *oauthController) Token(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+	ctx.Set(fiber.HeaderPragma, "no-cache")
+
+	request := new(model.OAuthTokenRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "issue oauth token").WithError(err).Warn("Failed to parse request body")
+		return ctx.Status(fiber.StatusBadRequest).JSON(&model.OAuthError{Code: "invalid_request", Description: "Request body could not be parsed"})
+	}
+
+	// HTTP Basic lebih diutamakan dari kredensial di body (RFC 6749 section 2.3.1)
+	basic := strings.HasPrefix(ctx.Get(fiber.HeaderAuthorization), "Basic ")
+	if basic {
+		clientID, clientSecret, ok := parseBasicAuth(ctx.Get(fiber.HeaderAuthorization))
+		if !ok {
+			return ctx.Status(fiber.StatusBadRequest).JSON(&model.OAuthError{Code: "invalid_request", Description: "Malformed basic authorization header"})
+		}
+		request.ClientID, request.ClientSecret = clientID, clientSecret
+	}
+
+	token, err := c.UseCase.IssueToken(ctx.Context(), request)
+	var oauthErr *model.OAuthError
+	if errors.As(err, &oauthErr) {
+		if oauthErr.Status == fiber.StatusUnauthorized && basic {
+			ctx.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+		}
+		return ctx.Status(oauthErr.Status).JSON(oauthErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(token)
+}
+
+func (c *oauthController) ListClients(ctx *fiber.Ctx) error {
+	clients, err := c.UseCase.List(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.OAuthClientResponse]{
+		Success: true,
+		Message: "OAuth clients retrieved successfully",
+		Data:    clients,
+	})
+}
+
+func (c *oauthController) CreateClient(ctx *fiber.Ctx) error {
+	request := new(model.CreateOAuthClientRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "create oauth client").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	request.CreatedBy = middleware.GetUser(ctx).ID
+
+	client, err := c.UseCase.Create(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.CreateOAuthClientResponse]{
+		Success: true,
+		Message: "OAuth client created successfully, copy the client secret now because it will not be shown again",
+		Data:    client,
+	})
+}
+
+func (c *oauthController) RevokeClient(ctx *fiber.Ctx) error {
+	request := &model.RevokeOAuthClientRequest{ID: ctx.Params("id")}
+
+	if err := c.UseCase.Revoke(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "OAuth client revoked successfully",
+	})
+}
+
+// parseBasicAuth membaca client_id dan client_secret dari header Basic, keduanya di form-urlencode sebelum di base64 (RFC 6749 section 2.3.1)
+func parseBasicAuth(header string) (string, string, bool) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "Basic "))
+	if err != nil {
+		return "", "", false
+	}
+
+	rawID, rawSecret, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", false
+	}
+
+	clientID, err := url.QueryUnescape(rawID)
+	if err != nil {
+		return "", "", false
+	}
+	clientSecret, err := url.QueryUnescape(rawSecret)
+	if err != nil {
+		return "", "", false
+	}
+
+	return clientID, clientSecret, true
+}
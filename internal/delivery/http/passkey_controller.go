@@ -0,0 +1,137 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/alfianyulianto/pds-service/pkg/webauthn"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type PasskeyController interface {
+	RegistrationOptions(ctx *fiber.Ctx) error
+	Register(ctx *fiber.Ctx) error
+	List(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	LoginOptions(ctx *fiber.Ctx) error
+	Login(ctx *fiber.Ctx) error
+}
+
+type passkeyController struct {
+	UseCase     usecase.PasskeyUseCase
+	AuthUseCase usecase.AuthUseCase
+	Log         *logrus.Entry
+}
+
+func NewPasskeyController(useCase usecase.PasskeyUseCase, authUseCase usecase.AuthUseCase, log *logrus.Entry) PasskeyController {
+	return &passkeyController{UseCase: useCase, AuthUseCase: authUseCase, Log: log}
+}
+
+func (c *passkeyController) RegistrationOptions(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.PasskeyRegistrationOptionsRequest{UserID: auth.ID}
+
+	options, err := c.UseCase.RegistrationOptions(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*webauthn.CreationOptions]{
+		Success: true,
+		Message: "Passkey registration options created successfully",
+		Data:    options,
+	})
+}
+
+func (c *passkeyController) Register(ctx *fiber.Ctx) error {
+	request := new(model.RegisterPasskeyRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "register passkey").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	request.UserID = middleware.GetUser(ctx).ID
+
+	passkey, err := c.UseCase.Register(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.PasskeyResponse]{
+		Success: true,
+		Message: "Passkey registered successfully",
+		Data:    passkey,
+	})
+}
+
+func (c *passkeyController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.ListPasskeyRequest{UserID: auth.ID}
+
+	passkeys, err := c.UseCase.List(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.PasskeyResponse]{
+		Success: true,
+		Message: "Passkeys retrieved successfully",
+		Data:    passkeys,
+	})
+}
+
+func (c *passkeyController) Delete(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.DeletePasskeyRequest{UserID: auth.ID, ID: ctx.Params("id")}
+
+	if err := c.UseCase.Delete(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Passkey deleted successfully",
+	})
+}
+
+func (c *passkeyController) LoginOptions(ctx *fiber.Ctx) error {
+	request := new(model.PasskeyLoginOptionsRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "passkey login options").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	options, err := c.UseCase.LoginOptions(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*webauthn.RequestOptions]{
+		Success: true,
+		Message: "Passkey login options created successfully",
+		Data:    options,
+	})
+}
+
+func (c *passkeyController) Login(ctx *fiber.Ctx) error {
+	request := new(model.PasskeyLoginRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "passkey login").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	setClientInfo(ctx)
+
+	token, err := c.AuthUseCase.PasskeyLogin(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.AuthResponse]{
+		Success: true,
+		Message: "User logged in successfully",
+		Data:    token,
+	})
+}
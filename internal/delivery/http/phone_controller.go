@@ -0,0 +1,112 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type PhoneController interface {
+	SendVerificationCode(ctx *fiber.Ctx) error
+	Verify(ctx *fiber.Ctx) error
+	LoginCode(ctx *fiber.Ctx) error
+	Login(ctx *fiber.Ctx) error
+}
+
+type phoneController struct {
+	UseCase     usecase.PhoneUseCase
+	AuthUseCase usecase.AuthUseCase
+	Log         *logrus.Entry
+}
+
+func NewPhoneController(useCase usecase.PhoneUseCase, authUseCase usecase.AuthUseCase, log *logrus.Entry) PhoneController {
+	return &phoneController{UseCase: useCase, AuthUseCase: authUseCase, Log: log}
+}
+
+func (c *phoneController) SendVerificationCode(ctx *fiber.Ctx) error {
+	request := new(model.SendPhoneVerificationRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "send phone verification").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	request.UserID = middleware.GetUser(ctx).ID
+	request.IPAddress = middleware.GetClientIP(ctx)
+
+	code, err := c.UseCase.SendVerificationCode(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.PhoneCodeResponse]{
+		Success: true,
+		Message: "Verification code has been sent to your phone",
+		Data:    code,
+	})
+}
+
+func (c *phoneController) Verify(ctx *fiber.Ctx) error {
+	request := new(model.VerifyPhoneRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "verify phone").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	request.UserID = middleware.GetUser(ctx).ID
+
+	user, err := c.UseCase.Verify(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "Phone number verified successfully",
+		Data:    user,
+	})
+}
+
+func (c *phoneController) LoginCode(ctx *fiber.Ctx) error {
+	request := new(model.PhoneLoginCodeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "request phone login code").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	setClientInfo(ctx)
+
+	code, err := c.AuthUseCase.RequestPhoneLoginCode(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.PhoneCodeResponse]{
+		Success: true,
+		Message: "If the phone number is registered, a login code has been sent",
+		Data:    code,
+	})
+}
+
+func (c *phoneController) Login(ctx *fiber.Ctx) error {
+	request := new(model.PhoneLoginRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "phone login").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	setClientInfo(ctx)
+
+	token, err := c.AuthUseCase.PhoneLogin(ctx.UserContext(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.AuthResponse]{
+		Success: true,
+		Message: "User logged in successfully",
+		Data:    token,
+	})
+}
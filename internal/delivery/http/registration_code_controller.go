@@ -0,0 +1,72 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type RegistrationCodeController interface {
+	List(ctx *fiber.Ctx) error
+	Create(ctx *fiber.Ctx) error
+	Revoke(ctx *fiber.Ctx) error
+}
+
+type registrationCodeController struct {
+	UseCase usecase.RegistrationCodeUseCase
+	Log     *logrus.Entry
+}
+
+func NewRegistrationCodeController(useCase usecase.RegistrationCodeUseCase, log *logrus.Entry) RegistrationCodeController {
+	return &registrationCodeController{UseCase: useCase, Log: log}
+}
+
+func (c *registrationCodeController) List(ctx *fiber.Ctx) error {
+	codes, err := c.UseCase.List(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.RegistrationCodeResponse]{
+		Success: true,
+		Message: "Registration codes retrieved successfully",
+		Data:    codes,
+	})
+}
+
+func (c *registrationCodeController) Create(ctx *fiber.Ctx) error {
+	request := new(model.CreateRegistrationCodeRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "create registration code").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	request.CreatedBy = middleware.GetUser(ctx).ID
+
+	code, err := c.UseCase.Create(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.CreateRegistrationCodeResponse]{
+		Success: true,
+		Message: "Registration code created successfully, copy the code now because it will not be shown again",
+		Data:    code,
+	})
+}
+
+func (c *registrationCodeController) Revoke(ctx *fiber.Ctx) error {
+	request := &model.RevokeRegistrationCodeRequest{ID: ctx.Params("id")}
+
+	if err := c.UseCase.Revoke(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Registration code revoked successfully",
+	})
+}
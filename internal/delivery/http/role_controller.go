@@ -0,0 +1,152 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type RoleController interface {
+	List(ctx *fiber.Ctx) error
+	FindById(ctx *fiber.Ctx) error
+	Create(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	ListPermissions(ctx *fiber.Ctx) error
+	AssignRole(ctx *fiber.Ctx) error
+}
+
+type roleController struct {
+	UseCase usecase.RoleUseCase
+	Log     *logrus.Entry
+}
+
+func NewRoleController(useCase usecase.RoleUseCase, log *logrus.Entry) RoleController {
+	return &roleController{UseCase: useCase, Log: log}
+}
+
+func (c *roleController) List(ctx *fiber.Ctx) error {
+	roles, err := c.UseCase.List(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.RoleResponse]{
+		Success: true,
+		Message: "Roles retrieved successfully",
+		Data:    roles,
+	})
+}
+
+func (c *roleController) FindById(ctx *fiber.Ctx) error {
+	role, err := c.UseCase.FindById(ctx.Context(), ctx.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.RoleResponse]{
+		Success: true,
+		Message: "Role retrieved successfully",
+		Data:    role,
+	})
+}
+
+func (c *roleController) Create(ctx *fiber.Ctx) error {
+	request := new(model.CreateRoleRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "create role").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	role, err := c.UseCase.Create(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.RoleResponse]{
+		Success: true,
+		Message: "Role created successfully",
+		Data:    role,
+	})
+}
+
+func (c *roleController) Update(ctx *fiber.Ctx) error {
+	request := new(model.UpdateRoleRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "update role").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var err error
+	request.ID, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		c.Log.WithError(err).Warn("Failed to parse id")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	role, err := c.UseCase.Update(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.RoleResponse]{
+		Success: true,
+		Message: "Role updated successfully",
+		Data:    role,
+	})
+}
+
+func (c *roleController) Delete(ctx *fiber.Ctx) error {
+	if err := c.UseCase.Delete(ctx.Context(), ctx.Params("id")); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Role deleted successfully",
+	})
+}
+
+func (c *roleController) ListPermissions(ctx *fiber.Ctx) error {
+	permissions, err := c.UseCase.ListPermissions(ctx.Context())
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.PermissionResponse]{
+		Success: true,
+		Message: "Permissions retrieved successfully",
+		Data:    permissions,
+	})
+}
+
+func (c *roleController) AssignRole(ctx *fiber.Ctx) error {
+	request := new(model.AssignRoleRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "assign role").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var err error
+	request.ID, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		c.Log.WithError(err).Warn("Failed to parse id")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.ActorID = middleware.GetUser(ctx).ID
+
+	user, err := c.UseCase.AssignRole(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "User role updated successfully",
+		Data:    user,
+	})
+}
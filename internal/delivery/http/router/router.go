@@ -1,6 +1,8 @@
 package router
 
 import (
+	"slices"
+
 	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
 	"github.com/gofiber/fiber/v2"
 
@@ -8,15 +10,34 @@ import (
 )
 
 type RouterConfig struct {
-	App               *fiber.App
-	Middleware        *middleware.Middleware
-	AuthController    http.AuthController
-	AccountController http.AccountController
-	UserController    http.UserController
+	App                        *fiber.App
+	Middleware                 *middleware.Middleware
+	AuthController             http.AuthController
+	AccountController          http.AccountController
+	UserController             http.UserController
+	TwoFactorController        http.TwoFactorController
+	SessionController          http.SessionController
+	WellKnownController        http.WellKnownController
+	IdentityController         http.IdentityController
+	ApiKeyController           http.ApiKeyController
+	RoleController             http.RoleController
+	ImpersonationController    http.ImpersonationController
+	PasskeyController          http.PasskeyController
+	OAuthController            http.OAuthController
+	LoginEventController       http.LoginEventController
+	RegistrationCodeController http.RegistrationCodeController
+	PhoneController            http.PhoneController
+	UserImportController       http.UserImportController
+	UserExportController       http.UserExportController
+	VerifiedGroups             []string // nama group (auth, account, users, roles, admin) yang hanya bisa diakses akun dengan email terverifikasi
+	ClientGroups               []string // nama group yang boleh diakses token OAuth client, akses per route dibatasi dengan scope (nama permission)
 }
 
 func (c RouterConfig) Setup() {
+	c.App.Use(c.Middleware.RealIP)
 	c.App.Static("/uploads", "./uploads")
+	c.App.Get("/.well-known/jwks.json", c.WellKnownController.JWKS)
+	c.App.Post("/oauth/token", c.OAuthController.Token)
 
 	c.setupGuestRoute()
 	c.setupAuthRoute()
@@ -28,20 +49,117 @@ func (c RouterConfig) setupGuestRoute() {
 	auth.Post("/login", c.AuthController.Login)
 	auth.Post("/request-reset-password", c.AuthController.RequestResetPassword)
 	auth.Post("/reset-password", c.AuthController.ResetPassword)
+	auth.Post("/2fa/verify", c.AuthController.VerifyTwoFactor)
+	auth.Post("/verify-email", c.AuthController.VerifyEmail)
+	auth.Post("/resend-verification", c.AuthController.ResendVerification)
+	auth.Post("/magic-link", c.AuthController.RequestMagicLink)
+	auth.Post("/magic-link/consume", c.AuthController.ConsumeMagicLink)
+	auth.Get("/oidc/:provider", c.IdentityController.Authorize)
+	auth.Post("/oidc/:provider/callback", c.IdentityController.Callback)
+	auth.Post("/passkeys/login/options", c.PasskeyController.LoginOptions)
+	auth.Post("/passkeys/login", c.PasskeyController.Login)
+	auth.Post("/invitations/accept", c.UserController.AcceptInvitation)
+	auth.Post("/phone/login/send-code", c.PhoneController.LoginCode)
+	auth.Post("/phone/login", c.PhoneController.Login)
+
+	// link konfirmasi dan pembatalan dibuka dari email, tidak harus dalam keadaan login
+	account := c.App.Group("/api/account")
+	account.Post("/change-email/confirm", c.AccountController.ConfirmEmailChange)
+	account.Post("/change-email/cancel", c.AccountController.CancelEmailChange)
 }
 
 func (c RouterConfig) setupAuthRoute() {
-	auth := c.App.Group("/api/auth", c.Middleware.AuthMiddleware)
+	auth := c.App.Group("/api/auth", c.authHandlers("auth")...)
 	auth.Get("/_current", c.AccountController.Current)
 	auth.Post("/refresh-token", c.AuthController.RefreshToken)
-	auth.Post("/logout", c.AccountController.Logout)
-	auth.Post("/update-password", c.AccountController.UpdatePassword)
+	auth.Post("/impersonation/stop", c.ImpersonationController.Stop)
+
+	// aksi sensitif tidak bisa dilakukan saat admin sedang impersonate user, logout diganti dengan /impersonation/stop
+	auth.Use([]string{"/logout", "/update-password", "/2fa", "/sessions", "/identities", "/api-keys", "/passkeys"}, c.Middleware.RefuseImpersonation)
+
+	// endpoint keamanan akun tidak bisa diakses dengan API key
+	sessionOnly := c.Middleware.SessionOnly
+	auth.Post("/logout", sessionOnly, c.AccountController.Logout)
+	auth.Post("/update-password", sessionOnly, c.AccountController.UpdatePassword)
+	auth.Post("/2fa/setup", sessionOnly, c.TwoFactorController.Setup)
+	auth.Post("/2fa/confirm", sessionOnly, c.TwoFactorController.Confirm)
+	auth.Post("/2fa/disable", sessionOnly, c.TwoFactorController.Disable)
+	auth.Post("/2fa/recovery-codes", sessionOnly, c.TwoFactorController.RegenerateRecoveryCodes)
+	auth.Get("/sessions", sessionOnly, c.SessionController.List)
+	auth.Delete("/sessions", sessionOnly, c.SessionController.RevokeOthers)
+	auth.Delete("/sessions/:id", sessionOnly, c.SessionController.Revoke)
+	auth.Get("/identities", sessionOnly, c.IdentityController.List)
+	auth.Post("/identities/:provider", sessionOnly, c.IdentityController.LinkAuthorize)
+	auth.Post("/identities/:provider/callback", sessionOnly, c.IdentityController.LinkCallback)
+	auth.Delete("/identities/:id", sessionOnly, c.IdentityController.Unlink)
+	auth.Get("/api-keys", sessionOnly, c.ApiKeyController.List)
+	auth.Post("/api-keys", sessionOnly, c.ApiKeyController.Create)
+	auth.Delete("/api-keys/:id", sessionOnly, c.ApiKeyController.Revoke)
+	auth.Get("/passkeys", sessionOnly, c.PasskeyController.List)
+	auth.Post("/passkeys/register/options", sessionOnly, c.PasskeyController.RegistrationOptions)
+	auth.Post("/passkeys/register", sessionOnly, c.PasskeyController.Register)
+	auth.Delete("/passkeys/:id", sessionOnly, c.PasskeyController.Delete)
+
+	account := c.App.Group("/api/account", c.authHandlers("account")...)
+	account.Post("/change-email", sessionOnly, c.Middleware.RefuseImpersonation, c.AccountController.ChangeEmail)
+	account.Post("/delete", sessionOnly, c.Middleware.RefuseImpersonation, c.AccountController.Delete)
+	account.Get("/login-history", c.LoginEventController.History)
+	account.Post("/phone/send-code", sessionOnly, c.Middleware.RefuseImpersonation, c.PhoneController.SendVerificationCode)
+	account.Post("/phone/verify", sessionOnly, c.Middleware.RefuseImpersonation, c.PhoneController.Verify)
+
+	can := c.Middleware.RequirePermission
+	user := c.App.Group("/api/users", c.authHandlers("users")...)
+	user.Get("/", can("users.read"), c.UserController.List)
+	// didaftarkan sebelum /:id supaya "export" tidak dianggap id user
+	user.Get("/export", c.Middleware.RefuseClient, can("users.export"), c.UserExportController.Export)
+	user.Get("/export/:id", c.Middleware.RefuseClient, can("users.export"), c.UserExportController.FindById)
+	user.Get("/export/:id/download", c.Middleware.RefuseClient, can("users.export"), c.UserExportController.Download)
+	user.Post("/", can("users.create"), c.UserController.Create)
+	user.Post("/invite", c.Middleware.RefuseClient, can("users.invite"), c.UserController.Invite)
+	user.Post("/import", c.Middleware.RefuseClient, can("users.import"), c.UserImportController.Import)
+	user.Get("/import/:id", c.Middleware.RefuseClient, can("users.import"), c.UserImportController.FindById)
+	user.Get("/:id", can("users.read"), c.UserController.FindById)
+	user.Put("/:id", can("users.update"), c.UserController.Update)
+	user.Delete("/:id", can("users.delete"), c.UserController.Delete)
+	user.Post("/:id/unlock", can("users.unlock"), c.UserController.Unlock)
+	user.Post("/:id/invitation/resend", c.Middleware.RefuseClient, can("users.invite"), c.UserController.ResendInvitation)
+	user.Delete("/:id/invitation", c.Middleware.RefuseClient, can("users.invite"), c.UserController.RevokeInvitation)
+	user.Put("/:id/role", c.Middleware.RefuseClient, can("roles.assign"), c.RoleController.AssignRole)
+
+	role := c.App.Group("/api/roles", c.authHandlers("roles")...)
+	role.Get("/", can("roles.read"), c.RoleController.List)
+	role.Get("/permissions", can("roles.read"), c.RoleController.ListPermissions)
+	role.Get("/:id", can("roles.read"), c.RoleController.FindById)
+	role.Post("/", can("roles.manage"), c.RoleController.Create)
+	role.Put("/:id", can("roles.manage"), c.RoleController.Update)
+	role.Delete("/:id", can("roles.manage"), c.RoleController.Delete)
+
+	admin := c.App.Group("/api/admin", c.authHandlers("admin")...)
+	admin.Post("/users/:id/impersonate", sessionOnly, c.Middleware.RefuseImpersonation, can("users.impersonate"), c.ImpersonationController.Start)
+	admin.Post("/users/:id/suspend", sessionOnly, c.Middleware.RefuseImpersonation, can("users.suspend"), c.UserController.Suspend)
+	admin.Post("/users/:id/reinstate", sessionOnly, c.Middleware.RefuseImpersonation, can("users.suspend"), c.UserController.Reinstate)
+	admin.Post("/users/:id/approve", sessionOnly, c.Middleware.RefuseImpersonation, can("registration.manage"), c.UserController.ApproveRegistration)
+	admin.Post("/users/:id/reject", sessionOnly, c.Middleware.RefuseImpersonation, can("registration.manage"), c.UserController.RejectRegistration)
+	admin.Get("/registration-codes", sessionOnly, can("registration.manage"), c.RegistrationCodeController.List)
+	admin.Post("/registration-codes", sessionOnly, c.Middleware.RefuseImpersonation, can("registration.manage"), c.RegistrationCodeController.Create)
+	admin.Delete("/registration-codes/:id", sessionOnly, c.Middleware.RefuseImpersonation, can("registration.manage"), c.RegistrationCodeController.Revoke)
+	admin.Get("/login-events", can("login_events.read"), c.LoginEventController.Search)
+	admin.Get("/oauth-clients", sessionOnly, can("oauth_clients.manage"), c.OAuthController.ListClients)
+	admin.Post("/oauth-clients", sessionOnly, c.Middleware.RefuseImpersonation, can("oauth_clients.manage"), c.OAuthController.CreateClient)
+	admin.Delete("/oauth-clients/:id", sessionOnly, c.Middleware.RefuseImpersonation, can("oauth_clients.manage"), c.OAuthController.RevokeClient)
+}
 
-	user := c.App.Group("/api/users", c.Middleware.AuthMiddleware)
-	user.Get("/", c.UserController.List)
-	user.Post("/", c.UserController.Create)
-	user.Get("/:id", c.UserController.FindById)
-	user.Put("/:id", c.UserController.Update)
-	user.Delete("/:id", c.UserController.Delete)
+// authHandlers mengembalikan middleware untuk group yang membutuhkan login, API key dibatasi dengan scope "<group>:read|write".
+// Token OAuth client hanya diterima di group yang terdaftar di ClientGroups
+func (c RouterConfig) authHandlers(group string) []fiber.Handler {
+	var handlers []fiber.Handler
+	if slices.Contains(c.ClientGroups, group) {
+		handlers = append(handlers, c.Middleware.AllowClients)
+	}
+	handlers = append(handlers, c.Middleware.AuthMiddleware, c.Middleware.RequireScope(group))
+	if slices.Contains(c.VerifiedGroups, group) {
+		handlers = append(handlers, c.Middleware.EmailVerifiedMiddleware)
+	}
 
+	return handlers
 }
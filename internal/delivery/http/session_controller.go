@@ -0,0 +1,82 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type SessionController interface {
+	List(ctx *fiber.Ctx) error
+	Revoke(ctx *fiber.Ctx) error
+	RevokeOthers(ctx *fiber.Ctx) error
+}
+
+type sessionController struct {
+	UseCase usecase.SessionUseCase
+	Log     *logrus.Entry
+}
+
+func NewSessionController(useCase usecase.SessionUseCase, log *logrus.Entry) SessionController {
+	return &sessionController{UseCase: useCase, Log: log}
+}
+
+func (c *sessionController) List(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.ListSessionRequest{
+		UserID:           auth.ID,
+		CurrentSessionID: auth.SessionID,
+	}
+
+	sessions, err := c.UseCase.List(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[[]model.SessionResponse]{
+		Success: true,
+		Message: "Sessions retrieved successfully",
+		Data:    sessions,
+	})
+}
+
+func (c *sessionController) Revoke(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.RevokeSessionRequest{
+		UserID:           auth.ID,
+		CurrentSessionID: auth.SessionID,
+		ID:               ctx.Params("id"),
+	}
+
+	if err := c.UseCase.Revoke(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Session revoked successfully",
+	})
+}
+
+func (c *sessionController) RevokeOthers(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.ListSessionRequest{
+		UserID:           auth.ID,
+		CurrentSessionID: auth.SessionID,
+	}
+
+	revoked, err := c.UseCase.RevokeOthers(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: fmt.Sprintf("%d other sessions revoked successfully", revoked),
+	})
+}
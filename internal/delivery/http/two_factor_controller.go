@@ -0,0 +1,108 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type TwoFactorController interface {
+	Setup(ctx *fiber.Ctx) error
+	Confirm(ctx *fiber.Ctx) error
+	Disable(ctx *fiber.Ctx) error
+	RegenerateRecoveryCodes(ctx *fiber.Ctx) error
+}
+
+type twoFactorController struct {
+	UseCase usecase.TwoFactorUseCase
+	Log     *logrus.Entry
+}
+
+func NewTwoFactorController(useCase usecase.TwoFactorUseCase, log *logrus.Entry) TwoFactorController {
+	return &twoFactorController{UseCase: useCase, Log: log}
+}
+
+func (c *twoFactorController) Setup(ctx *fiber.Ctx) error {
+	auth := middleware.GetUser(ctx)
+	request := &model.GetUserRequest{
+		ID: auth.ID,
+	}
+
+	setup, err := c.UseCase.Setup(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.TwoFactorSetupResponse]{
+		Success: true,
+		Message: "Scan the provisioning uri with your authenticator app, then confirm with the generated code",
+		Data:    setup,
+	})
+}
+
+func (c *twoFactorController) Confirm(ctx *fiber.Ctx) error {
+	request := new(model.ConfirmTwoFactorRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "confirm two factor").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	auth := middleware.GetUser(ctx)
+	request.ID = auth.ID
+
+	codes, err := c.UseCase.Confirm(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.RecoveryCodesResponse]{
+		Success: true,
+		Message: "Two-factor authentication enabled successfully. Store the recovery codes in a safe place",
+		Data:    codes,
+	})
+}
+
+func (c *twoFactorController) Disable(ctx *fiber.Ctx) error {
+	request := new(model.DisableTwoFactorRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "disable two factor").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	auth := middleware.GetUser(ctx)
+	request.ID = auth.ID
+
+	if err := c.UseCase.Disable(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Two-factor authentication disabled successfully",
+	})
+}
+
+func (c *twoFactorController) RegenerateRecoveryCodes(ctx *fiber.Ctx) error {
+	request := new(model.RegenerateRecoveryCodesRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "regenerate recovery codes").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	auth := middleware.GetUser(ctx)
+	request.ID = auth.ID
+
+	codes, err := c.UseCase.RegenerateRecoveryCodes(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.RecoveryCodesResponse]{
+		Success: true,
+		Message: "Recovery codes regenerated successfully",
+		Data:    codes,
+	})
+}
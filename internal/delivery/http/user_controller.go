@@ -1,6 +1,7 @@
 package http
 
 import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
 	"github.com/alfianyulianto/pds-service/internal/model"
 	"github.com/alfianyulianto/pds-service/internal/usecase"
 	"github.com/alfianyulianto/pds-service/pkg/response"
@@ -15,6 +16,15 @@ type UserController interface {
 	List(ctx *fiber.Ctx) error
 	FindById(ctx *fiber.Ctx) error
 	Delete(ctx *fiber.Ctx) error
+	Unlock(ctx *fiber.Ctx) error
+	Suspend(ctx *fiber.Ctx) error
+	Reinstate(ctx *fiber.Ctx) error
+	Invite(ctx *fiber.Ctx) error
+	ResendInvitation(ctx *fiber.Ctx) error
+	RevokeInvitation(ctx *fiber.Ctx) error
+	AcceptInvitation(ctx *fiber.Ctx) error
+	ApproveRegistration(ctx *fiber.Ctx) error
+	RejectRegistration(ctx *fiber.Ctx) error
 }
 
 type userController struct {
@@ -89,6 +99,8 @@ func (c *userController) List(ctx *fiber.Ctx) error {
 	request.Search = ctx.Query("search")
 	request.IsActive = ctx.Query("is_active")
 	request.Role = ctx.Query("role")
+	request.Invitation = ctx.Query("invitation")
+	request.Approval = ctx.Query("approval")
 	request.OrderBy = ctx.Query("order_by", "created_at")
 	request.OrderDir = ctx.Query("order_dir", "desc")
 	request.Page = ctx.QueryInt("page", 1)
@@ -134,3 +146,197 @@ func (c *userController) Delete(ctx *fiber.Ctx) error {
 		Data:    nil,
 	})
 }
+
+func (c *userController) Unlock(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+	if err := c.UseCase.Unlock(ctx.Context(), id); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "User account unlocked successfully",
+	})
+}
+
+func (c *userController) Suspend(ctx *fiber.Ctx) error {
+	request := new(model.SuspendUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "suspend user").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var err error
+	request.ID, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		c.Log.WithError(err).Warn("Failed to parse id")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.ActorID = middleware.GetUser(ctx).ID
+
+	user, err := c.UseCase.Suspend(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "User suspended successfully",
+		Data:    user,
+	})
+}
+
+func (c *userController) Reinstate(ctx *fiber.Ctx) error {
+	request := new(model.ReinstateUserRequest)
+
+	var err error
+	request.ID, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		c.Log.WithError(err).Warn("Failed to parse id")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.ActorID = middleware.GetUser(ctx).ID
+
+	user, err := c.UseCase.Reinstate(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "User reinstated successfully",
+		Data:    user,
+	})
+}
+
+func (c *userController) Invite(ctx *fiber.Ctx) error {
+	request := new(model.InviteUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "invite user").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	request.InvitedBy = middleware.GetUser(ctx).ID
+
+	user, err := c.UseCase.Invite(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "User invited successfully",
+		Data:    user,
+	})
+}
+
+func (c *userController) ResendInvitation(ctx *fiber.Ctx) error {
+	request := new(model.InvitationRequest)
+
+	var err error
+	request.ID, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		c.Log.WithError(err).Warn("Failed to parse id")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.ActorID = middleware.GetUser(ctx).ID
+
+	user, err := c.UseCase.ResendInvitation(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "Invitation resent successfully",
+		Data:    user,
+	})
+}
+
+func (c *userController) RevokeInvitation(ctx *fiber.Ctx) error {
+	request := new(model.InvitationRequest)
+
+	var err error
+	request.ID, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		c.Log.WithError(err).Warn("Failed to parse id")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.ActorID = middleware.GetUser(ctx).ID
+
+	if err = c.UseCase.RevokeInvitation(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Invitation revoked successfully",
+	})
+}
+
+func (c *userController) AcceptInvitation(ctx *fiber.Ctx) error {
+	request := new(model.AcceptInvitationRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "accept invitation").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	user, err := c.UseCase.AcceptInvitation(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "Invitation accepted successfully, you can now log in",
+		Data:    user,
+	})
+}
+
+func (c *userController) ApproveRegistration(ctx *fiber.Ctx) error {
+	request := new(model.ApproveRegistrationRequest)
+
+	var err error
+	request.ID, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		c.Log.WithError(err).Warn("Failed to parse id")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.ActorID = middleware.GetUser(ctx).ID
+
+	user, err := c.UseCase.ApproveRegistration(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserResponse]{
+		Success: true,
+		Message: "Registration approved successfully",
+		Data:    user,
+	})
+}
+
+func (c *userController) RejectRegistration(ctx *fiber.Ctx) error {
+	request := new(model.RejectRegistrationRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "reject registration").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var err error
+	request.ID, err = uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		c.Log.WithError(err).Warn("Failed to parse id")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	request.ActorID = middleware.GetUser(ctx).ID
+
+	if err = c.UseCase.RejectRegistration(ctx.Context(), request); err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[any]{
+		Success: true,
+		Message: "Registration rejected successfully",
+	})
+}
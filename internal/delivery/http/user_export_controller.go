@@ -0,0 +1,99 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"strings"
+
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/alfianyulianto/pds-service/pkg/spreadsheet"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type UserExportController interface {
+	Export(ctx *fiber.Ctx) error
+	FindById(ctx *fiber.Ctx) error
+	Download(ctx *fiber.Ctx) error
+}
+
+type userExportController struct {
+	UseCase usecase.UserExportUseCase
+	Log     *logrus.Entry
+}
+
+func NewUserExportController(useCase usecase.UserExportUseCase, log *logrus.Entry) UserExportController {
+	return &userExportController{UseCase: useCase, Log: log}
+}
+
+func (c *userExportController) Export(ctx *fiber.Ctx) error {
+	request := new(model.ExportUserRequest)
+
+	// nilai query dari fiber menunjuk ke buffer request yang dipakai ulang setelah handler selesai, sedangkan request
+	// masih dibaca oleh export di background dan stream writer sehingga harus disalin
+	request.ExportedBy = middleware.GetUser(ctx).ID
+	request.Format = strings.Clone(ctx.Query("format", "csv"))
+	request.Columns = strings.Clone(ctx.Query("columns"))
+	request.Background = ctx.QueryBool("background")
+	request.Search = strings.Clone(ctx.Query("search"))
+	request.IsActive = strings.Clone(ctx.Query("is_active"))
+	request.Role = strings.Clone(ctx.Query("role"))
+	request.Invitation = strings.Clone(ctx.Query("invitation"))
+	request.Approval = strings.Clone(ctx.Query("approval"))
+
+	job, err := c.UseCase.Export(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	if job != nil {
+		return ctx.Status(fiber.StatusAccepted).JSON(response.Response[*model.UserExportResponse]{
+			Success: true,
+			Message: "User export is being processed",
+			Data:    job,
+		})
+	}
+
+	ctx.Attachment(usecase.ExportFileName(request.Format))
+	ctx.Set(fiber.HeaderContentType, spreadsheet.ContentTypes[request.Format])
+
+	// stream writer dijalankan setelah handler selesai, fiber ctx tidak boleh dipakai di dalamnya. Status sudah terkirim
+	// sehingga error di tengah stream hanya bisa dicatat di log
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := c.UseCase.Stream(context.Background(), request, w); err != nil {
+			c.Log.WithField("action", "export user").WithError(err).Error("Failed to stream user export")
+		}
+	})
+
+	return nil
+}
+
+func (c *userExportController) FindById(ctx *fiber.Ctx) error {
+	request := &model.UserExportRequest{ID: ctx.Params("id"), ActorID: middleware.GetUser(ctx).ID}
+
+	job, err := c.UseCase.FindById(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserExportResponse]{
+		Success: true,
+		Message: "User export retrieved successfully",
+		Data:    job,
+	})
+}
+
+func (c *userExportController) Download(ctx *fiber.Ctx) error {
+	request := &model.UserExportRequest{ID: ctx.Params("id"), ActorID: middleware.GetUser(ctx).ID}
+
+	path, job, err := c.UseCase.Download(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, spreadsheet.ContentTypes[job.Format])
+	return ctx.Download(path, job.FileName)
+}
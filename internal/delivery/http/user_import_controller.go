@@ -0,0 +1,80 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/delivery/http/middleware"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type UserImportController interface {
+	Import(ctx *fiber.Ctx) error
+	FindById(ctx *fiber.Ctx) error
+}
+
+type userImportController struct {
+	UseCase usecase.UserImportUseCase
+	Log     *logrus.Entry
+}
+
+func NewUserImportController(useCase usecase.UserImportUseCase, log *logrus.Entry) UserImportController {
+	return &userImportController{UseCase: useCase, Log: log}
+}
+
+func (c *userImportController) Import(ctx *fiber.Ctx) error {
+	request := new(model.ImportUserRequest)
+	if err := ctx.BodyParser(request); err != nil {
+		c.Log.WithField("action", "import user").WithError(err).Error("Failed to parse request body")
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var err error
+	request.File, err = ctx.FormFile("file")
+	if err != nil {
+		c.Log.WithField("action", "import user").WithError(err).Warn("Failed to parse import file")
+	}
+
+	request.ImportedBy = middleware.GetUser(ctx).ID
+
+	job, err := c.UseCase.Import(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	// file besar masih diproses di background, status dipantau lewat GET /api/users/import/:id
+	if job.FinishedAt == nil {
+		return ctx.Status(fiber.StatusAccepted).JSON(response.Response[*model.UserImportResponse]{
+			Success: true,
+			Message: "User import is being processed",
+			Data:    job,
+		})
+	}
+
+	message := "User import completed"
+	if job.DryRun {
+		message = "User import validated, no user has been created"
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserImportResponse]{
+		Success: true,
+		Message: message,
+		Data:    job,
+	})
+}
+
+func (c *userImportController) FindById(ctx *fiber.Ctx) error {
+	request := &model.GetUserImportRequest{ID: ctx.Params("id")}
+
+	job, err := c.UseCase.FindById(ctx.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(200).JSON(response.Response[*model.UserImportResponse]{
+		Success: true,
+		Message: "User import retrieved successfully",
+		Data:    job,
+	})
+}
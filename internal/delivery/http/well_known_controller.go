@@ -0,0 +1,26 @@
+package http
+
+import (
+	"github.com/alfianyulianto/pds-service/pkg/auth"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type WellKnownController interface {
+	JWKS(ctx *fiber.Ctx) error
+}
+
+type wellKnownController struct {
+	Log        *logrus.Entry
+	JwtService *auth.JWTService
+}
+
+func NewWellKnownController(log *logrus.Entry, jwtService *auth.JWTService) WellKnownController {
+	return &wellKnownController{Log: log, JwtService: jwtService}
+}
+
+// JWKS mengikuti format RFC 7517 (bukan response.Response) supaya bisa langsung dibaca library JWT di service lain
+func (c *wellKnownController) JWKS(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return ctx.Status(200).JSON(c.JwtService.Keys.JWKS())
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// AccountDeletionWorker menjalankan purge akun yang masa tenggang penghapusannya sudah lewat secara berkala
+type AccountDeletionWorker struct {
+	UseCase  usecase.AccountUseCase
+	Log      *logrus.Entry
+	Interval time.Duration
+}
+
+func NewAccountDeletionWorker(useCase usecase.AccountUseCase, log *logrus.Entry, interval time.Duration) *AccountDeletionWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &AccountDeletionWorker{UseCase: useCase, Log: log, Interval: interval}
+}
+
+func (w *AccountDeletionWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *AccountDeletionWorker) run(ctx context.Context) {
+	purged, err := w.UseCase.PurgeDeletedAccounts(ctx)
+	if err != nil {
+		w.Log.WithField("action", "account deletion worker").WithError(err).Error("Failed to purge deleted accounts")
+		return
+	}
+
+	if purged > 0 {
+		w.Log.WithField("action", "account deletion worker").WithField("purged", purged).Info("Deleted accounts purged")
+	}
+}
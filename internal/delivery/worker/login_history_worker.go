@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// LoginHistoryWorker menghapus login history yang sudah melewati masa retensi secara berkala
+type LoginHistoryWorker struct {
+	UseCase  usecase.LoginEventUseCase
+	Log      *logrus.Entry
+	Interval time.Duration
+}
+
+func NewLoginHistoryWorker(useCase usecase.LoginEventUseCase, log *logrus.Entry, interval time.Duration) *LoginHistoryWorker {
+	if interval <= 0 {
+		interval = 24 * time.Hour
+	}
+
+	return &LoginHistoryWorker{UseCase: useCase, Log: log, Interval: interval}
+}
+
+func (w *LoginHistoryWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *LoginHistoryWorker) run(ctx context.Context) {
+	purged, err := w.UseCase.PurgeExpired(ctx)
+	if err != nil {
+		w.Log.WithField("action", "login history worker").WithError(err).Error("Failed to purge login history")
+		return
+	}
+
+	if purged > 0 {
+		w.Log.WithField("action", "login history worker").WithField("purged", purged).Info("Expired login history purged")
+	}
+}
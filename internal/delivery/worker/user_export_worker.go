@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+// UserExportWorker menghapus file export user yang sudah melewati masa simpan secara berkala
+type UserExportWorker struct {
+	UseCase  usecase.UserExportUseCase
+	Log      *logrus.Entry
+	Interval time.Duration
+}
+
+func NewUserExportWorker(useCase usecase.UserExportUseCase, log *logrus.Entry, interval time.Duration) *UserExportWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &UserExportWorker{UseCase: useCase, Log: log, Interval: interval}
+}
+
+func (w *UserExportWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *UserExportWorker) run(ctx context.Context) {
+	purged, err := w.UseCase.PurgeExpired(ctx)
+	if err != nil {
+		w.Log.WithField("action", "user export worker").WithError(err).Error("Failed to purge user exports")
+		return
+	}
+
+	if purged > 0 {
+		w.Log.WithField("action", "user export worker").WithField("purged", purged).Info("Expired user exports purged")
+	}
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ApiKey struct {
+	ID         uuid.UUID  `gorm:"column:id;primaryKey"`
+	UserID     uuid.UUID  `gorm:"column:user_id;not null"`
+	Name       string     `gorm:"column:name;not null"`
+	Prefix     string     `gorm:"column:prefix;not null"`
+	KeyHash    string     `gorm:"column:key_hash;not null"`
+	Scopes     string     `gorm:"column:scopes;not null"` // dipisahkan koma
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	LastUsedIP *string    `gorm:"column:last_used_ip"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (a *ApiKey) TableName() string {
+	return "api_keys"
+}
+
+func (a *ApiKey) BeforeCreate(tx *gorm.DB) error {
+	a.ID = uuid.New()
+	return nil
+}
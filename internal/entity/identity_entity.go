@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Identity struct {
+	ID        uuid.UUID `gorm:"column:id;primaryKey"`
+	UserID    uuid.UUID `gorm:"column:user_id;not null"`
+	Provider  string    `gorm:"column:provider;not null"`
+	Subject   string    `gorm:"column:subject;not null"`
+	Email     *string   `gorm:"column:email"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (i *Identity) TableName() string {
+	return "identities"
+}
+
+func (i *Identity) BeforeCreate(tx *gorm.DB) error {
+	i.ID = uuid.New()
+	return nil
+}
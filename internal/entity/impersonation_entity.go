@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Impersonation struct {
+	ID             uuid.UUID  `gorm:"column:id;primaryKey"`
+	ImpersonatorID uuid.UUID  `gorm:"column:impersonator_id;not null"`
+	UserID         uuid.UUID  `gorm:"column:user_id;not null"`
+	Reason         *string    `gorm:"column:reason"`
+	IPAddress      *string    `gorm:"column:ip_address"`
+	UserAgent      *string    `gorm:"column:user_agent"`
+	ExpiresAt      time.Time  `gorm:"column:expires_at;not null"`
+	EndedAt        *time.Time `gorm:"column:ended_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (i *Impersonation) TableName() string {
+	return "impersonations"
+}
+
+func (i *Impersonation) BeforeCreate(tx *gorm.DB) error {
+	i.ID = uuid.New()
+	return nil
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+// LoginEvent mencatat setiap percobaan login, UserID kosong jika email yang dipakai tidak terdaftar
+type LoginEvent struct {
+	ID             uuid.UUID  `gorm:"column:id;primaryKey"`
+	UserID         *uuid.UUID `gorm:"column:user_id"`
+	Email          *string    `gorm:"column:email"`
+	Method         string     `gorm:"column:method;not null"`
+	Success        bool       `gorm:"column:success;not null"`
+	FailureReason  *string    `gorm:"column:failure_reason"`
+	IPAddress      string     `gorm:"column:ip_address;not null"`
+	UserAgent      *string    `gorm:"column:user_agent"`
+	Browser        *string    `gorm:"column:browser"`
+	BrowserVersion *string    `gorm:"column:browser_version"`
+	OS             *string    `gorm:"column:os"`
+	OSVersion      *string    `gorm:"column:os_version"`
+	Device         string     `gorm:"column:device;not null"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (l *LoginEvent) TableName() string {
+	return "login_events"
+}
+
+func (l *LoginEvent) BeforeCreate(tx *gorm.DB) error {
+	l.ID = uuid.New()
+	return nil
+}
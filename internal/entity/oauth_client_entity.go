@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type OAuthClient struct {
+	ID         uuid.UUID  `gorm:"column:id;primaryKey"`
+	ClientID   string     `gorm:"column:client_id;not null"`
+	SecretHash string     `gorm:"column:secret_hash;not null"`
+	Name       string     `gorm:"column:name;not null"`
+	Scopes     string     `gorm:"column:scopes;not null"` // nama permission, dipisahkan koma
+	CreatedBy  *uuid.UUID `gorm:"column:created_by"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (c *OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+func (c *OAuthClient) BeforeCreate(tx *gorm.DB) error {
+	c.ID = uuid.New()
+	return nil
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type PasswordHistory struct {
+	ID        uuid.UUID `gorm:"column:id;primaryKey"`
+	UserID    uuid.UUID `gorm:"column:user_id;not null"`
+	Password  string    `gorm:"column:password;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (p *PasswordHistory) TableName() string {
+	return "password_histories"
+}
+
+func (p *PasswordHistory) BeforeCreate(tx *gorm.DB) error {
+	p.ID = uuid.New()
+	return nil
+}
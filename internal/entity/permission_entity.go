@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Permission struct {
+	ID          uuid.UUID `gorm:"column:id;primaryKey"`
+	Name        string    `gorm:"column:name;not null"`
+	Description *string   `gorm:"column:description"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (p *Permission) TableName() string {
+	return "permissions"
+}
+
+func (p *Permission) BeforeCreate(tx *gorm.DB) error {
+	p.ID = uuid.New()
+	return nil
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"column:id;primaryKey"`
+	UserID    uuid.UUID  `gorm:"column:user_id;not null"`
+	CodeHash  string     `gorm:"column:code_hash;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (r *RecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+func (r *RecoveryCode) BeforeCreate(tx *gorm.DB) error {
+	r.ID = uuid.New()
+	return nil
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type RegistrationCode struct {
+	ID          uuid.UUID  `gorm:"column:id;primaryKey"`
+	Prefix      string     `gorm:"column:prefix;not null"`
+	CodeHash    string     `gorm:"column:code_hash;not null"`
+	Description *string    `gorm:"column:description"`
+	MaxUses     *int       `gorm:"column:max_uses"` // kosong = bisa dipakai berkali-kali tanpa batas sampai kadaluarsa
+	Uses        int        `gorm:"column:uses;not null"`
+	ExpiresAt   time.Time  `gorm:"column:expires_at;not null"`
+	CreatedBy   *uuid.UUID `gorm:"column:created_by"`
+	RevokedAt   *time.Time `gorm:"column:revoked_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (c *RegistrationCode) TableName() string {
+	return "registration_codes"
+}
+
+// Usable bernilai true jika kode belum di revoke, belum kadaluarsa dan kuota pemakaiannya belum habis
+func (c *RegistrationCode) Usable() bool {
+	return c.RevokedAt == nil && time.Now().Before(c.ExpiresAt) && (c.MaxUses == nil || c.Uses < *c.MaxUses)
+}
+
+func (c *RegistrationCode) BeforeCreate(tx *gorm.DB) error {
+	c.ID = uuid.New()
+	return nil
+}
@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type Role struct {
+	ID          uuid.UUID    `gorm:"column:id;primaryKey"`
+	Name        string       `gorm:"column:name;not null"`
+	Description *string      `gorm:"column:description"`
+	Permissions []Permission `gorm:"many2many:role_permissions;joinForeignKey:role_id;joinReferences:permission_id"`
+	CreatedAt   time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time    `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (r *Role) TableName() string {
+	return "roles"
+}
+
+func (r *Role) BeforeCreate(tx *gorm.DB) error {
+	r.ID = uuid.New()
+	return nil
+}
@@ -7,25 +7,71 @@ import (
 )
 
 type User struct {
-	ID              uuid.UUID      `gorm:"column:id;primaryKey"`
-	Name            string         `gorm:"column:name;not null"`
-	Email           string         `gorm:"column:email;not null"`
-	EmailVerifiedAt *time.Time     `gorm:"column:email_verified_at"`
-	Password        string         `gorm:"column:password;not null"`
-	Phone           *string        `gorm:"column:phone"`
-	Avatar          *string        `gorm:"column:avatar"`
-	IsActive        bool           `gorm:"column:is_active"`
-	LastLoginAt     *time.Time     `gorm:"column:last_login_at"`
-	Role            string         `gorm:"column:role;default:User"`
-	CreatedAt       time.Time      `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt       time.Time      `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
-	DeletedAt       gorm.DeletedAt `gorm:"column:deleted_at"`
+	ID                   uuid.UUID      `gorm:"column:id;primaryKey"`
+	Name                 string         `gorm:"column:name;not null"`
+	Email                string         `gorm:"column:email;not null"`
+	EmailVerifiedAt      *time.Time     `gorm:"column:email_verified_at"`
+	Password             string         `gorm:"column:password;not null"`
+	PasswordChangedAt    *time.Time     `gorm:"column:password_changed_at"`
+	TwoFactorSecret      *string        `gorm:"column:two_factor_secret"`
+	TwoFactorEnabledAt   *time.Time     `gorm:"column:two_factor_enabled_at"`
+	Phone                *string        `gorm:"column:phone"` // format E.164
+	PhoneVerifiedAt      *time.Time     `gorm:"column:phone_verified_at"`
+	Avatar               *string        `gorm:"column:avatar"`
+	IsActive             bool           `gorm:"column:is_active"`
+	SuspendedUntil       *time.Time     `gorm:"column:suspended_until"` // kosong dan SuspendedBy terisi = suspend sampai di reinstate
+	SuspensionReason     *string        `gorm:"column:suspension_reason"`
+	SuspendedBy          *uuid.UUID     `gorm:"column:suspended_by"`
+	DeletionScheduledAt  *time.Time     `gorm:"column:deletion_scheduled_at"` // akun dihapus permanen setelah waktu ini
+	InvitedBy            *uuid.UUID     `gorm:"column:invited_by"`
+	InvitedAt            *time.Time     `gorm:"column:invited_at"`
+	InvitationTokenHash  *string        `gorm:"column:invitation_token_hash"` // sha256 token undangan, kosong setelah undangan diterima
+	InvitationExpiresAt  *time.Time     `gorm:"column:invitation_expires_at"`
+	InvitationAcceptedAt *time.Time     `gorm:"column:invitation_accepted_at"`
+	ApprovalStatus       *string        `gorm:"column:approval_status"` // pending atau approved untuk akun yang mendaftar saat registration.mode = approval
+	ApprovedBy           *uuid.UUID     `gorm:"column:approved_by"`
+	ApprovedAt           *time.Time     `gorm:"column:approved_at"`
+	RegistrationCodeID   *uuid.UUID     `gorm:"column:registration_code_id"`
+	LastLoginAt          *time.Time     `gorm:"column:last_login_at"`
+	Role                 string         `gorm:"column:role;default:User"`
+	CreatedAt            time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt            time.Time      `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+	DeletedAt            gorm.DeletedAt `gorm:"column:deleted_at"`
 }
 
 func (u *User) TableName() string {
 	return "users"
 }
 
+// Suspended bernilai true jika user sedang di suspend, suspend dengan SuspendedUntil yang sudah lewat dianggap selesai
+func (u *User) Suspended() bool {
+	return u.SuspendedBy != nil && (u.SuspendedUntil == nil || time.Now().Before(*u.SuspendedUntil))
+}
+
+// InvitationPending bernilai true jika user dibuat lewat undangan yang belum diterima, user ini belum punya password
+func (u *User) InvitationPending() bool {
+	return u.InvitedAt != nil && u.InvitationAcceptedAt == nil
+}
+
+// ApprovalPending bernilai true jika pendaftaran user masih menunggu persetujuan admin
+func (u *User) ApprovalPending() bool {
+	return u.ApprovalStatus != nil && *u.ApprovalStatus == "pending"
+}
+
+// InvitationStatus mengembalikan pending, expired atau accepted untuk user yang diundang, string kosong jika user tidak diundang
+func (u *User) InvitationStatus() string {
+	switch {
+	case u.InvitedAt == nil:
+		return ""
+	case u.InvitationAcceptedAt != nil:
+		return "accepted"
+	case u.InvitationExpiresAt != nil && !time.Now().Before(*u.InvitationExpiresAt):
+		return "expired"
+	}
+
+	return "pending"
+}
+
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	u.ID = uuid.New()
 	return nil
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+type WebAuthnCredential struct {
+	ID           uuid.UUID  `gorm:"column:id;primaryKey"`
+	UserID       uuid.UUID  `gorm:"column:user_id;not null"`
+	CredentialID string     `gorm:"column:credential_id;not null"` // base64url
+	PublicKey    []byte     `gorm:"column:public_key;not null"`    // COSE_Key
+	Algorithm    int64      `gorm:"column:algorithm;not null"`
+	SignCount    uint32     `gorm:"column:sign_count;not null"`
+	Transports   *string    `gorm:"column:transports"` // dipisahkan koma
+	AAGUID       *string    `gorm:"column:aaguid"`
+	Name         string     `gorm:"column:name;not null"`
+	LastUsedAt   *time.Time `gorm:"column:last_used_at"`
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;autoCreateTime;autoUpdateTime"`
+}
+
+func (w *WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
+
+func (w *WebAuthnCredential) BeforeCreate(tx *gorm.DB) error {
+	w.ID = uuid.New()
+	return nil
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type ApiKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP *string    `json:"last_used_ip"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateApiKeyResponse berisi key asli yang hanya ditampilkan satu kali saat dibuat
+type CreateApiKeyResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
+
+type CreateApiKeyRequest struct {
+	UserID        uuid.UUID `validate:"required"`
+	Name          string    `json:"name" form:"name" validate:"required,max=100"`
+	Scopes        []string  `json:"scopes" form:"scopes" validate:"required,min=1,dive,oneof=* auth:read auth:write account:read account:write users:read users:write roles:read roles:write admin:read admin:write"` // * = semua scope
+	ExpiresInDays int       `json:"expires_in_days" form:"expires_in_days" validate:"omitempty,min=1,max=3650"`
+}
+
+type ListApiKeyRequest struct {
+	UserID uuid.UUID `validate:"required"`
+}
+
+type RevokeApiKeyRequest struct {
+	UserID uuid.UUID `validate:"required"`
+	ID     string    `validate:"required,uuid"`
+}
@@ -3,18 +3,27 @@ package model
 import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"time"
 )
 
 type AuthResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken           string     `json:"access_token,omitempty"`
+	RefreshToken          string     `json:"refresh_token,omitempty"`
+	TokenType             string     `json:"token_type,omitempty"`
+	ExpiresIn             int        `json:"expires_in,omitempty"` // detik
+	AccessTokenExpiresAt  *time.Time `json:"access_token_expires_at,omitempty"`
+	RefreshExpiresIn      int        `json:"refresh_expires_in,omitempty"` // detik
+	RefreshTokenExpiresAt *time.Time `json:"refresh_token_expires_at,omitempty"`
+	TwoFactorRequired     bool       `json:"two_factor_required,omitempty"`
+	ChallengeToken        string     `json:"challenge_token,omitempty"`
 }
 
 type RegisterUserRequest struct {
 	Name            string `json:"name" form:"name" validate:"required,max=255"`
 	Email           string `json:"email" form:"email" validate:"required,email,max=100,unique=users.email"` // unique=table.column
-	Password        string `json:"password" form:"password" validate:"required,min=8,max=100"`
+	Password        string `json:"password" form:"password" validate:"required" password:"policy"`
 	ConfirmPassword string `json:"confirm_password" form:"confirm_password" validate:"required,eqfield=Password"`
+	InviteCode      string `json:"invite_code" form:"invite_code" validate:"omitempty,max=64"` // wajib jika registration.mode = invite_code
 }
 
 type LoginUserRequest struct {
@@ -27,17 +36,44 @@ type VerifyUserRequest struct {
 }
 
 type UserClaimToken struct {
-	ID   uuid.UUID
-	Role string
-	Type string
+	ID           uuid.UUID `json:"ID,omitzero"` // kosong untuk token OAuth client (Type "client")
+	Role         string
+	Type         string
+	SessionID    string
+	ClientID     string     `json:"client_id,omitempty"`    // hanya diisi untuk token OAuth client
+	Scopes       []string   `json:"Scopes,omitempty"`       // diisi untuk API key (Type "api_key") dan OAuth client (Type "client")
+	Impersonator *uuid.UUID `json:"impersonator,omitempty"` // admin yang sedang masuk sebagai user ini
+	IssuedAtMs   int64      `json:"iat_ms,omitempty"`       // waktu terbit dalam milidetik, iat hanya dalam detik
 	jwt.RegisteredClaims
 }
 
 type UpdatePasswordRequest struct {
 	ID                 uuid.UUID `validate:"required,exists=users.id"`
 	OldPassword        string    `json:"old_password" form:"old_password" validate:"required,match_password=users"`
-	NewPassword        string    `json:"new_password" form:"new_password" validate:"required,min=8,max=100,nefield"`
+	NewPassword        string    `json:"new_password" form:"new_password" validate:"required,nefield=OldPassword" password:"policy"`
 	ConfirmNewPassword string    `json:"confirm_new_password" form:"confirm_new_password" validate:"required,eqfield=NewPassword"`
+	KeepCurrentSession bool      `json:"keep_current_session" form:"keep_current_session"` // true = session yang dipakai saat ini tidak ikut logout
+	SessionID          string
+}
+
+type ChangeEmailRequest struct {
+	ID       uuid.UUID `validate:"required,exists=users.id"`
+	NewEmail string    `json:"new_email" form:"new_email" validate:"required,email,max=100,unique=users.email"`
+	Password string    `json:"password" form:"password" validate:"required,match_password=users"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token    string `json:"token" form:"token" validate:"required"`
+	NewEmail string `validate:"required,email,max=100,unique=users.email"` // diisi dari token, unique dicek ulang saat email diganti
+}
+
+type CancelEmailChangeRequest struct {
+	Token string `json:"token" form:"token" validate:"required"`
+}
+
+type DeleteAccountRequest struct {
+	ID       uuid.UUID `validate:"required,exists=users.id"`
+	Password string    `json:"password" form:"password" validate:"required,match_password=users"`
 }
 
 type RequestPasswordResetRequest struct {
@@ -45,7 +81,31 @@ type RequestPasswordResetRequest struct {
 }
 
 type ResetPasswordRequest struct {
-	Token              string `json:"token" form:"token" validate:"required"`
-	NewPassword        string `json:"new_password" form:"new_password" validate:"required,min=8,max=100"`
-	ConfirmNewPassword string `json:"confirm_new_password" form:"confirm_new_password" validate:"required,eqfield=NewPassword"`
+	ID                 uuid.UUID // diisi dari token, dipakai password_policy untuk mengecek riwayat password
+	Token              string    `json:"token" form:"token" validate:"required"`
+	NewPassword        string    `json:"new_password" form:"new_password" validate:"required" password:"policy"`
+	ConfirmNewPassword string    `json:"confirm_new_password" form:"confirm_new_password" validate:"required,eqfield=NewPassword"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" form:"token" validate:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" form:"email" validate:"required,email"`
+}
+
+type MagicLinkRequest struct {
+	Email      string `json:"email" form:"email" validate:"required,email"`
+	BindDevice bool   `json:"bind_device" form:"bind_device"`
+}
+
+// MagicLinkResponse berisi device token jika link diikat ke perangkat, token ini wajib dikirim lagi saat link dipakai
+type MagicLinkResponse struct {
+	DeviceToken string `json:"device_token,omitempty"`
+}
+
+type ConsumeMagicLinkRequest struct {
+	Token       string `json:"token" form:"token" validate:"required"`
+	DeviceToken string `json:"device_token" form:"device_token"`
 }
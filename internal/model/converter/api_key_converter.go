@@ -0,0 +1,22 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+)
+
+func ApiKeyToResponse(apiKey *entity.ApiKey) *model.ApiKeyResponse {
+	return &model.ApiKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     strings.Split(apiKey.Scopes, ","),
+		LastUsedAt: apiKey.LastUsedAt,
+		LastUsedIP: apiKey.LastUsedIP,
+		ExpiresAt:  apiKey.ExpiresAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
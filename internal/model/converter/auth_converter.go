@@ -10,5 +10,6 @@ func RegisterRequestToUser(request *model.RegisterUserRequest) *entity.User {
 		Name:     request.Name,
 		Email:    request.Email,
 		Password: request.Password,
+		IsActive: true,
 	}
 }
@@ -0,0 +1,16 @@
+package converter
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+)
+
+func IdentityToResponse(identity *entity.Identity) *model.IdentityResponse {
+	return &model.IdentityResponse{
+		ID:        identity.ID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
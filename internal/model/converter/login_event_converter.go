@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+)
+
+func LoginEventToResponse(event *entity.LoginEvent) *model.LoginEventResponse {
+	return &model.LoginEventResponse{
+		ID:             event.ID,
+		UserID:         event.UserID,
+		Email:          event.Email,
+		Method:         event.Method,
+		Success:        event.Success,
+		FailureReason:  event.FailureReason,
+		IPAddress:      event.IPAddress,
+		UserAgent:      event.UserAgent,
+		Browser:        event.Browser,
+		BrowserVersion: event.BrowserVersion,
+		OS:             event.OS,
+		OSVersion:      event.OSVersion,
+		Device:         event.Device,
+		CreatedAt:      event.CreatedAt,
+	}
+}
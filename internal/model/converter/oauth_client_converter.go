@@ -0,0 +1,21 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+)
+
+func OAuthClientToResponse(client *entity.OAuthClient) *model.OAuthClientResponse {
+	return &model.OAuthClientResponse{
+		ID:         client.ID,
+		ClientID:   client.ClientID,
+		Name:       client.Name,
+		Scopes:     strings.Split(client.Scopes, ","),
+		CreatedBy:  client.CreatedBy,
+		LastUsedAt: client.LastUsedAt,
+		RevokedAt:  client.RevokedAt,
+		CreatedAt:  client.CreatedAt,
+	}
+}
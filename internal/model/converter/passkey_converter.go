@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+)
+
+func PasskeyToResponse(credential *entity.WebAuthnCredential) *model.PasskeyResponse {
+	transports := []string{}
+	if credential.Transports != nil && *credential.Transports != "" {
+		transports = strings.Split(*credential.Transports, ",")
+	}
+
+	return &model.PasskeyResponse{
+		ID:         credential.ID,
+		Name:       credential.Name,
+		Transports: transports,
+		AAGUID:     credential.AAGUID,
+		SignCount:  credential.SignCount,
+		LastUsedAt: credential.LastUsedAt,
+		CreatedAt:  credential.CreatedAt,
+	}
+}
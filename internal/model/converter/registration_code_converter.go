@@ -0,0 +1,21 @@
+package converter
+
+import (
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+)
+
+func RegistrationCodeToResponse(code *entity.RegistrationCode) *model.RegistrationCodeResponse {
+	return &model.RegistrationCodeResponse{
+		ID:          code.ID,
+		Prefix:      code.Prefix,
+		Description: code.Description,
+		MaxUses:     code.MaxUses,
+		Uses:        code.Uses,
+		ExpiresAt:   code.ExpiresAt,
+		CreatedBy:   code.CreatedBy,
+		RevokedAt:   code.RevokedAt,
+		Usable:      code.Usable(),
+		CreatedAt:   code.CreatedAt,
+	}
+}
@@ -7,19 +7,33 @@ import (
 
 func UserToResponse(user *entity.User) *model.UserResponse {
 	return &model.UserResponse{
-		ID:              user.ID,
-		Name:            user.Name,
-		Email:           user.Email,
-		EmailVerifiedAt: user.EmailVerifiedAt,
-		Password:        user.Password,
-		Phone:           user.Phone,
-		Avatar:          user.Avatar,
-		IsActive:        user.IsActive,
-		LastLoginAt:     user.LastLoginAt,
-		Role:            user.Role,
-		CreatedAt:       user.CreatedAt,
-		UpdatedAt:       user.UpdatedAt,
-		DeletedAt:       user.DeletedAt,
+		ID:                  user.ID,
+		Name:                user.Name,
+		Email:               user.Email,
+		EmailVerifiedAt:     user.EmailVerifiedAt,
+		Password:            user.Password,
+		TwoFactorEnabled:    user.TwoFactorEnabledAt != nil,
+		Phone:               user.Phone,
+		PhoneVerifiedAt:     user.PhoneVerifiedAt,
+		Avatar:              user.Avatar,
+		IsActive:            user.IsActive,
+		Suspended:           user.Suspended(),
+		SuspendedUntil:      user.SuspendedUntil,
+		SuspensionReason:    user.SuspensionReason,
+		SuspendedBy:         user.SuspendedBy,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+		InvitationStatus:    user.InvitationStatus(),
+		InvitedBy:           user.InvitedBy,
+		InvitedAt:           user.InvitedAt,
+		InvitationExpiresAt: user.InvitationExpiresAt,
+		ApprovalStatus:      user.ApprovalStatus,
+		ApprovedBy:          user.ApprovedBy,
+		ApprovedAt:          user.ApprovedAt,
+		LastLoginAt:         user.LastLoginAt,
+		Role:                user.Role,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+		DeletedAt:           user.DeletedAt,
 	}
 }
 
@@ -33,9 +47,18 @@ func CreateRequestToUser(request *model.CreateUserRequest) *entity.User {
 	}
 }
 
+func InviteRequestToUser(request *model.InviteUserRequest) *entity.User {
+	return &entity.User{
+		Name:      request.Name,
+		Email:     request.Email,
+		Phone:     request.Phone,
+		Role:      request.Role,
+		InvitedBy: &request.InvitedBy,
+	}
+}
+
 func UpdateRequestToUser(user *entity.User, request *model.UpdateUserRequest) *entity.User {
 	user.Name = request.Name
-	user.Email = request.Email
 	user.Password = request.Password
 	user.Phone = request.Phone
 	user.IsActive = request.IsActive
@@ -43,3 +66,11 @@ func UpdateRequestToUser(user *entity.User, request *model.UpdateUserRequest) *e
 	return user
 
 }
+
+func UserToImpersonatorResponse(user *entity.User) *model.ImpersonatorResponse {
+	return &model.ImpersonatorResponse{
+		ID:    user.ID,
+		Name:  user.Name,
+		Email: user.Email,
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type IdentityResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     *string   `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OIDCAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+// OIDCAuthorizeRequest memulai login atau penautan akun, UserID hanya diisi saat menautkan provider ke akun yang sedang login
+type OIDCAuthorizeRequest struct {
+	Provider string `validate:"required"`
+	UserID   uuid.UUID
+}
+
+type OIDCCallbackRequest struct {
+	Provider string `validate:"required"`
+	UserID   uuid.UUID
+	Code     string `json:"code" form:"code" validate:"required"`
+	State    string `json:"state" form:"state" validate:"required"`
+}
+
+type ListIdentityRequest struct {
+	UserID uuid.UUID `validate:"required"`
+}
+
+type UnlinkIdentityRequest struct {
+	UserID uuid.UUID `validate:"required"`
+	ID     string    `validate:"required,uuid"`
+}
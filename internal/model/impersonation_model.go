@@ -0,0 +1,19 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type ImpersonatorResponse struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+}
+
+type ImpersonateRequest struct {
+	ID             uuid.UUID `validate:"required"`
+	ImpersonatorID uuid.UUID `validate:"required"`
+	Reason         string    `json:"reason" form:"reason" validate:"omitempty,max=255"`
+	IPAddress      string
+	UserAgent      string
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/google/uuid"
+)
+
+type LoginEventResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         *uuid.UUID `json:"user_id"`
+	Email          *string    `json:"email"`
+	Method         string     `json:"method"`
+	Success        bool       `json:"success"`
+	FailureReason  *string    `json:"failure_reason"`
+	IPAddress      string     `json:"ip_address"`
+	UserAgent      *string    `json:"user_agent"`
+	Browser        *string    `json:"browser"`
+	BrowserVersion *string    `json:"browser_version"`
+	OS             *string    `json:"os"`
+	OSVersion      *string    `json:"os_version"`
+	Device         string     `json:"device"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type ListLoginHistoryRequest struct {
+	UserID uuid.UUID `validate:"required"`
+	response.PaginationRequest
+}
+
+// SearchLoginEventRequest dipakai admin untuk mencari riwayat login semua user, semua filter optional
+type SearchLoginEventRequest struct {
+	UserID        string     `json:"user_id" validate:"omitempty,uuid"`
+	Email         string     `json:"email" validate:"omitempty,max=100"`
+	IPAddress     string     `json:"ip_address" validate:"omitempty,ip"`
+	Method        string     `json:"method" validate:"omitempty,oneof=password magic_link oidc passkey two_factor"`
+	Success       string     `json:"success" validate:"omitempty,boolean"`
+	FailureReason string     `json:"failure_reason" validate:"omitempty,max=50"`
+	From          *time.Time `json:"from"`
+	To            *time.Time `json:"to"`
+	response.PaginationRequest
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type OAuthClientResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	ClientID   string     `json:"client_id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  *uuid.UUID `json:"created_by"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateOAuthClientResponse berisi client secret asli yang hanya ditampilkan satu kali saat dibuat
+type CreateOAuthClientResponse struct {
+	OAuthClientResponse
+	ClientSecret string `json:"client_secret"`
+}
+
+// CreateOAuthClientRequest mendaftarkan client untuk komunikasi antar service, scope yang boleh diminta berupa nama permission
+type CreateOAuthClientRequest struct {
+	CreatedBy uuid.UUID `validate:"required"`
+	Name      string    `json:"name" form:"name" validate:"required,max=100"`
+	Scopes    []string  `json:"scopes" form:"scopes" validate:"required,min=1,dive,exists=permissions.name"`
+}
+
+type RevokeOAuthClientRequest struct {
+	ID string `validate:"required,uuid"`
+}
+
+// OAuthTokenRequest mengikuti RFC 6749 section 4.4, client bisa mengirim kredensial lewat HTTP Basic atau body
+type OAuthTokenRequest struct {
+	GrantType    string `json:"grant_type" form:"grant_type"`
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	Scope        string `json:"scope" form:"scope"` // dipisahkan spasi, kosong berarti semua scope client
+}
+
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// OAuthError adalah error response RFC 6749 section 5.2, dikirim apa adanya tanpa response.Response
+type OAuthError struct {
+	Status      int    `json:"-"`
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
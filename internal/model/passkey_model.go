@@ -0,0 +1,44 @@
+package model
+
+import (
+	"github.com/alfianyulianto/pds-service/pkg/webauthn"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PasskeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Transports []string   `json:"transports"`
+	AAGUID     *string    `json:"aaguid"`
+	SignCount  uint32     `json:"sign_count"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type PasskeyRegistrationOptionsRequest struct {
+	UserID uuid.UUID `validate:"required"`
+}
+
+type RegisterPasskeyRequest struct {
+	UserID     uuid.UUID                    `validate:"required"`
+	Name       string                       `json:"name" form:"name" validate:"required,max=100"`
+	Credential webauthn.AttestationResponse `json:"credential"`
+}
+
+type ListPasskeyRequest struct {
+	UserID uuid.UUID `validate:"required"`
+}
+
+type DeletePasskeyRequest struct {
+	UserID uuid.UUID `validate:"required"`
+	ID     string    `validate:"required,uuid"`
+}
+
+type PasskeyLoginOptionsRequest struct {
+	Email string `json:"email" form:"email" validate:"omitempty,email"` // kosong = passkey discoverable, browser menawarkan semua passkey
+}
+
+type PasskeyLoginRequest struct {
+	Credential webauthn.AssertionResponse `json:"credential"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type SendPhoneVerificationRequest struct {
+	UserID    uuid.UUID `validate:"required"`
+	IPAddress string    `validate:"required"`
+	Phone     *string   `json:"phone" form:"phone" validate:"omitempty,e164"` // kosong = verifikasi nomor yang sudah tersimpan
+}
+
+type VerifyPhoneRequest struct {
+	UserID uuid.UUID `validate:"required"`
+	Code   string    `json:"code" form:"code" validate:"required,len=6,numeric"`
+}
+
+type PhoneLoginCodeRequest struct {
+	Phone string `json:"phone" form:"phone" validate:"required,e164"`
+}
+
+type PhoneLoginRequest struct {
+	Phone string `json:"phone" form:"phone" validate:"required,e164"`
+	Code  string `json:"code" form:"code" validate:"required,len=6,numeric"`
+}
+
+// PhoneCodeResponse tidak berisi kode OTP, kode hanya dikirim lewat SMS
+type PhoneCodeResponse struct {
+	Phone     string `json:"phone"`
+	ExpiresIn int    `json:"expires_in"` // dalam detik
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type RegistrationCodeResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Prefix      string     `json:"prefix"`
+	Description *string    `json:"description"`
+	MaxUses     *int       `json:"max_uses"`
+	Uses        int        `json:"uses"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	CreatedBy   *uuid.UUID `json:"created_by"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	Usable      bool       `json:"usable"` // false jika kode sudah di revoke, kadaluarsa atau kuotanya habis
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreateRegistrationCodeResponse berisi kode asli yang hanya ditampilkan satu kali saat dibuat
+type CreateRegistrationCodeResponse struct {
+	RegistrationCodeResponse
+	Code string `json:"code"`
+}
+
+type CreateRegistrationCodeRequest struct {
+	CreatedBy   uuid.UUID `validate:"required"`
+	Description *string   `json:"description" form:"description" validate:"omitempty,max=255"`
+	MaxUses     *int      `json:"max_uses" form:"max_uses" validate:"omitempty,min=1"` // kosong = tanpa batas pemakaian, 1 = sekali pakai
+	ExpiresAt   time.Time `json:"expires_at" form:"expires_at" validate:"required"`
+}
+
+type RevokeRegistrationCodeRequest struct {
+	ID string `validate:"required,uuid"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type RoleResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PermissionResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+}
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" form:"name" validate:"required,max=50,unique=roles.name"`
+	Description *string  `json:"description" form:"description" validate:"omitempty,max=255"`
+	Permissions []string `json:"permissions" form:"permissions" validate:"dive,exists=permissions.name"`
+}
+
+type UpdateRoleRequest struct {
+	ID          uuid.UUID `json:"id" form:"id" validate:"required"`
+	Name        string    `json:"name" form:"name" validate:"required,max=50,unique=roles.name.ID"`
+	Description *string   `json:"description" form:"description" validate:"omitempty,max=255"`
+	Permissions []string  `json:"permissions" form:"permissions" validate:"dive,exists=permissions.name"`
+}
+
+// AssignRoleRequest mengganti role user, ActorID adalah admin yang melakukan perubahan
+type AssignRoleRequest struct {
+	ActorID uuid.UUID `validate:"required"`
+	ID      uuid.UUID `validate:"required"`
+	Role    string    `json:"role" form:"role" validate:"required,exists=roles.name"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// ClientInfo berisi informasi perangkat yang melakukan request, diisi oleh controller lewat context "ClientInfoKey"
+type ClientInfo struct {
+	Device         string
+	IPAddress      string
+	UserAgent      string
+	Browser        string
+	BrowserVersion string
+	OS             string
+	OSVersion      string
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+type ListSessionRequest struct {
+	UserID           uuid.UUID `validate:"required"`
+	CurrentSessionID string
+}
+
+type RevokeSessionRequest struct {
+	UserID           uuid.UUID `validate:"required"`
+	CurrentSessionID string
+	ID               string `validate:"required,uuid"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import "github.com/google/uuid"
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type ConfirmTwoFactorRequest struct {
+	ID   uuid.UUID `validate:"required"`
+	Code string    `json:"code" form:"code" validate:"required,numeric,len=6"`
+}
+
+type DisableTwoFactorRequest struct {
+	ID       uuid.UUID `validate:"required,exists=users.id"`
+	Password string    `json:"password" form:"password" validate:"required,match_password=users"`
+	Code     string    `json:"code" form:"code" validate:"required"`
+}
+
+type RegenerateRecoveryCodesRequest struct {
+	ID   uuid.UUID `validate:"required"`
+	Code string    `json:"code" form:"code" validate:"required"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" form:"challenge_token" validate:"required"`
+	Code           string `json:"code" form:"code" validate:"required"`
+}
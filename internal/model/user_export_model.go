@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ExportUserRequest struct {
+	ExportedBy uuid.UUID `validate:"required"`
+	Format     string    `json:"format" validate:"required,oneof=csv xlsx ndjson"`
+	Columns    string    `json:"columns" validate:"omitempty,max=500"` // nama kolom dipisah koma, kosong = kolom default
+	Background bool      `json:"background"`                           // paksa dijalankan di background walaupun jumlah baris di bawah export.background_threshold
+	SearchUserRequest
+}
+
+type UserExportRequest struct {
+	ID      string    `json:"id" validate:"required,uuid"`
+	ActorID uuid.UUID `validate:"required"`
+}
+
+type UserExportResponse struct {
+	ID            string     `json:"id"`
+	Status        string     `json:"status"` // queued, processing, completed atau failed
+	Format        string     `json:"format"`
+	Columns       []string   `json:"columns"`
+	TotalRows     int64      `json:"total_rows"`
+	ProcessedRows int64      `json:"processed_rows"`
+	FileName      string     `json:"file_name"`
+	DownloadURL   string     `json:"download_url,omitempty"` // diisi setelah export selesai
+	Error         string     `json:"error,omitempty"`
+	ExportedBy    uuid.UUID  `json:"exported_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	FinishedAt    *time.Time `json:"finished_at"`
+	ExpiresAt     time.Time  `json:"expires_at"` // file dihapus setelah waktu ini
+}
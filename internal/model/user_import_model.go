@@ -0,0 +1,43 @@
+package model
+
+import (
+	"mime/multipart"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportUserRequest struct {
+	ImportedBy uuid.UUID             `validate:"required"`
+	File       *multipart.FileHeader `json:"file" form:"file" validate:"required,size=10"`              // csv atau xlsx, baris pertama berisi header
+	Mapping    string                `json:"mapping" form:"mapping" validate:"omitempty,json,max=2000"` // JSON {"field": "nama kolom"}, field yang tidak dipetakan dicari dari kolom dengan nama yang sama
+	DryRun     bool                  `json:"dry_run" form:"dry_run" validate:"boolean"`                 // hanya validasi, tidak ada user yang dibuat
+	Invite     bool                  `json:"invite" form:"invite" validate:"boolean"`                   // kirim undangan email, kolom password tidak dipakai
+}
+
+type GetUserImportRequest struct {
+	ID string `json:"id" validate:"required,uuid"`
+}
+
+type UserImportResponse struct {
+	ID            string           `json:"id"`
+	Status        string           `json:"status"` // queued, processing atau completed
+	FileName      string           `json:"file_name"`
+	DryRun        bool             `json:"dry_run"`
+	Invite        bool             `json:"invite"`
+	TotalRows     int              `json:"total_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	ValidRows     int              `json:"valid_rows"`
+	CreatedRows   int              `json:"created_rows"` // selalu 0 untuk dry run
+	FailedRows    int              `json:"failed_rows"`
+	Errors        []ImportRowError `json:"errors"`
+	ImportedBy    uuid.UUID        `json:"imported_by"`
+	CreatedAt     time.Time        `json:"created_at"`
+	FinishedAt    *time.Time       `json:"finished_at"`
+}
+
+type ImportRowError struct {
+	Row    int               `json:"row"` // nomor baris di file, header adalah baris 1
+	Email  string            `json:"email,omitempty"`
+	Errors map[string]string `json:"errors"` // per field, error yang bukan milik field tertentu memakai key "row"
+}
@@ -9,27 +9,42 @@ import (
 )
 
 type UserResponse struct {
-	ID              uuid.UUID      `json:"id"`
-	Name            string         `json:"name"`
-	Email           string         `json:"email"`
-	EmailVerifiedAt *time.Time     `json:"email_verified_at"`
-	Password        string         `json:"-"`
-	Phone           *string        `json:"phone"`
-	Avatar          *string        `json:"avatar"`
-	IsActive        bool           `json:"is_active"`
-	LastLoginAt     *time.Time     `json:"last_login_at"`
-	Role            string         `json:"role"`
-	CreatedAt       time.Time      `json:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at"`
-	DeletedAt       gorm.DeletedAt `json:"deleted_at"`
+	ID                  uuid.UUID             `json:"id"`
+	Name                string                `json:"name"`
+	Email               string                `json:"email"`
+	EmailVerifiedAt     *time.Time            `json:"email_verified_at"`
+	Password            string                `json:"-"`
+	TwoFactorEnabled    bool                  `json:"two_factor_enabled"`
+	Phone               *string               `json:"phone"`
+	PhoneVerifiedAt     *time.Time            `json:"phone_verified_at"`
+	Avatar              *string               `json:"avatar"`
+	IsActive            bool                  `json:"is_active"`
+	Suspended           bool                  `json:"suspended"`
+	SuspendedUntil      *time.Time            `json:"suspended_until"`
+	SuspensionReason    *string               `json:"suspension_reason"`
+	SuspendedBy         *uuid.UUID            `json:"suspended_by"`
+	DeletionScheduledAt *time.Time            `json:"deletion_scheduled_at"`
+	InvitationStatus    string                `json:"invitation_status,omitempty"` // pending, expired atau accepted, kosong jika user tidak diundang
+	InvitedBy           *uuid.UUID            `json:"invited_by,omitempty"`
+	InvitedAt           *time.Time            `json:"invited_at,omitempty"`
+	InvitationExpiresAt *time.Time            `json:"invitation_expires_at,omitempty"`
+	ApprovalStatus      *string               `json:"approval_status,omitempty"` // pending atau approved, kosong jika pendaftaran tidak butuh persetujuan
+	ApprovedBy          *uuid.UUID            `json:"approved_by,omitempty"`
+	ApprovedAt          *time.Time            `json:"approved_at,omitempty"`
+	LastLoginAt         *time.Time            `json:"last_login_at"`
+	Role                string                `json:"role"`
+	CreatedAt           time.Time             `json:"created_at"`
+	UpdatedAt           time.Time             `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt        `json:"deleted_at"`
+	Impersonator        *ImpersonatorResponse `json:"impersonator,omitempty"` // hanya diisi di /api/auth/_current saat admin sedang impersonate
 }
 
 type CreateUserRequest struct {
 	Name            string                `json:"name" form:"name" validate:"required,max=255"`
 	Email           string                `json:"email" form:"email" validate:"required,email,max=100,unique=users.email"` // unique=table.column
-	Password        string                `json:"password" form:"password" validate:"required,min=8,max=100"`
+	Password        string                `json:"password" form:"password" validate:"required" password:"policy"`
 	ConfirmPassword string                `json:"confirm_password" form:"confirm_password" validate:"required,eqfield=Password"`
-	Phone           *string               `json:"phone" form:"phone" validate:"omitempty,max=20"`
+	Phone           *string               `json:"phone" form:"phone" validate:"omitempty,e164"` // dinormalisasi ke E.164 sebelum divalidasi
 	Avatar          *multipart.FileHeader `json:"avatar" form:"avatar" validate:"omitempty,image,size=2"`
 	IsActive        bool                  `json:"is_active" form:"is_active" validate:"boolean"`
 }
@@ -37,23 +52,68 @@ type CreateUserRequest struct {
 type UpdateUserRequest struct {
 	ID              uuid.UUID             `json:"id" form:"id" validate:"required,uuid"`
 	Name            string                `json:"name" form:"name" validate:"required,max=255"`
-	Email           string                `json:"email" form:"email" validate:"required,email,max=100,unique=users.email.ID"` // unique=table.column.ignore1.ignore2
-	Password        string                `json:"password" form:"password" validate:"omitempty,min=8,max=100"`
+	Password        string                `json:"password" form:"password" validate:"omitempty" password:"policy"`
 	ConfirmPassword string                `json:"confirm_password" form:"confirm_password" validate:"required_with,eqfield=Password"`
-	Phone           *string               `json:"phone" form:"phone" validate:"omitempty,max=20"`
+	Phone           *string               `json:"phone" form:"phone" validate:"omitempty,e164"`
 	Avatar          *multipart.FileHeader `json:"avatar" form:"avatar" validate:"omitempty,image,size=2"`
 	IsActive        bool                  `json:"is_active" form:"is_active" validate:"boolean"`
 }
 
 type SearchUserRequest struct {
-	Search   string `json:"search" form:"search" validate:"omitempty"`
-	IsActive string `json:"is_active" form:"is_active" validate:"omitempty,boolean"`
-	Role     string `json:"role" form:"role" validate:"omitempty,oneof=Admin User"`
-	OrderBy  string `json:"order_by" validate:"omitempty"`
-	OrderDir string `json:"order_dir" validate:"omitempty,oneof=asc desc"`
+	Search     string `json:"search" form:"search" validate:"omitempty"`
+	IsActive   string `json:"is_active" form:"is_active" validate:"omitempty,boolean"`
+	Role       string `json:"role" form:"role" validate:"omitempty,exists=roles.name"`
+	Invitation string `json:"invitation" form:"invitation" validate:"omitempty,oneof=pending expired accepted"`
+	Approval   string `json:"approval" form:"approval" validate:"omitempty,oneof=pending approved"`
+	OrderBy    string `json:"order_by" validate:"omitempty"`
+	OrderDir   string `json:"order_dir" validate:"omitempty,oneof=asc desc"`
 	response.PaginationRequest
 }
 
+type InviteUserRequest struct {
+	InvitedBy uuid.UUID `validate:"required"`
+	Name      string    `json:"name" form:"name" validate:"required,max=255"`
+	Email     string    `json:"email" form:"email" validate:"required,email,max=100,unique=users.email"`
+	Phone     *string   `json:"phone" form:"phone" validate:"omitempty,e164"`
+	Role      string    `json:"role" form:"role" validate:"omitempty,exists=roles.name"` // kosong = role default User
+}
+
+type InvitationRequest struct {
+	ID      uuid.UUID `validate:"required"`
+	ActorID uuid.UUID `validate:"required"`
+}
+
+type AcceptInvitationRequest struct {
+	ID              uuid.UUID // diisi dari token, dipakai password_policy untuk menolak password yang mengandung nama atau email
+	Token           string    `json:"token" form:"token" validate:"required"`
+	Password        string    `json:"password" form:"password" validate:"required" password:"policy"`
+	ConfirmPassword string    `json:"confirm_password" form:"confirm_password" validate:"required,eqfield=Password"`
+}
+
+type ApproveRegistrationRequest struct {
+	ID      uuid.UUID `validate:"required"`
+	ActorID uuid.UUID `validate:"required"`
+}
+
+type RejectRegistrationRequest struct {
+	ID      uuid.UUID `validate:"required"`
+	ActorID uuid.UUID `validate:"required"`
+	Reason  string    `json:"reason" form:"reason" validate:"omitempty,max=255"`
+}
+
+type SuspendUserRequest struct {
+	ID      uuid.UUID  `validate:"required"`
+	ActorID uuid.UUID  `validate:"required"`
+	Reason  string     `json:"reason" form:"reason" validate:"required,max=255"`
+	Until   *time.Time `json:"until" form:"until"` // kosong = suspend sampai di reinstate
+}
+
+type ReinstateUserRequest struct {
+	ID      uuid.UUID `validate:"required"`
+	ActorID uuid.UUID `validate:"required"`
+}
+
 type GetUserRequest struct {
-	ID uuid.UUID `json:"id" form:"id" validate:"required,uuid"`
+	ID           uuid.UUID  `json:"id" form:"id" validate:"required,uuid"`
+	Impersonator *uuid.UUID `json:"-"`
 }
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type ApiKeyRepository interface {
+	Create(db *gorm.DB, apiKey *entity.ApiKey) error
+	Update(db *gorm.DB, apiKey *entity.ApiKey) error
+	FindActiveByHash(db *gorm.DB, apiKey *entity.ApiKey, keyHash string) error
+	FindByIdAndUserId(db *gorm.DB, apiKey *entity.ApiKey, id string, userID uuid.UUID) error
+	FindAllByUserId(db *gorm.DB, userID uuid.UUID) ([]entity.ApiKey, error)
+	TouchLastUsed(db *gorm.DB, id uuid.UUID, usedAt time.Time, ip string) error
+}
+
+type apiKeyRepository struct {
+	Repository[entity.ApiKey]
+	Log *logrus.Entry
+}
+
+func NewApiKeyRepository(log *logrus.Entry) ApiKeyRepository {
+	return &apiKeyRepository{Log: log}
+}
+
+func (r *apiKeyRepository) FindActiveByHash(db *gorm.DB, apiKey *entity.ApiKey, keyHash string) error {
+	return db.Where("key_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", keyHash, time.Now()).Take(apiKey).Error
+}
+
+func (r *apiKeyRepository) FindByIdAndUserId(db *gorm.DB, apiKey *entity.ApiKey, id string, userID uuid.UUID) error {
+	return db.Where("id = ? AND user_id = ?", id, userID).Take(apiKey).Error
+}
+
+func (r *apiKeyRepository) FindAllByUserId(db *gorm.DB, userID uuid.UUID) ([]entity.ApiKey, error) {
+	var apiKeys []entity.ApiKey
+	err := db.Where("user_id = ?", userID).Order("created_at desc").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+// TouchLastUsed hanya mengubah kolom last used supaya tidak menimpa perubahan lain (misalnya revoke) yang terjadi bersamaan
+func (r *apiKeyRepository) TouchLastUsed(db *gorm.DB, id uuid.UUID, usedAt time.Time, ip string) error {
+	return db.Model(new(entity.ApiKey)).Where("id = ?", id).Updates(map[string]any{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
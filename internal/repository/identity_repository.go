@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type IdentityRepository interface {
+	Create(db *gorm.DB, identity *entity.Identity) error
+	Update(db *gorm.DB, identity *entity.Identity) error
+	HardDelete(db *gorm.DB, identity *entity.Identity) error
+	FindByProviderSubject(db *gorm.DB, identity *entity.Identity, provider string, subject string) error
+	FindByIdAndUserId(db *gorm.DB, identity *entity.Identity, id string, userID uuid.UUID) error
+	FindAllByUserId(db *gorm.DB, userID uuid.UUID) ([]entity.Identity, error)
+}
+
+type identityRepository struct {
+	Repository[entity.Identity]
+	Log *logrus.Entry
+}
+
+func NewIdentityRepository(log *logrus.Entry) IdentityRepository {
+	return &identityRepository{Log: log}
+}
+
+func (r *identityRepository) FindByProviderSubject(db *gorm.DB, identity *entity.Identity, provider string, subject string) error {
+	return db.Where("provider = ? AND subject = ?", provider, subject).Take(identity).Error
+}
+
+func (r *identityRepository) FindByIdAndUserId(db *gorm.DB, identity *entity.Identity, id string, userID uuid.UUID) error {
+	return db.Where("id = ? AND user_id = ?", id, userID).Take(identity).Error
+}
+
+func (r *identityRepository) FindAllByUserId(db *gorm.DB, userID uuid.UUID) ([]entity.Identity, error) {
+	var identities []entity.Identity
+	err := db.Where("user_id = ?", userID).Order("created_at asc").Find(&identities).Error
+	return identities, err
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type ImpersonationRepository interface {
+	Create(db *gorm.DB, impersonation *entity.Impersonation) error
+	Update(db *gorm.DB, impersonation *entity.Impersonation) error
+	FindById(db *gorm.DB, impersonation *entity.Impersonation, id any) error
+}
+
+type impersonationRepository struct {
+	Repository[entity.Impersonation]
+	Log *logrus.Entry
+}
+
+func NewImpersonationRepository(log *logrus.Entry) ImpersonationRepository {
+	return &impersonationRepository{Log: log}
+}
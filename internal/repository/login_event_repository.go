@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+)
+
+type LoginEventRepository interface {
+	Create(db *gorm.DB, event *entity.LoginEvent) error
+	FindAll(db *gorm.DB, request *model.SearchLoginEventRequest) ([]entity.LoginEvent, int64, error)
+	DeleteBefore(db *gorm.DB, before time.Time) (int64, error)
+}
+
+type loginEventRepository struct {
+	Repository[entity.LoginEvent]
+	Log *logrus.Entry
+}
+
+func NewLoginEventRepository(log *logrus.Entry) LoginEventRepository {
+	return &loginEventRepository{Log: log}
+}
+
+func (r *loginEventRepository) FindAll(db *gorm.DB, request *model.SearchLoginEventRequest) ([]entity.LoginEvent, int64, error) {
+	var count int64
+	if err := db.Model(new(entity.LoginEvent)).Scopes(r.Filter(request)).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []entity.LoginEvent
+	err := db.Scopes(r.Filter(request)).Order("created_at desc").Offset((request.Page - 1) * request.PageSize).Limit(request.PageSize).Find(&events).Error
+	return events, count, err
+}
+
+func (r *loginEventRepository) Filter(request *model.SearchLoginEventRequest) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if request.UserID != "" {
+			tx = tx.Where("user_id = ?", request.UserID)
+		}
+
+		if request.Email != "" {
+			tx = tx.Where("email = ?", request.Email)
+		}
+
+		if request.IPAddress != "" {
+			tx = tx.Where("ip_address = ?", request.IPAddress)
+		}
+
+		if request.Method != "" {
+			tx = tx.Where("method = ?", request.Method)
+		}
+
+		if success, err := strconv.ParseBool(request.Success); err == nil {
+			tx = tx.Where("success = ?", success)
+		}
+
+		if request.FailureReason != "" {
+			tx = tx.Where("failure_reason = ?", request.FailureReason)
+		}
+
+		if request.From != nil {
+			tx = tx.Where("created_at >= ?", *request.From)
+		}
+
+		if request.To != nil {
+			tx = tx.Where("created_at < ?", *request.To)
+		}
+
+		return tx
+	}
+}
+
+func (r *loginEventRepository) DeleteBefore(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("created_at < ?", before).Delete(new(entity.LoginEvent))
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type OAuthClientRepository interface {
+	Create(db *gorm.DB, client *entity.OAuthClient) error
+	Update(db *gorm.DB, client *entity.OAuthClient) error
+	FindById(db *gorm.DB, client *entity.OAuthClient, id any) error
+	FindByClientId(db *gorm.DB, client *entity.OAuthClient, clientID string) error
+	FindAll(db *gorm.DB) ([]entity.OAuthClient, error)
+	TouchLastUsed(db *gorm.DB, id uuid.UUID, usedAt time.Time) error
+}
+
+type oauthClientRepository struct {
+	Repository[entity.OAuthClient]
+	Log *logrus.Entry
+}
+
+func NewOAuthClientRepository(log *logrus.Entry) OAuthClientRepository {
+	return &oauthClientRepository{Log: log}
+}
+
+func (r *oauthClientRepository) FindByClientId(db *gorm.DB, client *entity.OAuthClient, clientID string) error {
+	return db.Where("client_id = ?", clientID).Take(client).Error
+}
+
+func (r *oauthClientRepository) FindAll(db *gorm.DB) ([]entity.OAuthClient, error) {
+	var clients []entity.OAuthClient
+	err := db.Order("created_at desc").Find(&clients).Error
+	return clients, err
+}
+
+// TouchLastUsed hanya mengubah kolom last_used_at supaya tidak menimpa revoke yang terjadi bersamaan
+func (r *oauthClientRepository) TouchLastUsed(db *gorm.DB, id uuid.UUID, usedAt time.Time) error {
+	return db.Model(new(entity.OAuthClient)).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
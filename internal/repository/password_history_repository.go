@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type PasswordHistoryRepository interface {
+	Create(db *gorm.DB, history *entity.PasswordHistory) error
+	Prune(db *gorm.DB, userID uuid.UUID, keep int) error
+}
+
+type passwordHistoryRepository struct {
+	Repository[entity.PasswordHistory]
+	Log *logrus.Entry
+}
+
+func NewPasswordHistoryRepository(log *logrus.Entry) PasswordHistoryRepository {
+	return &passwordHistoryRepository{Log: log}
+}
+
+// Prune menghapus riwayat password user selain keep riwayat terbaru
+func (r *passwordHistoryRepository) Prune(db *gorm.DB, userID uuid.UUID, keep int) error {
+	var ids []uuid.UUID
+	err := db.Model(new(entity.PasswordHistory)).Where("user_id = ?", userID).
+		Order("created_at desc").Pluck("id", &ids).Error
+	if err != nil || len(ids) <= keep {
+		return err
+	}
+
+	return db.Where("id IN ?", ids[keep:]).Delete(new(entity.PasswordHistory)).Error
+}
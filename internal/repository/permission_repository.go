@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type PermissionRepository interface {
+	FindAll(db *gorm.DB) ([]entity.Permission, error)
+	FindByNames(db *gorm.DB, names []string) ([]entity.Permission, error)
+}
+
+type permissionRepository struct {
+	Repository[entity.Permission]
+	Log *logrus.Entry
+}
+
+func NewPermissionRepository(log *logrus.Entry) PermissionRepository {
+	return &permissionRepository{Log: log}
+}
+
+func (r *permissionRepository) FindAll(db *gorm.DB) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	err := db.Order("name asc").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *permissionRepository) FindByNames(db *gorm.DB, names []string) ([]entity.Permission, error) {
+	var permissions []entity.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+
+	err := db.Where("name IN ?", names).Find(&permissions).Error
+	return permissions, err
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type RecoveryCodeRepository interface {
+	Create(db *gorm.DB, code *entity.RecoveryCode) error
+	Update(db *gorm.DB, code *entity.RecoveryCode) error
+	Consume(db *gorm.DB, userID uuid.UUID, codeHash string) (bool, error)
+	CountUnused(db *gorm.DB, userID uuid.UUID) (int64, error)
+	DeleteByUserId(db *gorm.DB, userID uuid.UUID) error
+}
+
+type recoveryCodeRepository struct {
+	Repository[entity.RecoveryCode]
+	Log *logrus.Entry
+}
+
+func NewRecoveryCodeRepository(log *logrus.Entry) RecoveryCodeRepository {
+	return &recoveryCodeRepository{Log: log}
+}
+
+// Consume menandai kode yang belum dipakai sebagai terpakai dengan satu query bersyarat, sehingga kode yang sama tidak bisa
+// dipakai dua request yang berjalan bersamaan. Mengembalikan false jika kode tidak ada atau sudah dipakai
+func (r *recoveryCodeRepository) Consume(db *gorm.DB, userID uuid.UUID, codeHash string) (bool, error) {
+	result := db.Model(new(entity.RecoveryCode)).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *recoveryCodeRepository) CountUnused(db *gorm.DB, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := db.Model(new(entity.RecoveryCode)).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *recoveryCodeRepository) DeleteByUserId(db *gorm.DB, userID uuid.UUID) error {
+	return db.Where("user_id = ?", userID).Delete(new(entity.RecoveryCode)).Error
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type RegistrationCodeRepository interface {
+	Create(db *gorm.DB, code *entity.RegistrationCode) error
+	Update(db *gorm.DB, code *entity.RegistrationCode) error
+	FindById(db *gorm.DB, code *entity.RegistrationCode, id any) error
+	FindByCodeHash(db *gorm.DB, code *entity.RegistrationCode, codeHash string) error
+	FindAll(db *gorm.DB) ([]entity.RegistrationCode, error)
+	Consume(db *gorm.DB, id uuid.UUID) (bool, error)
+}
+
+type registrationCodeRepository struct {
+	Repository[entity.RegistrationCode]
+	Log *logrus.Entry
+}
+
+func NewRegistrationCodeRepository(log *logrus.Entry) RegistrationCodeRepository {
+	return &registrationCodeRepository{Log: log}
+}
+
+func (r *registrationCodeRepository) FindByCodeHash(db *gorm.DB, code *entity.RegistrationCode, codeHash string) error {
+	return db.Where("code_hash = ?", codeHash).Take(code).Error
+}
+
+func (r *registrationCodeRepository) FindAll(db *gorm.DB) ([]entity.RegistrationCode, error) {
+	var codes []entity.RegistrationCode
+	err := db.Order("created_at desc").Find(&codes).Error
+	return codes, err
+}
+
+// Consume menambah jumlah pemakaian kode dengan satu query bersyarat, sehingga kode sekali pakai tidak bisa dipakai dua
+// registrasi yang berjalan bersamaan. Mengembalikan false jika kode sudah tidak bisa dipakai
+func (r *registrationCodeRepository) Consume(db *gorm.DB, id uuid.UUID) (bool, error) {
+	result := db.Model(new(entity.RegistrationCode)).
+		Where("id = ? and revoked_at is null and expires_at > ? and (max_uses is null or uses < max_uses)", id, time.Now()).
+		Update("uses", gorm.Expr("uses + 1"))
+
+	return result.RowsAffected > 0, result.Error
+}
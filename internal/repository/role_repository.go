@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type RoleRepository interface {
+	Create(db *gorm.DB, role *entity.Role) error
+	Update(db *gorm.DB, role *entity.Role) error
+	HardDelete(db *gorm.DB, role *entity.Role) error
+	FindAll(db *gorm.DB) ([]entity.Role, error)
+	FindById(db *gorm.DB, role *entity.Role, id any) error
+	FindByName(db *gorm.DB, role *entity.Role, name string) error
+	ReplacePermissions(db *gorm.DB, role *entity.Role, permissions []entity.Permission) error
+	RenameUsersRole(db *gorm.DB, oldName string, newName string) error
+	CountUsers(db *gorm.DB, name string) (int64, error)
+}
+
+type roleRepository struct {
+	Repository[entity.Role]
+	Log *logrus.Entry
+}
+
+func NewRoleRepository(log *logrus.Entry) RoleRepository {
+	return &roleRepository{Log: log}
+}
+
+func (r *roleRepository) FindAll(db *gorm.DB) ([]entity.Role, error) {
+	var roles []entity.Role
+	err := db.Preload("Permissions").Order("name asc").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) FindById(db *gorm.DB, role *entity.Role, id any) error {
+	return db.Preload("Permissions").Where("id = ?", id).Take(role).Error
+}
+
+func (r *roleRepository) FindByName(db *gorm.DB, role *entity.Role, name string) error {
+	return db.Preload("Permissions").Where("name = ?", name).Take(role).Error
+}
+
+// ReplacePermissions menulis tabel role_permissions secara langsung, supaya data permission tidak ikut di upsert oleh gorm
+func (r *roleRepository) ReplacePermissions(db *gorm.DB, role *entity.Role, permissions []entity.Permission) error {
+	if err := db.Table("role_permissions").Where("role_id = ?", role.ID).Delete(nil).Error; err != nil {
+		return err
+	}
+
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	rows := make([]map[string]any, len(permissions))
+	for i, permission := range permissions {
+		rows[i] = map[string]any{"role_id": role.ID, "permission_id": permission.ID}
+	}
+
+	return db.Table("role_permissions").Create(rows).Error
+}
+
+// RenameUsersRole menyesuaikan kolom users.role ketika nama role diubah, karena user menyimpan nama role bukan id
+func (r *roleRepository) RenameUsersRole(db *gorm.DB, oldName string, newName string) error {
+	return db.Model(new(entity.User)).Unscoped().Where("role = ?", oldName).Update("role", newName).Error
+}
+
+func (r *roleRepository) CountUsers(db *gorm.DB, name string) (int64, error) {
+	var count int64
+	err := db.Model(new(entity.User)).Unscoped().Where("role = ?", name).Count(&count).Error
+	return count, err
+}
@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/alfianyulianto/pds-service/internal/entity"
 	"github.com/alfianyulianto/pds-service/internal/model"
@@ -15,7 +18,14 @@ type UserRepository interface {
 	SoftDelete(db *gorm.DB, user *entity.User) error
 	HardDelete(db *gorm.DB, user *entity.User) error
 	FindAll(db *gorm.DB, request *model.SearchUserRequest) ([]entity.User, int64, error)
+	Count(db *gorm.DB, request *model.SearchUserRequest) (int64, error)
+	FindInBatches(db *gorm.DB, request *model.SearchUserRequest, batchSize int, fn func(users []entity.User) error) error
 	FindByEmail(db *gorm.DB, user *entity.User, email string) error
+	FindByInvitationTokenHash(db *gorm.DB, user *entity.User, tokenHash string) error
+	FindByVerifiedPhone(db *gorm.DB, user *entity.User, phone string) error
+	FindDeletionDue(db *gorm.DB, before time.Time, limit int) ([]entity.User, error)
+	LockDeletionDue(db *gorm.DB, user *entity.User, id any, before time.Time) error
+	DeleteRelations(db *gorm.DB, user *entity.User) error
 }
 
 type userRepository struct {
@@ -33,22 +43,52 @@ func (r *userRepository) FindAll(db *gorm.DB, request *model.SearchUserRequest)
 		return nil, 0, err
 	}
 
-	var count int64
-	if err := db.Model(new(entity.User)).Count(&count).Error; err != nil {
+	count, err := r.Count(db, request)
+	if err != nil {
 		return nil, 0, err
 	}
 
 	return users, count, nil
 }
 
+func (r *userRepository) Count(db *gorm.DB, request *model.SearchUserRequest) (int64, error) {
+	var count int64
+	err := db.Model(new(entity.User)).Scopes(r.Conditions(request)).Count(&count).Error
+
+	return count, err
+}
+
+// FindInBatches membaca user sesuai filter per batch berurutan berdasarkan id, sehingga data sebanyak apapun tidak dimuat
+// ke memory sekaligus. Urutan dari order_by diabaikan karena batch berikutnya diambil dengan id > id terakhir
+func (r *userRepository) FindInBatches(db *gorm.DB, request *model.SearchUserRequest, batchSize int, fn func(users []entity.User) error) error {
+	var users []entity.User
+	return db.Scopes(r.Conditions(request)).FindInBatches(&users, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(users)
+	}).Error
+}
+
 func (r *userRepository) Filter(request *model.SearchUserRequest) func(tx *gorm.DB) *gorm.DB {
 	return func(tx *gorm.DB) *gorm.DB {
+		tx = r.Conditions(request)(tx)
+
+		if request.OrderBy != "" && request.OrderDir != "" {
+			tx = tx.Order(request.OrderBy + " " + request.OrderDir)
+		} else {
+			tx = tx.Order("created_at desc")
+		}
+
+		return tx
+	}
+}
+
+// Conditions adalah filter tanpa urutan, dipakai untuk count dan pembacaan per batch
+func (r *userRepository) Conditions(request *model.SearchUserRequest) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		// dikelompokkan supaya "or" tidak mengabaikan filter lain
 		if request.Search != "" {
 			search := "%" + request.Search + "%"
 
-			tx = tx.Where("name like ?", "%"+search+"%").
-				Or("email like ?", "%"+search+"%").
-				Or("phone like ?", "%"+search+"%")
+			tx = tx.Where("(name like ? or email like ? or phone like ?)", search, search, search)
 		}
 
 		if request.IsActive != "" {
@@ -59,10 +99,17 @@ func (r *userRepository) Filter(request *model.SearchUserRequest) func(tx *gorm.
 			tx = tx.Where("role = ?", request.Role)
 		}
 
-		if request.OrderBy != "" && request.OrderDir != "" {
-			tx = tx.Order(request.OrderBy + " " + request.OrderDir)
-		} else {
-			tx = tx.Order("created_at desc")
+		switch request.Invitation {
+		case "pending":
+			tx = tx.Where("invited_at is not null and invitation_accepted_at is null and invitation_expires_at > ?", time.Now())
+		case "expired":
+			tx = tx.Where("invited_at is not null and invitation_accepted_at is null and invitation_expires_at <= ?", time.Now())
+		case "accepted":
+			tx = tx.Where("invitation_accepted_at is not null")
+		}
+
+		if request.Approval != "" {
+			tx = tx.Where("approval_status = ?", request.Approval)
 		}
 
 		return tx
@@ -72,3 +119,38 @@ func (r *userRepository) Filter(request *model.SearchUserRequest) func(tx *gorm.
 func (r *userRepository) FindByEmail(db *gorm.DB, user *entity.User, email string) error {
 	return db.Where("email = ?", email).Take(user).Error
 }
+
+func (r *userRepository) FindByInvitationTokenHash(db *gorm.DB, user *entity.User, tokenHash string) error {
+	return db.Where("invitation_token_hash = ?", tokenHash).Take(user).Error
+}
+
+func (r *userRepository) FindByVerifiedPhone(db *gorm.DB, user *entity.User, phone string) error {
+	return db.Where("phone = ? and phone_verified_at is not null", phone).Take(user).Error
+}
+
+func (r *userRepository) FindDeletionDue(db *gorm.DB, before time.Time, limit int) ([]entity.User, error) {
+	var users []entity.User
+	err := db.Where("deletion_scheduled_at is not null and deletion_scheduled_at <= ?", before).
+		Order("deletion_scheduled_at asc").Limit(limit).Find(&users).Error
+
+	return users, err
+}
+
+// LockDeletionDue membaca ulang user dengan FOR UPDATE hanya jika penghapusannya masih dijadwalkan dan sudah jatuh tempo,
+// mengembalikan gorm.ErrRecordNotFound jika penghapusan sudah dibatalkan
+func (r *userRepository) LockDeletionDue(db *gorm.DB, user *entity.User, id any, before time.Time) error {
+	return db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? and deletion_scheduled_at is not null and deletion_scheduled_at <= ?", id, before).
+		Take(user).Error
+}
+
+// DeleteRelations menghapus data milik user yang menyimpan data personal (tautan provider, API key, recovery code, riwayat password, passkey)
+func (r *userRepository) DeleteRelations(db *gorm.DB, user *entity.User) error {
+	for _, relation := range []any{new(entity.Identity), new(entity.ApiKey), new(entity.RecoveryCode), new(entity.PasswordHistory), new(entity.WebAuthnCredential), new(entity.LoginEvent)} {
+		if err := db.Unscoped().Where("user_id = ?", user.ID).Delete(relation).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+)
+
+type WebAuthnCredentialRepository interface {
+	Create(db *gorm.DB, credential *entity.WebAuthnCredential) error
+	Update(db *gorm.DB, credential *entity.WebAuthnCredential) error
+	HardDelete(db *gorm.DB, credential *entity.WebAuthnCredential) error
+	FindByCredentialId(db *gorm.DB, credential *entity.WebAuthnCredential, credentialID string) error
+	FindByIdAndUserId(db *gorm.DB, credential *entity.WebAuthnCredential, id string, userID uuid.UUID) error
+	FindAllByUserId(db *gorm.DB, userID uuid.UUID) ([]entity.WebAuthnCredential, error)
+}
+
+type webAuthnCredentialRepository struct {
+	Repository[entity.WebAuthnCredential]
+	Log *logrus.Entry
+}
+
+func NewWebAuthnCredentialRepository(log *logrus.Entry) WebAuthnCredentialRepository {
+	return &webAuthnCredentialRepository{Log: log}
+}
+
+func (r *webAuthnCredentialRepository) FindByCredentialId(db *gorm.DB, credential *entity.WebAuthnCredential, credentialID string) error {
+	return db.Where("credential_id = ?", credentialID).Take(credential).Error
+}
+
+func (r *webAuthnCredentialRepository) FindByIdAndUserId(db *gorm.DB, credential *entity.WebAuthnCredential, id string, userID uuid.UUID) error {
+	return db.Where("id = ? AND user_id = ?", id, userID).Take(credential).Error
+}
+
+func (r *webAuthnCredentialRepository) FindAllByUserId(db *gorm.DB, userID uuid.UUID) ([]entity.WebAuthnCredential, error) {
+	var credentials []entity.WebAuthnCredential
+	err := db.Where("user_id = ?", userID).Order("created_at desc").Find(&credentials).Error
+	return credentials, err
+}
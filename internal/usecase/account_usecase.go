@@ -2,30 +2,53 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/alfianyulianto/pds-service/internal/entity"
 	"github.com/alfianyulianto/pds-service/internal/model"
 	"github.com/alfianyulianto/pds-service/internal/model/converter"
 	"github.com/alfianyulianto/pds-service/internal/repository"
 	"github.com/alfianyulianto/pds-service/internal/utils"
+	"github.com/alfianyulianto/pds-service/pkg/auth"
 	"github.com/alfianyulianto/pds-service/pkg/email"
 	"github.com/gofiber/fiber/v2"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 	"time"
 )
 
 type AccountUseCase interface {
 	Current(ctx context.Context, request *model.GetUserRequest) (*model.UserResponse, error)
+	CheckStatus(ctx context.Context, id uuid.UUID) error
 	UpdatePassword(ctx context.Context, request *model.UpdatePasswordRequest) (*model.UserResponse, error)
+	ChangeEmail(ctx context.Context, request *model.ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, request *model.ConfirmEmailChangeRequest) (*model.UserResponse, error)
+	CancelEmailChange(ctx context.Context, request *model.CancelEmailChangeRequest) error
+	Delete(ctx context.Context, request *model.DeleteAccountRequest) (*model.UserResponse, error)
+	PurgeDeletedAccounts(ctx context.Context) (int, error)
+}
+
+// emailChange disimpan di redis sampai perubahan email dikonfirmasi lewat alamat email baru atau dibatalkan lewat alamat email lama
+type emailChange struct {
+	UserID      string `json:"user_id"`
+	NewEmail    string `json:"new_email"`
+	CancelToken string `json:"cancel_token"`
 }
 
 type accountUseCase struct {
 	*BaseUseCase
-	UserRepository repository.UserRepository
-	EmailService   *email.EmailService
+	UserRepository            repository.UserRepository
+	EmailService              *email.EmailService
+	JwtService                *auth.JWTService
+	PasswordHistoryRepository repository.PasswordHistoryRepository
+	Redis                     *redis.Client
 }
 
-func NewAccountUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, email *email.EmailService) AccountUseCase {
-	return &accountUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, EmailService: email}
+func NewAccountUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, email *email.EmailService, jwtService *auth.JWTService, passwordHistoryRepository repository.PasswordHistoryRepository, redis *redis.Client) AccountUseCase {
+	return &accountUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, EmailService: email, JwtService: jwtService, PasswordHistoryRepository: passwordHistoryRepository, Redis: redis}
 }
 
 func (u *accountUseCase) Current(ctx context.Context, request *model.GetUserRequest) (*model.UserResponse, error) {
@@ -43,12 +66,38 @@ func (u *accountUseCase) Current(ctx context.Context, request *model.GetUserRequ
 		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
 	}
 
+	// admin yang sedang impersonate ditampilkan supaya frontend bisa menampilkan banner
+	var impersonator *entity.User
+	if request.Impersonator != nil {
+		impersonator = new(entity.User)
+		if err := u.UserRepository.FindById(tx, impersonator, *request.Impersonator); err != nil {
+			u.Log.WithField("action", "current").WithError(err).Error("Failed to find impersonator")
+			return nil, fiber.NewError(fiber.StatusNotFound, "Impersonator data not found")
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		u.Log.WithField("action", "current").WithError(err).Error("Failed to commit transaction")
 		return nil, fiber.ErrInternalServerError
 	}
 
-	return converter.UserToResponse(user), nil
+	response := converter.UserToResponse(user)
+	if impersonator != nil {
+		response.Impersonator = converter.UserToImpersonatorResponse(impersonator)
+	}
+
+	return response, nil
+}
+
+// CheckStatus memastikan akun masih aktif dan tidak di suspend, dipakai AuthMiddleware di setiap request
+func (u *accountUseCase) CheckStatus(ctx context.Context, id uuid.UUID) error {
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(u.DB.WithContext(ctx), user, id); err != nil {
+		u.Log.WithField("action", "check account status").WithError(err).Warn("Failed to find user")
+		return fiber.ErrUnauthorized
+	}
+
+	return checkAccountStatus(user)
 }
 
 func (u *accountUseCase) UpdatePassword(ctx context.Context, request *model.UpdatePasswordRequest) (*model.UserResponse, error) {
@@ -66,23 +115,39 @@ func (u *accountUseCase) UpdatePassword(ctx context.Context, request *model.Upda
 		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
 	}
 
-	newPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	newPassword, err := u.Hasher.Hash(request.NewPassword)
 	if err != nil {
 		u.Log.WithField("action", "update password").WithError(err).Error("Failed to hash password")
 		return nil, fiber.ErrInternalServerError
 	}
-	user.Password = string(newPassword)
+	user.Password = newPassword
+	now := time.Now()
+	user.PasswordChangedAt = &now
 
 	if err = u.UserRepository.Update(tx, user); err != nil {
 		u.Log.WithField("action", "update password").WithError(err).Error("Failed to update user password")
 		return nil, fiber.ErrInternalServerError
 	}
 
+	if err = u.savePasswordHistory(tx, u.PasswordHistoryRepository, user); err != nil {
+		u.Log.WithField("action", "update password").WithError(err).Error("Failed to save password history")
+		return nil, fiber.ErrInternalServerError
+	}
+
 	if err = tx.Commit().Error; err != nil {
 		u.Log.WithField("action", "update password").WithError(err).Error("Failed to commit transaction")
 		return nil, fiber.ErrInternalServerError
 	}
 
+	keepSessionID := ""
+	if request.KeepCurrentSession {
+		keepSessionID = request.SessionID
+	}
+	if err = u.JwtService.InvalidateTokens(ctx, user.ID, keepSessionID); err != nil {
+		u.Log.WithField("action", "update password").WithError(err).Error("Failed to invalidate user tokens")
+		return nil, fiber.ErrInternalServerError
+	}
+
 	go func() {
 		err = email.QuickPasswordChangedEmail(u.EmailService, user.Email, user.Name, utils.FormatTime(time.Now()))
 		if err != nil {
@@ -92,3 +157,352 @@ func (u *accountUseCase) UpdatePassword(ctx context.Context, request *model.Upda
 
 	return converter.UserToResponse(user), nil
 }
+
+// ChangeEmail mengirim link konfirmasi ke alamat email baru dan pemberitahuan dengan link pembatalan ke alamat email lama.
+// Permintaan baru membatalkan permintaan sebelumnya yang belum dikonfirmasi
+func (u *accountUseCase) ChangeEmail(ctx context.Context, request *model.ChangeEmailRequest) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "change email").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "change email").WithError(err).Error("Failed to find user")
+		return fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "change email").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := u.deleteEmailChange(ctx, user.ID.String()); err != nil {
+		u.Log.WithField("action", "change email").WithError(err).Error("Failed to delete previous email change from redis")
+		return fiber.ErrInternalServerError
+	}
+
+	token := uuid.NewString()
+	change := &emailChange{UserID: user.ID.String(), NewEmail: request.NewEmail, CancelToken: uuid.NewString()}
+	value, err := json.Marshal(change)
+	if err != nil {
+		u.Log.WithField("action", "change email").WithError(err).Error("Failed to encode email change")
+		return fiber.ErrInternalServerError
+	}
+
+	_, err = u.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetEx(ctx, "change_email:"+token, value, 24*time.Hour)
+		pipe.SetEx(ctx, "change_email_cancel:"+change.CancelToken, token, 24*time.Hour)
+		pipe.SetEx(ctx, "change_email_user:"+change.UserID, token, 24*time.Hour)
+		return nil
+	})
+	if err != nil {
+		u.Log.WithField("action", "change email").WithError(err).Error("Failed to set email change token in redis")
+		return fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "change email").WithFields(logrus.Fields{
+		"event":     "email_change_requested",
+		"user_id":   user.ID,
+		"old_email": user.Email,
+		"new_email": request.NewEmail,
+	}).Info("Email change requested")
+
+	go func() {
+		confirmURL := fmt.Sprintf("https://alfian.my.id/accounts/email/change/confirm?token=%s", token)
+		err := email.QuickSendEmailChangeConfirmation(u.EmailService, request.NewEmail, user.Name, request.NewEmail, confirmURL)
+		if err != nil {
+			u.Log.WithField("action", "change email").WithError(err).Error("Failed to send email change confirmation email")
+		}
+
+		cancelURL := fmt.Sprintf("https://alfian.my.id/accounts/email/change/cancel?token=%s", change.CancelToken)
+		err = email.QuickSendEmailChangeRequested(u.EmailService, user.Email, user.Name, request.NewEmail, cancelURL)
+		if err != nil {
+			u.Log.WithField("action", "change email").WithError(err).Error("Failed to send email change notice email")
+		}
+	}()
+
+	return nil
+}
+
+func (u *accountUseCase) ConfirmEmailChange(ctx context.Context, request *model.ConfirmEmailChangeRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.StructPartial(request, "Token"); err != nil {
+		u.Log.WithField("action", "confirm email change").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	// GETDEL membuat link konfirmasi hanya bisa dipakai satu kali walaupun dipakai bersamaan
+	value, err := u.Redis.GetDel(ctx, "change_email:"+request.Token).Result()
+	if err != nil {
+		u.Log.WithField("action", "confirm email change").WithError(err).Warn("Failed to get email change token from redis")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid or expired email change token")
+	}
+
+	change := new(emailChange)
+	if err = json.Unmarshal([]byte(value), change); err != nil {
+		u.Log.WithField("action", "confirm email change").WithError(err).Error("Failed to decode email change")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// email baru bisa sudah dipakai akun lain sejak permintaan dibuat
+	request.NewEmail = change.NewEmail
+	if err = u.Validate.StructPartial(request, "NewEmail"); err != nil {
+		u.Log.WithField("action", "confirm email change").WithError(err).Warn("Failed to validate new email")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err = u.UserRepository.FindById(tx, user, change.UserID); err != nil {
+		u.Log.WithField("action", "confirm email change").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	// link konfirmasi dibuka dari alamat email baru, jadi email baru langsung dianggap terverifikasi
+	oldEmail := user.Email
+	verifiedAt := time.Now()
+	user.Email = change.NewEmail
+	user.EmailVerifiedAt = &verifiedAt
+	if err = u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "confirm email change").WithError(err).Error("Failed to update user email")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "confirm email change").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// token konfirmasi sudah dihapus oleh GETDEL, tinggal cancel token dan penanda permintaan milik user
+	if err = u.Redis.Del(ctx, "change_email_user:"+change.UserID, "change_email_cancel:"+change.CancelToken).Err(); err != nil {
+		u.Log.WithField("action", "confirm email change").WithError(err).Error("Failed to delete email change from redis")
+	}
+
+	u.Log.WithField("action", "confirm email change").WithFields(logrus.Fields{
+		"event":     "email_changed",
+		"user_id":   user.ID,
+		"old_email": oldEmail,
+		"new_email": user.Email,
+	}).Info("Email changed")
+
+	return converter.UserToResponse(user), nil
+}
+
+func (u *accountUseCase) CancelEmailChange(ctx context.Context, request *model.CancelEmailChangeRequest) error {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "cancel email change").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	token, err := u.Redis.Get(ctx, "change_email_cancel:"+request.Token).Result()
+	if err != nil {
+		u.Log.WithField("action", "cancel email change").WithError(err).Warn("Failed to get email change cancel token from redis")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid or expired email change cancel token")
+	}
+
+	value, err := u.Redis.Get(ctx, "change_email:"+token).Result()
+	if err != nil {
+		u.Log.WithField("action", "cancel email change").WithError(err).Warn("Failed to get email change token from redis")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid or expired email change cancel token")
+	}
+
+	change := new(emailChange)
+	if err = json.Unmarshal([]byte(value), change); err != nil {
+		u.Log.WithField("action", "cancel email change").WithError(err).Error("Failed to decode email change")
+		return fiber.ErrInternalServerError
+	}
+
+	if err = u.deleteEmailChange(ctx, change.UserID); err != nil {
+		u.Log.WithField("action", "cancel email change").WithError(err).Error("Failed to delete email change from redis")
+		return fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "cancel email change").WithFields(logrus.Fields{
+		"event":     "email_change_cancelled",
+		"user_id":   change.UserID,
+		"new_email": change.NewEmail,
+	}).Warn("Email change cancelled from old email address")
+
+	return nil
+}
+
+// deleteEmailChange menghapus permintaan perubahan email user yang masih menunggu konfirmasi beserta token pembatalannya
+func (u *accountUseCase) deleteEmailChange(ctx context.Context, userID string) error {
+	userKey := "change_email_user:" + userID
+	token, err := u.Redis.Get(ctx, userKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userKey, "change_email:" + token}
+	if value, err := u.Redis.Get(ctx, "change_email:"+token).Result(); err == nil {
+		change := new(emailChange)
+		if json.Unmarshal([]byte(value), change) == nil {
+			keys = append(keys, "change_email_cancel:"+change.CancelToken)
+		}
+	}
+
+	return u.Redis.Del(ctx, keys...).Err()
+}
+
+// Delete menjadwalkan penghapusan akun setelah account_deletion.grace_period_days, login sebelum waktu tersebut membatalkan penghapusan
+func (u *accountUseCase) Delete(ctx context.Context, request *model.DeleteAccountRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "delete account").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "delete account").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if user.DeletionScheduledAt != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Account deletion has already been scheduled")
+	}
+
+	gracePeriodDays := u.Config.GetInt("account_deletion.grace_period_days")
+	if gracePeriodDays <= 0 {
+		gracePeriodDays = 30
+	}
+	deletionScheduledAt := time.Now().AddDate(0, 0, gracePeriodDays)
+	user.DeletionScheduledAt = &deletionScheduledAt
+
+	if err := u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "delete account").WithError(err).Error("Failed to schedule account deletion")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "delete account").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := u.JwtService.InvalidateTokens(ctx, user.ID, ""); err != nil {
+		u.Log.WithField("action", "delete account").WithError(err).Error("Failed to invalidate user tokens")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "delete account").WithFields(logrus.Fields{
+		"event":                 "account_deletion_scheduled",
+		"user_id":               user.ID,
+		"deletion_scheduled_at": deletionScheduledAt,
+	}).Info("Account deletion scheduled")
+
+	go func() {
+		err := email.QuickSendAccountDeletionScheduled(u.EmailService, user.Email, user.Name, utils.FormatTime(deletionScheduledAt))
+		if err != nil {
+			u.Log.WithField("action", "delete account").WithError(err).Error("Failed to send account deletion scheduled email")
+		}
+	}()
+
+	return converter.UserToResponse(user), nil
+}
+
+// PurgeDeletedAccounts menghapus akun yang masa tenggangnya sudah lewat. Dengan account_deletion.mode "anonymize" data
+// personal dikosongkan dan row di soft delete, selain itu row dihapus permanen beserta relasinya
+func (u *accountUseCase) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	users, err := u.UserRepository.FindDeletionDue(u.DB.WithContext(ctx), time.Now(), 100)
+	if err != nil {
+		u.Log.WithField("action", "purge deleted accounts").WithError(err).Error("Failed to find accounts scheduled for deletion")
+		return 0, fiber.ErrInternalServerError
+	}
+
+	purged := 0
+	for i := range users {
+		ok, err := u.purgeAccount(ctx, users[i].ID)
+		if err != nil {
+			u.Log.WithField("action", "purge deleted accounts").WithField("user_id", users[i].ID).WithError(err).Error("Failed to purge account")
+			continue
+		}
+		if ok {
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// purgeAccount mengembalikan false tanpa error jika penghapusan sudah dibatalkan setelah daftar akun diambil
+func (u *accountUseCase) purgeAccount(ctx context.Context, id uuid.UUID) (bool, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	// row dibaca ulang dan dikunci agar login yang membatalkan penghapusan tidak balapan dengan purge
+	user := new(entity.User)
+	if err := u.UserRepository.LockDeletionDue(tx, user, id, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.Log.WithField("action", "purge deleted accounts").WithField("user_id", id).Info("Account deletion was cancelled, skipping purge")
+			return false, nil
+		}
+		return false, err
+	}
+
+	// data untuk email dan file avatar disimpan sebelum row dikosongkan atau dihapus
+	name, to, avatar := user.Name, user.Email, user.Avatar
+
+	mode := u.Config.GetString("account_deletion.mode")
+	if mode == "anonymize" {
+		user.Name = "Deleted User"
+		user.Email = fmt.Sprintf("deleted-%s@deleted.invalid", user.ID)
+		user.EmailVerifiedAt = nil
+		user.Password = ""
+		user.PasswordChangedAt = nil
+		user.TwoFactorSecret = nil
+		user.TwoFactorEnabledAt = nil
+		user.Phone = nil
+		user.PhoneVerifiedAt = nil
+		user.Avatar = nil
+		user.IsActive = false
+		user.DeletionScheduledAt = nil
+
+		if err := u.UserRepository.Update(tx, user); err != nil {
+			return false, err
+		}
+		if err := u.UserRepository.DeleteRelations(tx, user); err != nil {
+			return false, err
+		}
+		if err := u.UserRepository.SoftDelete(tx, user); err != nil {
+			return false, err
+		}
+	} else {
+		mode = "delete"
+		if err := u.UserRepository.HardDelete(tx, user); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	if avatar != nil && u.Storage != nil {
+		if err := u.Storage.DeleteFile(*avatar); err != nil {
+			u.Log.WithField("action", "purge deleted accounts").WithField("user_id", user.ID).WithError(err).Warn("Failed to delete avatar file")
+		}
+	}
+
+	u.Log.WithField("action", "purge deleted accounts").WithFields(logrus.Fields{
+		"event":   "account_purged",
+		"user_id": user.ID,
+		"mode":    mode,
+	}).Info("Account purged")
+
+	if err := email.QuickSendAccountDeleted(u.EmailService, to, name); err != nil {
+		u.Log.WithField("action", "purge deleted accounts").WithField("user_id", user.ID).WithError(err).Error("Failed to send account deleted email")
+	}
+
+	return true, nil
+}
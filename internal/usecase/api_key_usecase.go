@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/model/converter"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ApiKeyPrefix membedakan API key dari JWT saat dikirim sebagai bearer token
+const ApiKeyPrefix = "pds_"
+
+type ApiKeyUseCase interface {
+	Create(ctx context.Context, request *model.CreateApiKeyRequest) (*model.CreateApiKeyResponse, error)
+	List(ctx context.Context, request *model.ListApiKeyRequest) ([]model.ApiKeyResponse, error)
+	Revoke(ctx context.Context, request *model.RevokeApiKeyRequest) error
+	Authenticate(ctx context.Context, key string, ip string) (*model.UserClaimToken, error)
+}
+
+type apiKeyUseCase struct {
+	*BaseUseCase
+	UserRepository   repository.UserRepository
+	ApiKeyRepository repository.ApiKeyRepository
+}
+
+func NewApiKeyUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, apiKeyRepository repository.ApiKeyRepository) ApiKeyUseCase {
+	return &apiKeyUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, ApiKeyRepository: apiKeyRepository}
+}
+
+func (u *apiKeyUseCase) Create(ctx context.Context, request *model.CreateApiKeyRequest) (*model.CreateApiKeyResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "create api key").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		u.Log.WithField("action", "create api key").WithError(err).Error("Failed to generate api key")
+		return nil, fiber.ErrInternalServerError
+	}
+	key := ApiKeyPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	apiKey := &entity.ApiKey{
+		UserID:  request.UserID,
+		Name:    request.Name,
+		Prefix:  key[:len(ApiKeyPrefix)+8],
+		KeyHash: utils.HashToken(key),
+		Scopes:  strings.Join(slices.Compact(slices.Sorted(slices.Values(request.Scopes))), ","),
+	}
+	if request.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, request.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+
+	if err := u.ApiKeyRepository.Create(tx, apiKey); err != nil {
+		u.Log.WithField("action", "create api key").WithError(err).Error("Failed to create api key")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "create api key").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.CreateApiKeyResponse{ApiKeyResponse: *converter.ApiKeyToResponse(apiKey), Key: key}, nil
+}
+
+func (u *apiKeyUseCase) List(ctx context.Context, request *model.ListApiKeyRequest) ([]model.ApiKeyResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "list api key").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	apiKeys, err := u.ApiKeyRepository.FindAllByUserId(tx, request.UserID)
+	if err != nil {
+		u.Log.WithField("action", "list api key").WithError(err).Error("Failed to find api keys")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "list api key").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.ApiKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		responses[i] = *converter.ApiKeyToResponse(&apiKey)
+	}
+
+	return responses, nil
+}
+
+func (u *apiKeyUseCase) Revoke(ctx context.Context, request *model.RevokeApiKeyRequest) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "revoke api key").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	apiKey := new(entity.ApiKey)
+	if err := u.ApiKeyRepository.FindByIdAndUserId(tx, apiKey, request.ID, request.UserID); err != nil {
+		u.Log.WithField("action", "revoke api key").WithError(err).Warn("Failed to find api key")
+		return fiber.NewError(fiber.StatusNotFound, "API key not found")
+	}
+
+	if apiKey.RevokedAt != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "API key has already been revoked")
+	}
+
+	revokedAt := time.Now()
+	apiKey.RevokedAt = &revokedAt
+	if err := u.ApiKeyRepository.Update(tx, apiKey); err != nil {
+		u.Log.WithField("action", "revoke api key").WithError(err).Error("Failed to revoke api key")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "revoke api key").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Authenticate mengubah API key menjadi claims dengan bentuk yang sama seperti access token (Type "api_key"),
+// RegisteredClaims.ID berisi id API key
+func (u *apiKeyUseCase) Authenticate(ctx context.Context, key string, ip string) (*model.UserClaimToken, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	apiKey := new(entity.ApiKey)
+	if err := u.ApiKeyRepository.FindActiveByHash(tx, apiKey, utils.HashToken(key)); err != nil {
+		u.Log.WithField("action", "authenticate api key").WithError(err).Warn("Failed to find active api key")
+		return nil, fiber.ErrUnauthorized
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, apiKey.UserID); err != nil {
+		u.Log.WithField("action", "authenticate api key").WithError(err).Warn("Failed to find user of api key")
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if err := checkAccountStatus(user); err != nil {
+		u.Log.WithField("action", "authenticate api key").WithField("user_id", user.ID).WithError(err).Warn("API key owner is not active")
+		return nil, err
+	}
+
+	// penghapusan akun hanya bisa dibatalkan dengan login, API key tidak dipakai selama masa tenggang
+	if user.DeletionScheduledAt != nil {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Your account is scheduled for deletion, log in to cancel it")
+	}
+
+	// last used cukup akurat per menit, supaya tidak menulis ke database di setiap request
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > time.Minute || apiKey.LastUsedIP == nil || *apiKey.LastUsedIP != ip {
+		if err := u.ApiKeyRepository.TouchLastUsed(tx, apiKey.ID, now, ip); err != nil {
+			u.Log.WithField("action", "authenticate api key").WithError(err).Error("Failed to update api key last used")
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "authenticate api key").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	claims := &model.UserClaimToken{
+		ID:     user.ID,
+		Role:   user.Role,
+		Type:   "api_key",
+		Scopes: strings.Split(apiKey.Scopes, ","),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID: apiKey.ID.String(),
+		},
+	}
+	if apiKey.ExpiresAt != nil {
+		claims.ExpiresAt = jwt.NewNumericDate(*apiKey.ExpiresAt)
+	}
+
+	return claims, nil
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"github.com/alfianyulianto/pds-service/internal/entity"
 	"github.com/alfianyulianto/pds-service/internal/model"
@@ -10,10 +12,15 @@ import (
 	"github.com/alfianyulianto/pds-service/internal/utils"
 	"github.com/alfianyulianto/pds-service/pkg/auth"
 	"github.com/alfianyulianto/pds-service/pkg/email"
+	"github.com/alfianyulianto/pds-service/pkg/password"
+	"github.com/alfianyulianto/pds-service/pkg/telegram"
+	"github.com/alfianyulianto/pds-service/pkg/throttle"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
@@ -23,18 +30,36 @@ type AuthUseCase interface {
 	RefreshToken(ctx context.Context, request *model.VerifyUserRequest) (*model.AuthResponse, error)
 	RequestResetPassword(ctx context.Context, request *model.RequestPasswordResetRequest) error
 	ResetPassword(ctx context.Context, request *model.ResetPasswordRequest) error
+	VerifyTwoFactor(ctx context.Context, request *model.TwoFactorLoginRequest) (*model.AuthResponse, error)
+	VerifyEmail(ctx context.Context, request *model.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, request *model.ResendVerificationRequest) error
+	OIDCLogin(ctx context.Context, request *model.OIDCCallbackRequest) (*model.AuthResponse, error)
+	PasskeyLogin(ctx context.Context, request *model.PasskeyLoginRequest) (*model.AuthResponse, error)
+	RequestMagicLink(ctx context.Context, request *model.MagicLinkRequest) (*model.MagicLinkResponse, error)
+	ConsumeMagicLink(ctx context.Context, request *model.ConsumeMagicLinkRequest) (*model.AuthResponse, error)
+	RequestPhoneLoginCode(ctx context.Context, request *model.PhoneLoginCodeRequest) (*model.PhoneCodeResponse, error)
+	PhoneLogin(ctx context.Context, request *model.PhoneLoginRequest) (*model.AuthResponse, error)
 }
 
 type authUseCase struct {
 	*BaseUseCase
-	UserRepository repository.UserRepository
-	JwtService     *auth.JWTService
-	EmailService   *email.EmailService
-	Redis          *redis.Client
+	UserRepository             repository.UserRepository
+	JwtService                 *auth.JWTService
+	EmailService               *email.EmailService
+	Redis                      *redis.Client
+	TwoFactorUseCase           TwoFactorUseCase
+	Throttle                   *throttle.Throttle
+	Telegram                   *telegram.TelegramClient
+	IdentityUseCase            IdentityUseCase
+	PasswordHistoryRepository  repository.PasswordHistoryRepository
+	PasskeyUseCase             PasskeyUseCase
+	LoginEventUseCase          LoginEventUseCase
+	RegistrationCodeRepository repository.RegistrationCodeRepository
+	PhoneUseCase               PhoneUseCase
 }
 
-func NewAuthUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, jwtService *auth.JWTService, emailService *email.EmailService, redis *redis.Client) AuthUseCase {
-	return &authUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, JwtService: jwtService, EmailService: emailService, Redis: redis}
+func NewAuthUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, jwtService *auth.JWTService, emailService *email.EmailService, redis *redis.Client, twoFactorUseCase TwoFactorUseCase, throttle *throttle.Throttle, telegram *telegram.TelegramClient, identityUseCase IdentityUseCase, passwordHistoryRepository repository.PasswordHistoryRepository, passkeyUseCase PasskeyUseCase, loginEventUseCase LoginEventUseCase, registrationCodeRepository repository.RegistrationCodeRepository, phoneUseCase PhoneUseCase) AuthUseCase {
+	return &authUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, JwtService: jwtService, EmailService: emailService, Redis: redis, TwoFactorUseCase: twoFactorUseCase, Throttle: throttle, Telegram: telegram, IdentityUseCase: identityUseCase, PasswordHistoryRepository: passwordHistoryRepository, PasskeyUseCase: passkeyUseCase, LoginEventUseCase: loginEventUseCase, RegistrationCodeRepository: registrationCodeRepository, PhoneUseCase: phoneUseCase}
 }
 
 func (u *authUseCase) Register(ctx context.Context, request *model.RegisterUserRequest) (*model.UserResponse, error) {
@@ -48,33 +73,88 @@ func (u *authUseCase) Register(ctx context.Context, request *model.RegisterUserR
 
 	user := converter.RegisterRequestToUser(request)
 
-	password, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	switch u.registrationMode() {
+	case registrationModeInviteCode:
+		code, err := u.consumeRegistrationCode(tx, request.InviteCode)
+		if err != nil {
+			return nil, err
+		}
+		user.RegistrationCodeID = &code.ID
+	case registrationModeApproval:
+		// akun bisa dipakai setelah disetujui admin lewat /api/admin/users/:id/approve
+		status := approvalStatusPending
+		user.ApprovalStatus = &status
+	}
+
+	hash, err := u.Hasher.Hash(user.Password)
 	if err != nil {
 		u.Log.WithField("action", "login").WithError(err).Error("Failed to hash password")
 		return nil, fiber.ErrInternalServerError
 	}
 
-	user.Password = string(password)
+	user.Password = hash
+	now := time.Now()
+	user.PasswordChangedAt = &now
 
 	if err = u.UserRepository.Create(tx, user); err != nil {
 		u.Log.WithField("action", "login").WithError(err).Error("Failed to register user")
 		return nil, fiber.ErrInternalServerError
 	}
 
+	if err = u.savePasswordHistory(tx, u.PasswordHistoryRepository, user); err != nil {
+		u.Log.WithField("action", "register").WithError(err).Error("Failed to save password history")
+		return nil, fiber.ErrInternalServerError
+	}
+
 	if err = tx.Commit().Error; err != nil {
 		u.Log.WithField("action", "login").WithError(err).Error("Failed to commit transaction")
 		return nil, fiber.ErrInternalServerError
 	}
 
-	go func() {
-		err = email.QuickSendWelcome(u.EmailService, user.Email, user.Name)
-	}()
+	if user.ApprovalPending() {
+		u.Log.WithField("action", "register").WithFields(logrus.Fields{
+			"event":   "registration_pending_approval",
+			"user_id": user.ID,
+			"email":   user.Email,
+		}).Info("Registration is waiting for approval")
+	}
+
+	if err = u.sendVerificationEmail(ctx, user); err != nil {
+		u.Log.WithField("action", "register").WithError(err).Error("Failed to issue email verification token")
+	}
 
 	return converter.UserToResponse(user), nil
 }
 
+// consumeRegistrationCode memakai satu kuota kode undangan registrasi, kode yang tidak ada, di revoke, kadaluarsa atau
+// kuotanya habis dijawab dengan pesan yang sama
+func (u *authUseCase) consumeRegistrationCode(tx *gorm.DB, inviteCode string) (*entity.RegistrationCode, error) {
+	if inviteCode == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invite code is required")
+	}
+
+	invalid := fiber.NewError(fiber.StatusBadRequest, "Invalid or expired invite code")
+	code := new(entity.RegistrationCode)
+	if err := u.RegistrationCodeRepository.FindByCodeHash(tx, code, utils.HashToken(normalizeRegistrationCode(inviteCode))); err != nil {
+		u.Log.WithField("action", "register").WithError(err).Warn("Failed to find registration code")
+		return nil, invalid
+	}
+
+	consumed, err := u.RegistrationCodeRepository.Consume(tx, code.ID)
+	if err != nil {
+		u.Log.WithField("action", "register").WithError(err).Error("Failed to consume registration code")
+		return nil, fiber.ErrInternalServerError
+	}
+	if !consumed {
+		u.Log.WithField("action", "register").WithField("code_id", code.ID).Warn("Registration code is no longer usable")
+		return nil, invalid
+	}
+
+	return code, nil
+}
+
 func (u *authUseCase) Login(ctx context.Context, request *model.LoginUserRequest) (*model.AuthResponse, error) {
-	device := ctx.Value("DeviceTypeKey").(string)
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
 
 	tx := u.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
@@ -84,43 +164,486 @@ func (u *authUseCase) Login(ctx context.Context, request *model.LoginUserRequest
 		return nil, err
 	}
 
+	userEmail := strings.ToLower(request.Email)
+	if err := u.checkThrottle(ctx, "login", userEmail, client.IPAddress); err != nil {
+		if lockedError := new(throttle.LockedError); errors.As(err, &lockedError) {
+			u.recordLogin(ctx, nil, userEmail, loginMethodPassword, loginFailureTooManyAttempts, client)
+		}
+		return nil, err
+	}
+
 	user := new(entity.User)
 	if err := u.UserRepository.FindByEmail(tx, user, request.Email); err != nil {
 		u.Log.WithField("action", "login").WithError(err).Error("Failed to find user by email")
-		return nil, fiber.ErrUnauthorized
+		u.recordLogin(ctx, nil, userEmail, loginMethodPassword, loginFailureUnknownEmail, client)
+		return nil, u.hitThrottle(ctx, "login", userEmail, client, nil, fiber.ErrUnauthorized)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+	if err := u.Hasher.Verify(user.Password, request.Password); err != nil {
 		u.Log.WithField("action", "login").WithError(err).Error("Invalid password")
+		u.recordLogin(ctx, user, userEmail, loginMethodPassword, loginFailureInvalidPassword, client)
+		return nil, u.hitThrottle(ctx, "login", userEmail, client, user, fiber.ErrUnauthorized)
+	}
+
+	// hash lama (algoritma atau parameter yang sudah tidak dipakai) diganti saat login, di luar transaksi supaya tetap
+	// tersimpan walaupun login masih menunggu verifikasi 2FA
+	if u.Hasher.NeedsRehash(user.Password) {
+		if hash, err := u.Hasher.Hash(request.Password); err != nil {
+			u.Log.WithField("action", "login").WithError(err).Error("Failed to rehash password")
+		} else {
+			user.Password = hash
+			if err = u.UserRepository.Update(u.DB.WithContext(ctx), user); err != nil {
+				u.Log.WithField("action", "login").WithError(err).Error("Failed to save rehashed password")
+			}
+		}
+	}
+
+	if err := u.Throttle.Clear(ctx, "login_email", userEmail); err != nil {
+		u.Log.WithField("action", "login").WithError(err).Error("Failed to clear login attempts")
+	}
+
+	changedAt := user.CreatedAt
+	if user.PasswordChangedAt != nil {
+		changedAt = *user.PasswordChangedAt
+	}
+	if password.Expired(changedAt, u.Config.GetInt("password_policy.max_age_days")) {
+		u.Log.WithField("action", "login").WithFields(logrus.Fields{
+			"event":   "password_expired",
+			"user_id": user.ID,
+		}).Warn("Login rejected because password has expired")
+		u.recordLogin(ctx, user, userEmail, loginMethodPassword, loginFailurePasswordExpired, client)
+		return nil, fiber.NewError(fiber.StatusForbidden, "Password has expired, please reset your password")
+	}
+
+	return u.continueLogin(ctx, tx, user, client, "login")
+}
+
+func (u *authUseCase) OIDCLogin(ctx context.Context, request *model.OIDCCallbackRequest) (*model.AuthResponse, error) {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
+	user, err := u.IdentityUseCase.Authenticate(ctx, request)
+	if err != nil {
+		u.recordLogin(ctx, nil, "", loginMethodOIDC, loginFailureProviderFailed, client)
+		return nil, err
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	return u.continueLogin(ctx, tx, user, client, "oidc login")
+}
+
+// PasskeyLogin menyelesaikan login dengan passkey. Passkey dengan user verification (PIN/biometrik) sudah dianggap
+// multi-factor sehingga tidak diminta kode 2FA lagi
+func (u *authUseCase) PasskeyLogin(ctx context.Context, request *model.PasskeyLoginRequest) (*model.AuthResponse, error) {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
+	user, userVerified, err := u.PasskeyUseCase.Authenticate(ctx, request)
+	if err != nil {
+		u.recordLogin(ctx, user, "", loginMethodPasskey, loginFailureInvalidPasskey, client)
+		return nil, err
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if !userVerified {
+		return u.continueLogin(ctx, tx, user, client, "passkey login")
+	}
+
+	if err = u.checkLoginAllowed(ctx, user, client, "passkey login"); err != nil {
+		return nil, err
+	}
+
+	return u.completeLogin(ctx, tx, user, client, "passkey login")
+}
+
+func (u *authUseCase) RequestMagicLink(ctx context.Context, request *model.MagicLinkRequest) (*model.MagicLinkResponse, error) {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "request magic link").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	// setiap permintaan dihitung karena setiap permintaan mengirim email
+	userEmail := strings.ToLower(request.Email)
+	if err := u.checkThrottle(ctx, "magic_link", userEmail, client.IPAddress); err != nil {
+		return nil, err
+	}
+	if err := u.hitThrottle(ctx, "magic_link", userEmail, client, nil, nil); err != nil {
+		return nil, err
+	}
+
+	// link diikat ke perangkat dengan device token yang hanya diketahui perangkat peminta, yang disimpan hanya hash-nya.
+	// Device token tetap dibuat walaupun email tidak terdaftar supaya response tidak bisa dipakai untuk mencari email
+	response := new(model.MagicLinkResponse)
+	deviceHash := ""
+	if request.BindDevice || u.Config.GetBool("magic_link.require_device_binding") {
+		response.DeviceToken = uuid.NewString()
+		deviceHash = utils.HashToken(response.DeviceToken)
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindByEmail(tx, user, request.Email); err != nil {
+		u.Log.WithField("action", "request magic link").WithError(err).Warn("Failed to find user by email")
+		return response, nil
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "request magic link").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	expireDuration := u.Config.GetInt("magic_link.expire_duration")
+	if expireDuration <= 0 {
+		expireDuration = 900
+	}
+	expire := time.Duration(expireDuration) * time.Second
+
+	token, jti, err := u.JwtService.SignToken("magic_link", user.ID, expire)
+	if err != nil {
+		u.Log.WithField("action", "request magic link").WithError(err).Error("Failed to sign magic link token")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.Redis.SetEx(ctx, "magic_link:"+jti, deviceHash, expire).Err(); err != nil {
+		u.Log.WithField("action", "request magic link").WithError(err).Error("Failed to set magic link token in redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	go func() {
+		loginURL := fmt.Sprintf("https://alfian.my.id/accounts/magic-link?token=%s", token)
+		err = email.QuickSendMagicLink(u.EmailService, user.Email, user.Name, loginURL, fmt.Sprintf("%d menit", expireDuration/60))
+		if err != nil {
+			u.Log.WithField("action", "request magic link").WithError(err).Error("Failed to send magic link email")
+		}
+	}()
+
+	return response, nil
+}
+
+func (u *authUseCase) ConsumeMagicLink(ctx context.Context, request *model.ConsumeMagicLinkRequest) (*model.AuthResponse, error) {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "consume magic link").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	claims, err := u.JwtService.ParseToken(request.Token, "magic_link")
+	if err != nil {
+		u.Log.WithField("action", "consume magic link").WithError(err).Warn("Failed to parse magic link token")
+		u.recordLogin(ctx, nil, "", loginMethodMagicLink, loginFailureInvalidMagicLink, client)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired magic link")
+	}
+
+	// GETDEL membuat link hanya bisa dipakai satu kali walaupun dipakai bersamaan
+	deviceHash, err := u.Redis.GetDel(ctx, "magic_link:"+claims.RegisteredClaims.ID).Result()
+	if err != nil {
+		u.Log.WithField("action", "consume magic link").WithError(err).Warn("Magic link token not found in redis")
+		u.LoginEventUseCase.Record(ctx, &claims.ID, "", loginMethodMagicLink, loginFailureInvalidMagicLink, client)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired magic link")
+	}
+
+	if deviceHash != "" && subtle.ConstantTimeCompare([]byte(deviceHash), []byte(utils.HashToken(request.DeviceToken))) != 1 {
+		u.Log.WithField("action", "consume magic link").WithFields(logrus.Fields{
+			"event":      "magic_link_device_mismatch",
+			"user_id":    claims.ID,
+			"ip_address": client.IPAddress,
+			"user_agent": client.UserAgent,
+		}).Warn("Security event: magic link used from another device")
+		u.LoginEventUseCase.Record(ctx, &claims.ID, "", loginMethodMagicLink, loginFailureDeviceMismatch, client)
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Magic link must be opened on the device that requested it")
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err = u.UserRepository.FindById(tx, user, claims.ID); err != nil {
+		u.Log.WithField("action", "consume magic link").WithError(err).Error("Failed to find user")
 		return nil, fiber.ErrUnauthorized
 	}
 
+	// link dikirim ke email user, berarti email tersebut terbukti miliknya. Disimpan di luar transaksi login karena jika
+	// 2FA aktif continueLogin hanya mengembalikan challenge tanpa commit
+	if user.EmailVerifiedAt == nil {
+		verifiedAt := time.Now()
+		user.EmailVerifiedAt = &verifiedAt
+		if err = u.UserRepository.Update(u.DB.WithContext(ctx), user); err != nil {
+			u.Log.WithField("action", "consume magic link").WithError(err).Error("Failed to update user email verification")
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	return u.continueLogin(ctx, tx, user, client, "consume magic link")
+}
+
+// RequestPhoneLoginCode mengirim kode OTP login ke nomor yang sudah diverifikasi. Jeda kirim ulang dan throttle berlaku
+// untuk semua nomor sebelum nomor dicari, dan kegagalan mengirim SMS tidak dilaporkan, sehingga response nomor yang
+// terdaftar dan tidak terdaftar sama dan tidak bisa dipakai untuk mencari nomor yang terdaftar
+func (u *authUseCase) RequestPhoneLoginCode(ctx context.Context, request *model.PhoneLoginCodeRequest) (*model.PhoneCodeResponse, error) {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
+	if !u.Config.GetBool("phone.login_enabled") {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Phone login is not enabled")
+	}
+
+	u.normalizePhone(&request.Phone)
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "request phone login code").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	if err := u.PhoneUseCase.ReserveCode(ctx, phoneOTPLogin, request.Phone, request.Phone, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	response := &model.PhoneCodeResponse{Phone: request.Phone, ExpiresIn: int(u.phoneOTPExpire().Seconds())}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindByVerifiedPhone(u.DB.WithContext(ctx), user, request.Phone); err != nil {
+		u.Log.WithField("action", "request phone login code").WithError(err).Warn("Failed to find user by verified phone")
+		return response, nil
+	}
+
+	// kegagalan mengirim SMS sudah dicatat di DeliverCode, jeda kirim ulang tetap berlaku seperti nomor tidak terdaftar
+	_, _ = u.PhoneUseCase.DeliverCode(ctx, phoneOTPLogin, request.Phone, request.Phone)
+
+	return response, nil
+}
+
+func (u *authUseCase) PhoneLogin(ctx context.Context, request *model.PhoneLoginRequest) (*model.AuthResponse, error) {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
+	if !u.Config.GetBool("phone.login_enabled") {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Phone login is not enabled")
+	}
+
+	u.normalizePhone(&request.Phone)
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "phone login").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	if err := u.checkThrottle(ctx, "login", "", client.IPAddress); err != nil {
+		if lockedError := new(throttle.LockedError); errors.As(err, &lockedError) {
+			u.recordLogin(ctx, nil, "", loginMethodSMSOTP, loginFailureTooManyAttempts, client)
+		}
+		return nil, err
+	}
+
+	phoneNumber, err := u.PhoneUseCase.CheckCode(ctx, phoneOTPLogin, request.Phone, request.Code)
+	if err != nil {
+		u.recordLogin(ctx, nil, "", loginMethodSMSOTP, loginFailureInvalidSMSCode, client)
+		return nil, u.hitThrottle(ctx, "login", "", client, nil, err)
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	// nomor bisa saja dilepas dari akun setelah kode dikirim
+	user := new(entity.User)
+	if err = u.UserRepository.FindByVerifiedPhone(tx, user, phoneNumber); err != nil {
+		u.Log.WithField("action", "phone login").WithError(err).Warn("Failed to find user by verified phone")
+		u.recordLogin(ctx, nil, "", loginMethodSMSOTP, loginFailureInvalidSMSCode, client)
+		return nil, fiber.ErrUnauthorized
+	}
+
+	return u.continueLogin(ctx, tx, user, client, "phone login")
+}
+
+// continueLogin dipanggil setelah identitas user terbukti: jika 2FA aktif dikembalikan challenge token, jika tidak login diselesaikan
+func (u *authUseCase) continueLogin(ctx context.Context, tx *gorm.DB, user *entity.User, client *model.ClientInfo, action string) (*model.AuthResponse, error) {
+	if err := u.checkLoginAllowed(ctx, user, client, action); err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabledAt != nil {
+		challengeToken := uuid.NewString()
+		err := u.Redis.SetEx(ctx, "two_factor_challenge:"+challengeToken, user.ID.String(), 5*time.Minute).Err()
+		if err != nil {
+			u.Log.WithField("action", action).WithError(err).Error("Failed to set two factor challenge token in redis")
+			return nil, fiber.ErrInternalServerError
+		}
+
+		return &model.AuthResponse{TwoFactorRequired: true, ChallengeToken: challengeToken}, nil
+	}
+
+	return u.completeLogin(ctx, tx, user, client, action)
+}
+
+func (u *authUseCase) VerifyTwoFactor(ctx context.Context, request *model.TwoFactorLoginRequest) (*model.AuthResponse, error) {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "verify two factor").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	challengeKey := "two_factor_challenge:" + request.ChallengeToken
+	userID, err := u.Redis.Get(ctx, challengeKey).Result()
+	if err != nil {
+		u.Log.WithField("action", "verify two factor").WithError(err).Warn("Failed to get two factor challenge token from redis")
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired two-factor challenge")
+	}
+
+	attemptsKey := "two_factor_challenge_attempts:" + request.ChallengeToken
+	attempts, err := u.Redis.Incr(ctx, attemptsKey).Result()
+	if err != nil {
+		u.Log.WithField("action", "verify two factor").WithError(err).Error("Failed to count two factor attempts in redis")
+		return nil, fiber.ErrInternalServerError
+	}
+	u.Redis.Expire(ctx, attemptsKey, 5*time.Minute)
+
+	if attempts > 5 {
+		u.Redis.Del(ctx, challengeKey, attemptsKey)
+		u.Log.WithField("action", "verify two factor").WithField("user_id", userID).Warn("Too many two factor attempts")
+		if id, err := uuid.Parse(userID); err == nil {
+			u.LoginEventUseCase.Record(ctx, &id, "", loginMethodTwoFactor, loginFailureTooManyAttempts, client)
+		}
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Too many attempts, please login again")
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err = u.UserRepository.FindById(tx, user, userID); err != nil {
+		u.Log.WithField("action", "verify two factor").WithError(err).Error("Failed to find user")
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if err = u.checkLoginAllowed(ctx, user, client, "verify two factor"); err != nil {
+		return nil, err
+	}
+
+	// batas percobaan per challenge bisa dilewati dengan login ulang memakai password yang benar, sehingga kode yang
+	// salah juga dihitung per user dan tidak direset oleh login password
+	if err = u.Throttle.Check(ctx, "two_factor_user", user.ID.String()); err != nil {
+		if lockedError := new(throttle.LockedError); errors.As(err, &lockedError) {
+			u.Log.WithField("action", "verify two factor").WithField("user_id", user.ID).Warn("Request rejected, two factor is locked")
+			u.recordLogin(ctx, user, "", loginMethodTwoFactor, loginFailureTooManyAttempts, client)
+			return nil, err
+		}
+		u.Log.WithField("action", "verify two factor").WithError(err).Error("Failed to check throttle")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.TwoFactorUseCase.ValidateCode(ctx, user, request.Code); err != nil {
+		u.recordLogin(ctx, user, "", loginMethodTwoFactor, loginFailureInvalidTwoFactor, client)
+		if fiberError := new(fiber.Error); errors.As(err, &fiberError) && fiberError.Code == fiber.StatusUnauthorized {
+			return nil, u.hitTwoFactorThrottle(ctx, user, client, err)
+		}
+		return nil, err
+	}
+
+	if err = u.Throttle.Clear(ctx, "two_factor_user", user.ID.String()); err != nil {
+		u.Log.WithField("action", "verify two factor").WithError(err).Error("Failed to clear two factor attempts")
+	}
+
+	if err = u.Redis.Del(ctx, challengeKey, attemptsKey).Err(); err != nil {
+		u.Log.WithField("action", "verify two factor").WithError(err).Error("Failed to delete two factor challenge token from redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return u.completeLogin(ctx, tx, user, client, "verify two factor")
+}
+
+// checkLoginAllowed menolak login akun yang dinonaktifkan atau sedang di suspend
+func (u *authUseCase) checkLoginAllowed(ctx context.Context, user *entity.User, client *model.ClientInfo, action string) error {
+	if err := checkAccountStatus(user); err != nil {
+		u.Log.WithField("action", action).WithFields(logrus.Fields{
+			"event":   "login_rejected",
+			"user_id": user.ID,
+		}).WithError(err).Warn("Login rejected because account is not active")
+
+		// refresh token bukan login baru sehingga tidak dicatat di login history
+		if method, ok := loginMethods[action]; ok {
+			reason := loginFailureAccountSuspended
+			switch {
+			case user.InvitationPending():
+				reason = loginFailureInvitationPending
+			case user.ApprovalPending():
+				reason = loginFailureApprovalPending
+			case !user.IsActive:
+				reason = loginFailureAccountInactive
+			}
+			u.recordLogin(ctx, user, "", method, reason, client)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// loginMethods memetakan action login ke metode yang dicatat di login history
+var loginMethods = map[string]string{
+	"login":              loginMethodPassword,
+	"oidc login":         loginMethodOIDC,
+	"passkey login":      loginMethodPasskey,
+	"consume magic link": loginMethodMagicLink,
+	"phone login":        loginMethodSMSOTP,
+	"verify two factor":  loginMethodTwoFactor,
+}
+
+// recordLogin mencatat percobaan login ke login history, user nil jika akun tidak diketahui
+func (u *authUseCase) recordLogin(ctx context.Context, user *entity.User, email string, method string, failureReason string, client *model.ClientInfo) {
+	var userID *uuid.UUID
+	if user != nil {
+		userID = &user.ID
+		email = user.Email
+	}
+
+	u.LoginEventUseCase.Record(ctx, userID, email, method, failureReason, client)
+}
+
+// completeLogin menerbitkan access/refresh token, mencatat waktu login dan mengirim notifikasi login
+func (u *authUseCase) completeLogin(ctx context.Context, tx *gorm.DB, user *entity.User, client *model.ClientInfo, action string) (*model.AuthResponse, error) {
 	claims := model.UserClaimToken{
 		ID:   user.ID,
 		Role: user.Role,
 	}
-	token, err := u.JwtService.CreateToken(ctx, &claims)
+	token, err := u.JwtService.CreateToken(ctx, &claims, client)
 	if err != nil {
-		u.Log.WithField("action", "login").WithError(err).Error("Failed to create JWT token")
+		u.Log.WithField("action", action).WithError(err).Error("Failed to create JWT token")
 		return nil, fiber.ErrInternalServerError
 	}
 
 	lastLogIntAt := time.Now()
 	user.LastLoginAt = &lastLogIntAt
+
+	// login selama masa tenggang membatalkan penghapusan akun
+	deletionCancelled := user.DeletionScheduledAt != nil
+	user.DeletionScheduledAt = nil
+
 	if err = u.UserRepository.Update(tx, user); err != nil {
-		u.Log.WithField("action", "login").WithError(err).Error("Failed to update user last login")
+		u.Log.WithField("action", action).WithError(err).Error("Failed to update user last login")
 		return nil, fiber.ErrInternalServerError
 	}
 
 	if err = tx.Commit().Error; err != nil {
-		u.Log.WithField("action", "login").WithError(err).Error("Failed to commit transaction")
+		u.Log.WithField("action", action).WithError(err).Error("Failed to commit transaction")
 		return nil, fiber.ErrInternalServerError
 	}
 
+	u.recordLogin(ctx, user, "", loginMethods[action], "", client)
+
+	if deletionCancelled {
+		u.Log.WithField("action", action).WithFields(logrus.Fields{
+			"event":   "account_deletion_cancelled",
+			"user_id": user.ID,
+		}).Info("Account deletion cancelled by login")
+	}
+
 	go func() {
-		err = email.QuickSendLoginNotification(u.EmailService, user.Email, user.Name, utils.FormatTime(lastLogIntAt), device)
+		err = email.QuickSendLoginNotification(u.EmailService, user.Email, user.Name, utils.FormatTime(lastLogIntAt), client.Device)
 		if err != nil {
-			u.Log.WithField("action", "login").WithError(err).Error("Failed to send login notification email")
+			u.Log.WithField("action", action).WithError(err).Error("Failed to send login notification email")
 		}
 	}()
 
@@ -128,18 +651,42 @@ func (u *authUseCase) Login(ctx context.Context, request *model.LoginUserRequest
 }
 
 func (u *authUseCase) RefreshToken(ctx context.Context, request *model.VerifyUserRequest) (*model.AuthResponse, error) {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
 	if err := u.Validate.Struct(request); err != nil {
 		u.Log.WithField("action", "refresh token").WithError(err).Warn("Failed to validate request body")
 		return nil, err
 	}
 
 	claims, err := u.JwtService.ParseRefreshToken(ctx, request.Token)
+	if errors.Is(err, auth.ErrRefreshTokenReused) {
+		u.Log.WithField("action", "refresh token").WithFields(logrus.Fields{
+			"event":      "refresh_token_reuse",
+			"user_id":    claims.ID,
+			"session_id": claims.SessionID,
+			"jti":        claims.RegisteredClaims.ID,
+			"ip_address": client.IPAddress,
+			"user_agent": client.UserAgent,
+		}).Error("Security event: rotated refresh token was reused, token family revoked")
+		return nil, fiber.ErrUnauthorized
+	}
 	if err != nil {
-		u.Log.WithField("action", "refresh token").WithError(err).Error("Failed to parse access token")
+		u.Log.WithField("action", "refresh token").WithError(err).Error("Failed to parse refresh token")
 		return nil, fiber.ErrUnauthorized
 	}
 
-	token, err := u.JwtService.CreateToken(ctx, claims)
+	// role diambil ulang dari database supaya perubahan role oleh admin berlaku paling lambat saat token di refresh
+	user := new(entity.User)
+	if err = u.UserRepository.FindById(u.DB.WithContext(ctx), user, claims.ID); err != nil {
+		u.Log.WithField("action", "refresh token").WithError(err).Error("Failed to find user")
+		return nil, fiber.ErrUnauthorized
+	}
+
+	if err = u.checkLoginAllowed(ctx, user, client, "refresh token"); err != nil {
+		return nil, err
+	}
+
+	token, err := u.JwtService.CreateToken(ctx, &model.UserClaimToken{ID: user.ID, Role: user.Role, SessionID: claims.SessionID}, client)
 	if err != nil {
 		u.Log.WithField("action", "refresh token").WithError(err).Error("Failed to create new access token, from refresh token")
 		return nil, fiber.ErrInternalServerError
@@ -149,6 +696,8 @@ func (u *authUseCase) RefreshToken(ctx context.Context, request *model.VerifyUse
 }
 
 func (u *authUseCase) RequestResetPassword(ctx context.Context, request *model.RequestPasswordResetRequest) error {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
 	tx := u.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -157,6 +706,15 @@ func (u *authUseCase) RequestResetPassword(ctx context.Context, request *model.R
 		return err
 	}
 
+	// setiap permintaan dihitung (bukan hanya yang gagal) karena setiap permintaan mengirim email
+	userEmail := strings.ToLower(request.Email)
+	if err := u.checkThrottle(ctx, "reset_password", userEmail, client.IPAddress); err != nil {
+		return err
+	}
+	if err := u.hitThrottle(ctx, "reset_password", userEmail, client, nil, nil); err != nil {
+		return err
+	}
+
 	user := new(entity.User)
 	if err := u.UserRepository.FindByEmail(tx, user, request.Email); err != nil {
 		u.Log.WithField("action", "request reset password").WithError(err).Error("Failed to find user by email")
@@ -187,18 +745,25 @@ func (u *authUseCase) RequestResetPassword(ctx context.Context, request *model.R
 }
 
 func (u *authUseCase) ResetPassword(ctx context.Context, request *model.ResetPasswordRequest) error {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
 	tx := u.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
-	if err := u.Validate.Struct(request); err != nil {
+	// password baru divalidasi setelah user diketahui, supaya riwayat password bisa dicek
+	if err := u.Validate.StructExcept(request, "NewPassword"); err != nil {
 		u.Log.WithField("action", "reset password").WithError(err).Warn("Failed to validate request body")
 		return err
 	}
 
+	if err := u.checkThrottle(ctx, "reset_password", "", client.IPAddress); err != nil {
+		return err
+	}
+
 	userEmail, err := u.Redis.Get(ctx, "reset_password:"+request.Token).Result()
 	if err != nil {
 		u.Log.WithField("action", "reset password").WithError(err).Error("Failed to get reset password token from redis")
-		return fiber.NewError(fiber.StatusBadRequest, "Invalid or expired reset password token")
+		return u.hitThrottle(ctx, "reset_password", "", client, nil, fiber.NewError(fiber.StatusBadRequest, "Invalid or expired reset password token"))
 	}
 
 	user := new(entity.User)
@@ -207,23 +772,41 @@ func (u *authUseCase) ResetPassword(ctx context.Context, request *model.ResetPas
 		return fiber.NewError(fiber.StatusNotFound, "User data not found")
 	}
 
-	newPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	request.ID = user.ID
+	if err = u.Validate.StructPartial(request, "NewPassword"); err != nil {
+		u.Log.WithField("action", "reset password").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	newPassword, err := u.Hasher.Hash(request.NewPassword)
 	if err != nil {
 		u.Log.WithField("action", "reset password").WithError(err).Error("Failed to hash new password")
 		return fiber.ErrInternalServerError
 	}
-	user.Password = string(newPassword)
+	user.Password = newPassword
+	now := time.Now()
+	user.PasswordChangedAt = &now
 
 	if err = u.UserRepository.Update(tx, user); err != nil {
 		u.Log.WithField("action", "reset password").WithError(err).Error("Failed to update user password")
 		return fiber.ErrInternalServerError
 	}
 
+	if err = u.savePasswordHistory(tx, u.PasswordHistoryRepository, user); err != nil {
+		u.Log.WithField("action", "reset password").WithError(err).Error("Failed to save password history")
+		return fiber.ErrInternalServerError
+	}
+
 	if err = tx.Commit().Error; err != nil {
 		u.Log.WithField("action", "reset password").WithError(err).Error("Failed to commit transaction")
 		return fiber.ErrInternalServerError
 	}
 
+	if err = u.JwtService.InvalidateTokens(ctx, user.ID, ""); err != nil {
+		u.Log.WithField("action", "reset password").WithError(err).Error("Failed to invalidate user tokens")
+		return fiber.ErrInternalServerError
+	}
+
 	err = u.Redis.Del(ctx, "reset_password:"+request.Token).Err()
 	if err != nil {
 		u.Log.WithField("action", "reset password").WithError(err).Error("Failed to delete reset password token from redis")
@@ -239,3 +822,244 @@ func (u *authUseCase) ResetPassword(ctx context.Context, request *model.ResetPas
 
 	return nil
 }
+
+func (u *authUseCase) VerifyEmail(ctx context.Context, request *model.VerifyEmailRequest) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "verify email").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	userID, err := u.Redis.Get(ctx, "verify_email:"+request.Token).Result()
+	if err != nil {
+		u.Log.WithField("action", "verify email").WithError(err).Warn("Failed to get email verification token from redis")
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid or expired email verification token")
+	}
+
+	user := new(entity.User)
+	if err = u.UserRepository.FindById(tx, user, userID); err != nil {
+		u.Log.WithField("action", "verify email").WithError(err).Error("Failed to find user")
+		return fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	alreadyVerified := user.EmailVerifiedAt != nil
+	if !alreadyVerified {
+		verifiedAt := time.Now()
+		user.EmailVerifiedAt = &verifiedAt
+		if err = u.UserRepository.Update(tx, user); err != nil {
+			u.Log.WithField("action", "verify email").WithError(err).Error("Failed to update user email verification")
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "verify email").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	err = u.Redis.Del(ctx, "verify_email:"+request.Token, "verify_email_user:"+userID).Err()
+	if err != nil {
+		u.Log.WithField("action", "verify email").WithError(err).Error("Failed to delete email verification token from redis")
+		return fiber.ErrInternalServerError
+	}
+
+	if !alreadyVerified {
+		go func() {
+			err = email.QuickSendWelcome(u.EmailService, user.Email, user.Name)
+			if err != nil {
+				u.Log.WithField("action", "verify email").WithError(err).Error("Failed to send welcome email")
+			}
+		}()
+	}
+
+	return nil
+}
+
+// ResendVerification mengirim ulang email verifikasi. Response selalu sama walaupun email tidak terdaftar atau sudah
+// diverifikasi supaya tidak bisa dipakai untuk mencari email yang terdaftar
+func (u *authUseCase) ResendVerification(ctx context.Context, request *model.ResendVerificationRequest) error {
+	client := ctx.Value("ClientInfoKey").(*model.ClientInfo)
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "resend verification").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	// setiap permintaan dihitung karena setiap permintaan mengirim email
+	userEmail := strings.ToLower(request.Email)
+	if err := u.checkThrottle(ctx, "resend_verification", userEmail, client.IPAddress); err != nil {
+		return err
+	}
+	if err := u.hitThrottle(ctx, "resend_verification", userEmail, client, nil, nil); err != nil {
+		return err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindByEmail(tx, user, request.Email); err != nil {
+		u.Log.WithField("action", "resend verification").WithError(err).Warn("Failed to find user by email")
+		return nil
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "resend verification").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	if user.EmailVerifiedAt != nil {
+		u.Log.WithField("action", "resend verification").WithField("user_id", user.ID).Info("Email has already been verified")
+		return nil
+	}
+
+	if err := u.sendVerificationEmail(ctx, user); err != nil {
+		u.Log.WithField("action", "resend verification").WithError(err).Error("Failed to issue email verification token")
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// sendVerificationEmail menerbitkan token verifikasi baru (token lama otomatis tidak berlaku) lalu mengirim email verifikasi
+func (u *authUseCase) sendVerificationEmail(ctx context.Context, user *entity.User) error {
+	userKey := "verify_email_user:" + user.ID.String()
+	if oldToken, err := u.Redis.Get(ctx, userKey).Result(); err == nil {
+		if err = u.Redis.Del(ctx, "verify_email:"+oldToken).Err(); err != nil {
+			return err
+		}
+	}
+
+	token := uuid.NewString()
+	_, err := u.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetEx(ctx, "verify_email:"+token, user.ID.String(), 24*time.Hour)
+		pipe.SetEx(ctx, userKey, token, 24*time.Hour)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		verifyURL := fmt.Sprintf("https://alfian.my.id/accounts/email/verify?token=%s", token)
+		err := email.QuickSendVerification(u.EmailService, user.Email, user.Name, verifyURL)
+		if err != nil {
+			u.Log.WithField("action", "send verification email").WithError(err).Error("Failed to send verification email")
+		}
+	}()
+
+	return nil
+}
+
+// checkThrottle menolak request jika email atau IP sedang dikunci pada scope tersebut (rule <scope>_email dan <scope>_ip)
+func (u *authUseCase) checkThrottle(ctx context.Context, scope string, userEmail string, ip string) error {
+	subjects := map[string]string{scope + "_email": userEmail, scope + "_ip": ip}
+	for rule, subject := range subjects {
+		if subject == "" {
+			continue
+		}
+
+		err := u.Throttle.Check(ctx, rule, subject)
+		var lockedError *throttle.LockedError
+		if errors.As(err, &lockedError) {
+			u.Log.WithField("action", scope).WithFields(logrus.Fields{"rule": rule, "subject": subject}).Warn("Request rejected, subject is locked")
+			return err
+		}
+		if err != nil {
+			u.Log.WithField("action", scope).WithError(err).Error("Failed to check throttle")
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	return nil
+}
+
+// hitThrottle mencatat percobaan pada email dan IP. Jika salah satu terkunci karena percobaan ini maka *throttle.LockedError
+// dikembalikan, jika tidak maka fallback yang dikembalikan
+func (u *authUseCase) hitThrottle(ctx context.Context, scope string, userEmail string, client *model.ClientInfo, user *entity.User, fallback error) error {
+	var result error
+	subjects := map[string]string{scope + "_email": userEmail, scope + "_ip": client.IPAddress}
+	for rule, subject := range subjects {
+		if subject == "" {
+			continue
+		}
+
+		lockedError, err := u.Throttle.Hit(ctx, rule, subject)
+		if err != nil {
+			u.Log.WithField("action", scope).WithError(err).Error("Failed to record attempt")
+			continue
+		}
+
+		if lockedError == nil {
+			continue
+		}
+		result = lockedError
+
+		u.Log.WithField("action", scope).WithFields(logrus.Fields{
+			"event":       "lockout",
+			"rule":        rule,
+			"subject":     subject,
+			"ip_address":  client.IPAddress,
+			"retry_after": lockedError.Seconds(),
+		}).Warn("Security event: too many attempts, subject locked")
+
+		if rule == "login_email" {
+			u.notifyAccountLocked(userEmail, user, client, lockedError)
+		}
+	}
+
+	if result != nil {
+		return result
+	}
+
+	return fallback
+}
+
+// hitTwoFactorThrottle mencatat kode 2FA yang salah pada user. Jika user terkunci karena percobaan ini maka
+// *throttle.LockedError dikembalikan, jika tidak maka fallback yang dikembalikan
+func (u *authUseCase) hitTwoFactorThrottle(ctx context.Context, user *entity.User, client *model.ClientInfo, fallback error) error {
+	lockedError, err := u.Throttle.Hit(ctx, "two_factor_user", user.ID.String())
+	if err != nil {
+		u.Log.WithField("action", "verify two factor").WithError(err).Error("Failed to record attempt")
+		return fallback
+	}
+	if lockedError == nil {
+		return fallback
+	}
+
+	u.Log.WithField("action", "verify two factor").WithFields(logrus.Fields{
+		"event":       "lockout",
+		"rule":        "two_factor_user",
+		"subject":     user.ID,
+		"ip_address":  client.IPAddress,
+		"retry_after": lockedError.Seconds(),
+	}).Warn("Security event: too many attempts, subject locked")
+
+	u.notifyAccountLocked(user.Email, user, client, lockedError)
+
+	return lockedError
+}
+
+func (u *authUseCase) notifyAccountLocked(userEmail string, user *entity.User, client *model.ClientInfo, lockedError *throttle.LockedError) {
+	lockedUntil := utils.FormatTime(time.Now().Add(lockedError.RetryAfter))
+
+	if u.Config.GetBool("throttle.notify.telegram") {
+		go func() {
+			message := fmt.Sprintf("<b>🔒 %s - Account Locked</b>\n\n<b>Email:</b> %s\n<b>IP:</b> %s\n<b>Locked until:</b> %s",
+				u.Config.GetString("app.name"), userEmail, client.IPAddress, lockedUntil)
+			if err := u.Telegram.SendMessage(message); err != nil {
+				u.Log.WithField("action", "notify account locked").WithError(err).Warn("Failed to send account locked telegram notification")
+			}
+		}()
+	}
+
+	if u.Config.GetBool("throttle.notify.email") && user != nil {
+		go func() {
+			if err := email.QuickSendAccountLocked(u.EmailService, user.Email, user.Name, lockedUntil); err != nil {
+				u.Log.WithField("action", "notify account locked").WithError(err).Error("Failed to send account locked email")
+			}
+		}()
+	}
+}
@@ -0,0 +1,383 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/model/converter"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/pkg/email"
+	"github.com/alfianyulianto/pds-service/pkg/oidc"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type IdentityUseCase interface {
+	Authorize(ctx context.Context, request *model.OIDCAuthorizeRequest) (*model.OIDCAuthorizeResponse, error)
+	Authenticate(ctx context.Context, request *model.OIDCCallbackRequest) (*entity.User, error)
+	Link(ctx context.Context, request *model.OIDCCallbackRequest) (*model.IdentityResponse, error)
+	List(ctx context.Context, request *model.ListIdentityRequest) ([]model.IdentityResponse, error)
+	Unlink(ctx context.Context, request *model.UnlinkIdentityRequest) error
+}
+
+// oidcState disimpan di redis selama proses login di halaman provider, dipakai sekali lalu dihapus
+type oidcState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	UserID       string `json:"user_id,omitempty"` // diisi jika state dibuat untuk menautkan provider ke akun yang sedang login
+}
+
+type identityUseCase struct {
+	*BaseUseCase
+	UserRepository     repository.UserRepository
+	IdentityRepository repository.IdentityRepository
+	EmailService       *email.EmailService
+	Redis              *redis.Client
+	Providers          map[string]*oidc.Provider
+}
+
+func NewIdentityUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, identityRepository repository.IdentityRepository, emailService *email.EmailService, redis *redis.Client, providers map[string]*oidc.Provider) IdentityUseCase {
+	return &identityUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, IdentityRepository: identityRepository, EmailService: emailService, Redis: redis, Providers: providers}
+}
+
+func (u *identityUseCase) Authorize(ctx context.Context, request *model.OIDCAuthorizeRequest) (*model.OIDCAuthorizeResponse, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "oidc authorize").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	provider, ok := u.Providers[request.Provider]
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Provider not found")
+	}
+
+	state := &oidcState{Provider: request.Provider}
+	if request.UserID != uuid.Nil {
+		state.UserID = request.UserID.String()
+	}
+
+	stateToken, err := oidc.GenerateRandom()
+	if err == nil {
+		state.Nonce, err = oidc.GenerateRandom()
+	}
+	if err == nil {
+		state.CodeVerifier, err = oidc.GenerateRandom()
+	}
+	if err != nil {
+		u.Log.WithField("action", "oidc authorize").WithError(err).Error("Failed to generate state")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	authorizationURL, err := provider.AuthCodeURL(ctx, stateToken, state.Nonce, state.CodeVerifier)
+	if err != nil {
+		u.Log.WithField("action", "oidc authorize").WithField("provider", request.Provider).WithError(err).Error("Failed to build authorization url")
+		return nil, fiber.NewError(fiber.StatusBadGateway, "Provider is unavailable")
+	}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		u.Log.WithField("action", "oidc authorize").WithError(err).Error("Failed to encode state")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.Redis.SetEx(ctx, "oidc_state:"+stateToken, value, 10*time.Minute).Err(); err != nil {
+		u.Log.WithField("action", "oidc authorize").WithError(err).Error("Failed to set oidc state in redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.OIDCAuthorizeResponse{AuthorizationURL: authorizationURL, State: stateToken}, nil
+}
+
+// Authenticate menyelesaikan login lewat provider dan mengembalikan user yang terhubung. Jika identitas belum pernah
+// ditautkan, akun dengan email yang sama akan ditautkan (hanya jika email terverifikasi oleh provider dan akun lokal sudah
+// memverifikasi emailnya) atau akun baru dibuat
+func (u *identityUseCase) Authenticate(ctx context.Context, request *model.OIDCCallbackRequest) (*entity.User, error) {
+	info, err := u.exchange(ctx, request, "oidc login")
+	if err != nil {
+		return nil, err
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	identity := new(entity.Identity)
+	err = u.IdentityRepository.FindByProviderSubject(tx, identity, request.Provider, info.Subject)
+	if err == nil {
+		if err = u.UserRepository.FindById(tx, user, identity.UserID); err != nil {
+			u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to find user of identity")
+			return nil, fiber.ErrUnauthorized
+		}
+
+		if info.Email != "" {
+			identity.Email = &info.Email
+			if err = u.IdentityRepository.Update(tx, identity); err != nil {
+				u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to update identity")
+				return nil, fiber.ErrInternalServerError
+			}
+		}
+
+		if err = tx.Commit().Error; err != nil {
+			u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to commit transaction")
+			return nil, fiber.ErrInternalServerError
+		}
+
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to find identity")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if info.Email == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Provider did not return an email address")
+	}
+
+	created := false
+	err = u.UserRepository.FindByEmail(tx, user, info.Email)
+	switch {
+	case err == nil && (!info.EmailVerified || user.EmailVerifiedAt == nil):
+		// penautan otomatis hanya jika kedua sisi membuktikan kepemilikan email. Akun lokal yang emailnya belum
+		// terverifikasi bisa saja didaftarkan orang lain lebih dulu (pre-hijack) dengan password yang dia ketahui
+		u.Log.WithField("action", "oidc login").WithFields(logrus.Fields{
+			"event":                   "identity_link_refused",
+			"user_id":                 user.ID,
+			"provider":                request.Provider,
+			"provider_email_verified": info.EmailVerified,
+			"local_email_verified":    user.EmailVerifiedAt != nil,
+		}).Warn("Refused to link identity to existing account by email")
+		return nil, fiber.NewError(fiber.StatusConflict, "An account with this email already exists, login and link the provider from your account")
+	case err == nil:
+		u.Log.WithField("action", "oidc login").WithFields(logrus.Fields{"event": "identity_linked", "user_id": user.ID, "provider": request.Provider}).Info("Identity linked to existing account by verified email")
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if !info.EmailVerified {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Provider did not return a verified email address")
+		}
+
+		// provider tidak membawa kode undangan, akun baru harus dibuat lewat /api/auth/register
+		if u.registrationMode() == registrationModeInviteCode {
+			return nil, fiber.NewError(fiber.StatusForbidden, "Registration requires an invite code, register with your invite code first and link the provider from your account")
+		}
+
+		if user, err = u.newUser(info); err != nil {
+			u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to hash password")
+			return nil, fiber.ErrInternalServerError
+		}
+
+		if u.registrationMode() == registrationModeApproval {
+			status := approvalStatusPending
+			user.ApprovalStatus = &status
+		}
+
+		if err = u.UserRepository.Create(tx, user); err != nil {
+			u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to create user")
+			return nil, fiber.ErrInternalServerError
+		}
+		created = true
+	default:
+		u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to find user by email")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	identity = &entity.Identity{UserID: user.ID, Provider: request.Provider, Subject: info.Subject, Email: &info.Email}
+	if err = u.IdentityRepository.Create(tx, identity); err != nil {
+		u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to create identity")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// welcome email untuk akun yang menunggu persetujuan diganti email persetujuan dari admin
+	if created && !user.ApprovalPending() {
+		go func() {
+			err = email.QuickSendWelcome(u.EmailService, user.Email, user.Name)
+			if err != nil {
+				u.Log.WithField("action", "oidc login").WithError(err).Error("Failed to send welcome email")
+			}
+		}()
+	}
+
+	return user, nil
+}
+
+func (u *identityUseCase) Link(ctx context.Context, request *model.OIDCCallbackRequest) (*model.IdentityResponse, error) {
+	info, err := u.exchange(ctx, request, "link identity")
+	if err != nil {
+		return nil, err
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	identity := new(entity.Identity)
+	err = u.IdentityRepository.FindByProviderSubject(tx, identity, request.Provider, info.Subject)
+	if err == nil {
+		if identity.UserID != request.UserID {
+			return nil, fiber.NewError(fiber.StatusConflict, "This provider account is already linked to another user")
+		}
+
+		return converter.IdentityToResponse(identity), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		u.Log.WithField("action", "link identity").WithError(err).Error("Failed to find identity")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	identity = &entity.Identity{UserID: request.UserID, Provider: request.Provider, Subject: info.Subject}
+	if info.Email != "" {
+		identity.Email = &info.Email
+	}
+	if err = u.IdentityRepository.Create(tx, identity); err != nil {
+		u.Log.WithField("action", "link identity").WithError(err).Error("Failed to create identity")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "link identity").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "link identity").WithFields(logrus.Fields{"event": "identity_linked", "user_id": request.UserID, "provider": request.Provider}).Info("Identity linked")
+
+	return converter.IdentityToResponse(identity), nil
+}
+
+func (u *identityUseCase) List(ctx context.Context, request *model.ListIdentityRequest) ([]model.IdentityResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "list identity").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	identities, err := u.IdentityRepository.FindAllByUserId(tx, request.UserID)
+	if err != nil {
+		u.Log.WithField("action", "list identity").WithError(err).Error("Failed to find identities")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "list identity").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.IdentityResponse, len(identities))
+	for i, identity := range identities {
+		responses[i] = *converter.IdentityToResponse(&identity)
+	}
+
+	return responses, nil
+}
+
+func (u *identityUseCase) Unlink(ctx context.Context, request *model.UnlinkIdentityRequest) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "unlink identity").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	identity := new(entity.Identity)
+	if err := u.IdentityRepository.FindByIdAndUserId(tx, identity, request.ID, request.UserID); err != nil {
+		u.Log.WithField("action", "unlink identity").WithError(err).Warn("Failed to find identity")
+		return fiber.NewError(fiber.StatusNotFound, "Identity not found")
+	}
+
+	if err := u.IdentityRepository.HardDelete(tx, identity); err != nil {
+		u.Log.WithField("action", "unlink identity").WithError(err).Error("Failed to delete identity")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "unlink identity").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// exchange memakai state (sekali pakai) lalu menukar authorization code ke provider. State login tidak bisa dipakai untuk
+// menautkan akun dan sebaliknya
+func (u *identityUseCase) exchange(ctx context.Context, request *model.OIDCCallbackRequest, action string) (*oidc.UserInfo, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", action).WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	provider, ok := u.Providers[request.Provider]
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Provider not found")
+	}
+
+	value, err := u.Redis.GetDel(ctx, "oidc_state:"+request.State).Bytes()
+	if err != nil {
+		u.Log.WithField("action", action).WithError(err).Warn("Failed to get oidc state from redis")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid or expired state")
+	}
+
+	state := new(oidcState)
+	if err = json.Unmarshal(value, state); err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to decode oidc state")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid or expired state")
+	}
+
+	expectedUserID := ""
+	if request.UserID != uuid.Nil {
+		expectedUserID = request.UserID.String()
+	}
+	if state.Provider != request.Provider || state.UserID != expectedUserID {
+		u.Log.WithField("action", action).WithField("provider", request.Provider).Warn("OIDC state does not match request")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid or expired state")
+	}
+
+	info, err := provider.Exchange(ctx, request.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		u.Log.WithField("action", action).WithField("provider", request.Provider).WithError(err).Warn("Failed to exchange authorization code")
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Failed to authenticate with provider")
+	}
+
+	return info, nil
+}
+
+// newUser membuat user dari identitas provider dengan password acak, user tetap bisa memakai reset password untuk login dengan email
+func (u *identityUseCase) newUser(info *oidc.UserInfo) (*entity.User, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+
+	hash, err := u.Hasher.Hash(base64.RawURLEncoding.EncodeToString(random))
+	if err != nil {
+		return nil, err
+	}
+
+	name := info.Name
+	if name == "" {
+		name = strings.Split(info.Email, "@")[0]
+	}
+
+	verifiedAt := time.Now()
+	return &entity.User{
+		Name:            name,
+		Email:           info.Email,
+		EmailVerifiedAt: &verifiedAt,
+		Password:        hash,
+		IsActive:        true,
+	}, nil
+}
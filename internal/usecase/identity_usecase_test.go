@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/pkg/oidc"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// newTestIdentityUseCase membuat identityUseCase dengan redis palsu dan provider "mock" yang token endpoint-nya selalu
+// menolak code, jumlah request ke token endpoint dikembalikan untuk memastikan state diterima atau ditolak sebelum exchange
+func newTestIdentityUseCase(t *testing.T) (*identityUseCase, *atomic.Int32) {
+	t.Helper()
+
+	tokenRequests := new(atomic.Int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"error":"invalid_grant"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	provider := oidc.NewProvider("mock", oidc.ProviderConfig{
+		ClientID:              "test-client",
+		RedirectURL:           "https://app.example.com/callback",
+		AuthorizationEndpoint: server.URL + "/authorize",
+		TokenEndpoint:         server.URL + "/token",
+	})
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	baseUseCase := &BaseUseCase{Validate: validator.New(), Config: viper.New(), Log: logrus.NewEntry(log)}
+	useCase := &identityUseCase{BaseUseCase: baseUseCase, Redis: newFakeRedis(t), Providers: map[string]*oidc.Provider{"mock": provider}}
+
+	return useCase, tokenRequests
+}
+
+func TestIdentityExchangeState(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		authorize  model.OIDCAuthorizeRequest
+		callback   func(state string) model.OIDCCallbackRequest
+		reuseState bool
+		wantStatus int
+	}{
+		{
+			name:      "valid state",
+			authorize: model.OIDCAuthorizeRequest{Provider: "mock"},
+			callback: func(state string) model.OIDCCallbackRequest {
+				return model.OIDCCallbackRequest{Provider: "mock", Code: "code", State: state}
+			},
+			wantStatus: fiber.StatusUnauthorized,
+		},
+		{
+			name:      "wrong state",
+			authorize: model.OIDCAuthorizeRequest{Provider: "mock"},
+			callback: func(state string) model.OIDCCallbackRequest {
+				return model.OIDCCallbackRequest{Provider: "mock", Code: "code", State: state + "x"}
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:      "reused state",
+			authorize: model.OIDCAuthorizeRequest{Provider: "mock"},
+			callback: func(state string) model.OIDCCallbackRequest {
+				return model.OIDCCallbackRequest{Provider: "mock", Code: "code", State: state}
+			},
+			reuseState: true,
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:      "link state used for login",
+			authorize: model.OIDCAuthorizeRequest{Provider: "mock", UserID: userID},
+			callback: func(state string) model.OIDCCallbackRequest {
+				return model.OIDCCallbackRequest{Provider: "mock", Code: "code", State: state}
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:      "link state used by another user",
+			authorize: model.OIDCAuthorizeRequest{Provider: "mock", UserID: userID},
+			callback: func(state string) model.OIDCCallbackRequest {
+				return model.OIDCCallbackRequest{Provider: "mock", UserID: uuid.New(), Code: "code", State: state}
+			},
+			wantStatus: fiber.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			useCase, tokenRequests := newTestIdentityUseCase(t)
+			ctx := context.Background()
+
+			authorize, err := useCase.Authorize(ctx, &test.authorize)
+			if err != nil {
+				t.Fatalf("Authorize returned error: %v", err)
+			}
+
+			authorizationURL, err := url.Parse(authorize.AuthorizationURL)
+			if err != nil {
+				t.Fatalf("invalid authorization url: %v", err)
+			}
+			if got := authorizationURL.Query().Get("state"); got != authorize.State {
+				t.Fatalf("authorization url state = %q, want %q", got, authorize.State)
+			}
+
+			callback := test.callback(authorize.State)
+			if test.reuseState {
+				first := callback
+				_, _ = useCase.exchange(ctx, &first, "oidc login")
+				tokenRequests.Store(0)
+			}
+
+			_, err = useCase.exchange(ctx, &callback, "oidc login")
+
+			var fiberError *fiber.Error
+			if !errors.As(err, &fiberError) || fiberError.Code != test.wantStatus {
+				t.Fatalf("exchange error = %v, want status %d", err, test.wantStatus)
+			}
+
+			// state yang ditolak tidak boleh sampai ke token endpoint provider
+			wantTokenRequests := int32(0)
+			if test.wantStatus != fiber.StatusBadRequest {
+				wantTokenRequests = 1
+			}
+			if got := tokenRequests.Load(); got != wantTokenRequests {
+				t.Errorf("token endpoint requests = %d, want %d", got, wantTokenRequests)
+			}
+		})
+	}
+}
+
+// newFakeRedis menjalankan server RESP minimal di memori yang cukup untuk perintah yang dipakai use case di test
+func newFakeRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	var mu sync.Mutex
+	data := make(map[string]string)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRedis(conn, &mu, data)
+		}
+	}()
+
+	client := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), Protocol: 2, DisableIdentity: true})
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = listener.Close()
+	})
+
+	return client
+}
+
+func serveFakeRedis(conn net.Conn, mu *sync.Mutex, data map[string]string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		args, err := readRESPCommand(reader)
+		if err != nil {
+			return
+		}
+
+		mu.Lock()
+		reply := fakeRedisCommand(data, args)
+		mu.Unlock()
+
+		if _, err = io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func fakeRedisCommand(data map[string]string, args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "SET":
+		data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "SETEX":
+		data[args[1]] = args[3]
+		return "+OK\r\n"
+	case "GET", "GETDEL":
+		value, ok := data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		if strings.EqualFold(args[0], "GETDEL") {
+			delete(data, args[1])
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "DEL":
+		deleted := 0
+		for _, key := range args[1:] {
+			if _, ok := data[key]; ok {
+				delete(data, key)
+				deleted++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", deleted)
+	}
+
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+func readRESPCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected resp line %q", line)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		if line, err = reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+
+		value := make([]byte, size+2)
+		if _, err = io.ReadFull(reader, value); err != nil {
+			return nil, err
+		}
+		args[i] = string(value[:size])
+	}
+
+	return args, nil
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/pkg/auth"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+type ImpersonationUseCase interface {
+	Start(ctx context.Context, request *model.ImpersonateRequest) (*model.AuthResponse, error)
+	Stop(ctx context.Context, claims *model.UserClaimToken) error
+}
+
+type impersonationUseCase struct {
+	*BaseUseCase
+	UserRepository          repository.UserRepository
+	ImpersonationRepository repository.ImpersonationRepository
+	RoleUseCase             RoleUseCase
+	JwtService              *auth.JWTService
+}
+
+func NewImpersonationUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, impersonationRepository repository.ImpersonationRepository, roleUseCase RoleUseCase, jwtService *auth.JWTService) ImpersonationUseCase {
+	return &impersonationUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, ImpersonationRepository: impersonationRepository, RoleUseCase: roleUseCase, JwtService: jwtService}
+}
+
+func (u *impersonationUseCase) Start(ctx context.Context, request *model.ImpersonateRequest) (*model.AuthResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "start impersonation").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	if request.ID == request.ImpersonatorID {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "You cannot impersonate yourself")
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "start impersonation").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	// admin lain (role Admin atau role yang juga boleh impersonate) tidak boleh di impersonate
+	permissions, err := u.RoleUseCase.Permissions(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == "Admin" || slices.Contains(permissions, "users.impersonate") {
+		u.Log.WithField("action", "start impersonation").WithFields(logrus.Fields{
+			"event":           "impersonation_denied",
+			"impersonator_id": request.ImpersonatorID,
+			"user_id":         user.ID,
+		}).Warn("Attempt to impersonate another administrator")
+		return nil, fiber.NewError(fiber.StatusForbidden, "You cannot impersonate another administrator")
+	}
+
+	expireDuration := u.Config.GetInt("impersonation.expire_duration")
+	if expireDuration <= 0 {
+		expireDuration = 900
+	}
+	expire := time.Duration(expireDuration) * time.Second
+
+	impersonation := &entity.Impersonation{
+		ImpersonatorID: request.ImpersonatorID,
+		UserID:         user.ID,
+		ExpiresAt:      time.Now().Add(expire),
+	}
+	if request.Reason != "" {
+		impersonation.Reason = &request.Reason
+	}
+	if request.IPAddress != "" {
+		impersonation.IPAddress = &request.IPAddress
+	}
+	if request.UserAgent != "" {
+		impersonation.UserAgent = &request.UserAgent
+	}
+
+	if err = u.ImpersonationRepository.Create(tx, impersonation); err != nil {
+		u.Log.WithField("action", "start impersonation").WithError(err).Error("Failed to create impersonation")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	token, err := u.JwtService.CreateImpersonationToken(ctx, &model.UserClaimToken{
+		ID:               user.ID,
+		Role:             user.Role,
+		Impersonator:     &request.ImpersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{ID: impersonation.ID.String()},
+	}, expire)
+	if err != nil {
+		u.Log.WithField("action", "start impersonation").WithError(err).Error("Failed to create impersonation token")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "start impersonation").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "start impersonation").WithFields(logrus.Fields{
+		"event":            "impersonation_started",
+		"impersonation_id": impersonation.ID,
+		"impersonator_id":  request.ImpersonatorID,
+		"user_id":          user.ID,
+		"reason":           request.Reason,
+		"ip_address":       request.IPAddress,
+	}).Info("Impersonation started")
+
+	return token, nil
+}
+
+func (u *impersonationUseCase) Stop(ctx context.Context, claims *model.UserClaimToken) error {
+	if claims.Impersonator == nil {
+		return fiber.NewError(fiber.StatusBadRequest, "You are not impersonating any user")
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.JwtService.RevokeToken(ctx, claims); err != nil {
+		u.Log.WithField("action", "stop impersonation").WithError(err).Error("Failed to revoke impersonation token")
+		return fiber.ErrInternalServerError
+	}
+
+	impersonation := new(entity.Impersonation)
+	if err := u.ImpersonationRepository.FindById(tx, impersonation, claims.RegisteredClaims.ID); err != nil {
+		u.Log.WithField("action", "stop impersonation").WithError(err).Error("Failed to find impersonation")
+		return fiber.NewError(fiber.StatusNotFound, "Impersonation data not found")
+	}
+
+	now := time.Now()
+	impersonation.EndedAt = &now
+	if err := u.ImpersonationRepository.Update(tx, impersonation); err != nil {
+		u.Log.WithField("action", "stop impersonation").WithError(err).Error("Failed to update impersonation")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "stop impersonation").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "stop impersonation").WithFields(logrus.Fields{
+		"event":            "impersonation_stopped",
+		"impersonation_id": impersonation.ID,
+		"impersonator_id":  impersonation.ImpersonatorID,
+		"user_id":          impersonation.UserID,
+	}).Info("Impersonation stopped")
+
+	return nil
+}
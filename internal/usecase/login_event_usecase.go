@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/model/converter"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// metode login yang dicatat di login history
+const (
+	loginMethodPassword  = "password"
+	loginMethodMagicLink = "magic_link"
+	loginMethodOIDC      = "oidc"
+	loginMethodPasskey   = "passkey"
+	loginMethodTwoFactor = "two_factor"
+	loginMethodSMSOTP    = "sms_otp"
+)
+
+// alasan login gagal yang dicatat di login history
+const (
+	loginFailureUnknownEmail      = "unknown_email"
+	loginFailureInvalidPassword   = "invalid_password"
+	loginFailureTooManyAttempts   = "too_many_attempts"
+	loginFailurePasswordExpired   = "password_expired"
+	loginFailureAccountInactive   = "account_inactive"
+	loginFailureAccountSuspended  = "account_suspended"
+	loginFailureInvalidTwoFactor  = "invalid_two_factor_code"
+	loginFailureInvalidMagicLink  = "invalid_magic_link"
+	loginFailureDeviceMismatch    = "device_mismatch"
+	loginFailureProviderFailed    = "provider_failed"
+	loginFailureInvalidPasskey    = "invalid_passkey"
+	loginFailureInvitationPending = "invitation_pending"
+	loginFailureApprovalPending   = "approval_pending"
+	loginFailureInvalidSMSCode    = "invalid_sms_code"
+)
+
+type LoginEventUseCase interface {
+	Record(ctx context.Context, userID *uuid.UUID, email string, method string, failureReason string, client *model.ClientInfo)
+	History(ctx context.Context, request *model.ListLoginHistoryRequest) ([]model.LoginEventResponse, *response.Pagination, error)
+	Search(ctx context.Context, request *model.SearchLoginEventRequest) ([]model.LoginEventResponse, *response.Pagination, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type loginEventUseCase struct {
+	*BaseUseCase
+	LoginEventRepository repository.LoginEventRepository
+}
+
+func NewLoginEventUseCase(baseUseCase *BaseUseCase, loginEventRepository repository.LoginEventRepository) LoginEventUseCase {
+	return &loginEventUseCase{BaseUseCase: baseUseCase, LoginEventRepository: loginEventRepository}
+}
+
+// Record mencatat percobaan login, failureReason kosong berarti login berhasil. Kegagalan mencatat hanya di log
+// supaya tidak menggagalkan login
+func (u *loginEventUseCase) Record(ctx context.Context, userID *uuid.UUID, email string, method string, failureReason string, client *model.ClientInfo) {
+	event := &entity.LoginEvent{
+		UserID:         userID,
+		Email:          optionalString(email, 100),
+		Method:         method,
+		Success:        failureReason == "",
+		FailureReason:  optionalString(failureReason, 50),
+		IPAddress:      client.IPAddress,
+		UserAgent:      optionalString(client.UserAgent, 255),
+		Browser:        optionalString(client.Browser, 50),
+		BrowserVersion: optionalString(client.BrowserVersion, 32),
+		OS:             optionalString(client.OS, 50),
+		OSVersion:      optionalString(client.OSVersion, 32),
+		Device:         client.Device,
+	}
+
+	if err := u.LoginEventRepository.Create(u.DB.WithContext(ctx), event); err != nil {
+		u.Log.WithField("action", "record login event").WithError(err).Error("Failed to record login event")
+	}
+}
+
+func (u *loginEventUseCase) History(ctx context.Context, request *model.ListLoginHistoryRequest) ([]model.LoginEventResponse, *response.Pagination, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "login history").WithError(err).Warn("Failed to validate request body")
+		return nil, nil, err
+	}
+
+	return u.Search(ctx, &model.SearchLoginEventRequest{UserID: request.UserID.String(), PaginationRequest: request.PaginationRequest})
+}
+
+func (u *loginEventUseCase) Search(ctx context.Context, request *model.SearchLoginEventRequest) ([]model.LoginEventResponse, *response.Pagination, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "search login event").WithError(err).Warn("Failed to validate request body")
+		return nil, nil, err
+	}
+
+	if request.Page <= 0 {
+		request.Page = 1
+	}
+	if request.PageSize <= 0 {
+		request.PageSize = 10
+	}
+
+	events, total, err := u.LoginEventRepository.FindAll(tx, request)
+	if err != nil {
+		u.Log.WithField("action", "search login event").WithError(err).Error("Failed to find login events")
+		return nil, nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "search login event").WithError(err).Error("Failed to commit transaction")
+		return nil, nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.LoginEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = *converter.LoginEventToResponse(&event)
+	}
+
+	return responses, response.ToPaginated(request.Page, request.PageSize, total), nil
+}
+
+// PurgeExpired menghapus login history yang lebih lama dari login_history.retention_days
+func (u *loginEventUseCase) PurgeExpired(ctx context.Context) (int64, error) {
+	retention := u.Config.GetInt("login_history.retention_days")
+	if retention <= 0 {
+		retention = 90
+	}
+
+	return u.LoginEventRepository.DeleteBefore(u.DB.WithContext(ctx), time.Now().AddDate(0, 0, -retention))
+}
+
+// optionalString mengubah string kosong menjadi nil dan memotong string sesuai panjang kolom
+func optionalString(value string, max int) *string {
+	if value == "" {
+		return nil
+	}
+
+	if len(value) > max {
+		value = strings.ToValidUTF8(value[:max], "")
+	}
+
+	return &value
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/model/converter"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/internal/utils"
+	"github.com/alfianyulianto/pds-service/pkg/auth"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+type OAuthClientUseCase interface {
+	Create(ctx context.Context, request *model.CreateOAuthClientRequest) (*model.CreateOAuthClientResponse, error)
+	List(ctx context.Context) ([]model.OAuthClientResponse, error)
+	Revoke(ctx context.Context, request *model.RevokeOAuthClientRequest) error
+	IssueToken(ctx context.Context, request *model.OAuthTokenRequest) (*model.OAuthTokenResponse, error)
+	CheckStatus(ctx context.Context, clientID string) error
+}
+
+type oauthClientUseCase struct {
+	*BaseUseCase
+	OAuthClientRepository repository.OAuthClientRepository
+	JwtService            *auth.JWTService
+}
+
+func NewOAuthClientUseCase(baseUseCase *BaseUseCase, oauthClientRepository repository.OAuthClientRepository, jwtService *auth.JWTService) OAuthClientUseCase {
+	return &oauthClientUseCase{BaseUseCase: baseUseCase, OAuthClientRepository: oauthClientRepository, JwtService: jwtService}
+}
+
+func (u *oauthClientUseCase) Create(ctx context.Context, request *model.CreateOAuthClientRequest) (*model.CreateOAuthClientResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "create oauth client").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	clientID := make([]byte, 16)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(clientID); err != nil {
+		u.Log.WithField("action", "create oauth client").WithError(err).Error("Failed to generate client id")
+		return nil, fiber.ErrInternalServerError
+	}
+	if _, err := rand.Read(secret); err != nil {
+		u.Log.WithField("action", "create oauth client").WithError(err).Error("Failed to generate client secret")
+		return nil, fiber.ErrInternalServerError
+	}
+	clientSecret := base64.RawURLEncoding.EncodeToString(secret)
+
+	client := &entity.OAuthClient{
+		ClientID:   hex.EncodeToString(clientID),
+		SecretHash: utils.HashToken(clientSecret),
+		Name:       request.Name,
+		Scopes:     strings.Join(slices.Compact(slices.Sorted(slices.Values(request.Scopes))), ","),
+		CreatedBy:  &request.CreatedBy,
+	}
+
+	if err := u.OAuthClientRepository.Create(tx, client); err != nil {
+		u.Log.WithField("action", "create oauth client").WithError(err).Error("Failed to create oauth client")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "create oauth client").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "create oauth client").WithFields(logrus.Fields{
+		"event":     "oauth_client_created",
+		"actor_id":  request.CreatedBy,
+		"client_id": client.ClientID,
+		"scopes":    client.Scopes,
+		"name":      client.Name,
+	}).Info("OAuth client created")
+
+	return &model.CreateOAuthClientResponse{OAuthClientResponse: *converter.OAuthClientToResponse(client), ClientSecret: clientSecret}, nil
+}
+
+func (u *oauthClientUseCase) List(ctx context.Context) ([]model.OAuthClientResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	clients, err := u.OAuthClientRepository.FindAll(tx)
+	if err != nil {
+		u.Log.WithField("action", "list oauth client").WithError(err).Error("Failed to find oauth clients")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "list oauth client").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.OAuthClientResponse, len(clients))
+	for i, client := range clients {
+		responses[i] = *converter.OAuthClientToResponse(&client)
+	}
+
+	return responses, nil
+}
+
+// Revoke menonaktifkan client, token yang sudah diterbitkan ikut ditolak karena status client dicek di setiap request
+func (u *oauthClientUseCase) Revoke(ctx context.Context, request *model.RevokeOAuthClientRequest) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "revoke oauth client").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	client := new(entity.OAuthClient)
+	if err := u.OAuthClientRepository.FindById(tx, client, request.ID); err != nil {
+		u.Log.WithField("action", "revoke oauth client").WithError(err).Warn("Failed to find oauth client")
+		return fiber.NewError(fiber.StatusNotFound, "OAuth client not found")
+	}
+
+	if client.RevokedAt != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "OAuth client has already been revoked")
+	}
+
+	revokedAt := time.Now()
+	client.RevokedAt = &revokedAt
+	if err := u.OAuthClientRepository.Update(tx, client); err != nil {
+		u.Log.WithField("action", "revoke oauth client").WithError(err).Error("Failed to revoke oauth client")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "revoke oauth client").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "revoke oauth client").WithFields(logrus.Fields{
+		"event":     "oauth_client_revoked",
+		"client_id": client.ClientID,
+	}).Info("OAuth client revoked")
+
+	return nil
+}
+
+// IssueToken menjalankan grant client_credentials (RFC 6749 section 4.4), error dikembalikan sebagai *model.OAuthError
+func (u *oauthClientUseCase) IssueToken(ctx context.Context, request *model.OAuthTokenRequest) (*model.OAuthTokenResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if request.GrantType == "" {
+		return nil, &model.OAuthError{Status: fiber.StatusBadRequest, Code: "invalid_request", Description: "grant_type is required"}
+	}
+
+	if request.GrantType != "client_credentials" {
+		return nil, &model.OAuthError{Status: fiber.StatusBadRequest, Code: "unsupported_grant_type", Description: "Only the client_credentials grant is supported"}
+	}
+
+	invalidClient := &model.OAuthError{Status: fiber.StatusUnauthorized, Code: "invalid_client", Description: "Client authentication failed"}
+	if request.ClientID == "" || request.ClientSecret == "" {
+		return nil, invalidClient
+	}
+
+	client := new(entity.OAuthClient)
+	if err := u.OAuthClientRepository.FindByClientId(tx, client, request.ClientID); err != nil {
+		u.Log.WithField("action", "issue oauth token").WithField("client_id", request.ClientID).WithError(err).Warn("Failed to find oauth client")
+		return nil, invalidClient
+	}
+
+	if subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(utils.HashToken(request.ClientSecret))) != 1 || client.RevokedAt != nil {
+		u.Log.WithField("action", "issue oauth token").WithFields(logrus.Fields{
+			"event":     "oauth_client_auth_failed",
+			"client_id": client.ClientID,
+			"revoked":   client.RevokedAt != nil,
+		}).Warn("OAuth client authentication failed")
+		return nil, invalidClient
+	}
+
+	// scope kosong berarti semua scope yang diizinkan untuk client, selain itu harus bagian dari scope client
+	allowed := strings.Split(client.Scopes, ",")
+	scopes := allowed
+	if requested := strings.Fields(request.Scope); len(requested) > 0 {
+		for _, scope := range requested {
+			if !slices.Contains(allowed, scope) {
+				return nil, &model.OAuthError{Status: fiber.StatusBadRequest, Code: "invalid_scope", Description: "Scope " + scope + " is not allowed for this client"}
+			}
+		}
+		scopes = slices.Compact(slices.Sorted(slices.Values(requested)))
+	}
+
+	expire := u.Config.GetInt("oauth.token_expire_duration")
+	if expire <= 0 {
+		expire = 3600
+	}
+
+	token, err := u.JwtService.CreateClientToken(ctx, client.ClientID, scopes, time.Duration(expire)*time.Second)
+	if err != nil {
+		u.Log.WithField("action", "issue oauth token").WithError(err).Error("Failed to create client token")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.OAuthClientRepository.TouchLastUsed(tx, client.ID, time.Now()); err != nil {
+		u.Log.WithField("action", "issue oauth token").WithError(err).Error("Failed to update oauth client last used")
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "issue oauth token").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "issue oauth token").WithFields(logrus.Fields{
+		"event":     "oauth_token_issued",
+		"client_id": client.ClientID,
+		"scopes":    strings.Join(scopes, " "),
+	}).Info("OAuth client token issued")
+
+	return &model.OAuthTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   expire,
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// CheckStatus memastikan client belum di revoke, dipakai AuthMiddleware di setiap request dengan token client
+func (u *oauthClientUseCase) CheckStatus(ctx context.Context, clientID string) error {
+	client := new(entity.OAuthClient)
+	if err := u.OAuthClientRepository.FindByClientId(u.DB.WithContext(ctx), client, clientID); err != nil {
+		u.Log.WithField("action", "check oauth client status").WithError(err).Warn("Failed to find oauth client")
+		return fiber.ErrUnauthorized
+	}
+
+	if client.RevokedAt != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "OAuth client has been revoked")
+	}
+
+	return nil
+}
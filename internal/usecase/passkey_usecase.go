@@ -0,0 +1,351 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/model/converter"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/pkg/webauthn"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+type PasskeyUseCase interface {
+	RegistrationOptions(ctx context.Context, request *model.PasskeyRegistrationOptionsRequest) (*webauthn.CreationOptions, error)
+	Register(ctx context.Context, request *model.RegisterPasskeyRequest) (*model.PasskeyResponse, error)
+	List(ctx context.Context, request *model.ListPasskeyRequest) ([]model.PasskeyResponse, error)
+	Delete(ctx context.Context, request *model.DeletePasskeyRequest) error
+	LoginOptions(ctx context.Context, request *model.PasskeyLoginOptionsRequest) (*webauthn.RequestOptions, error)
+	Authenticate(ctx context.Context, request *model.PasskeyLoginRequest) (*entity.User, bool, error)
+}
+
+type passkeyUseCase struct {
+	*BaseUseCase
+	UserRepository               repository.UserRepository
+	WebAuthnCredentialRepository repository.WebAuthnCredentialRepository
+	Redis                        *redis.Client
+	WebAuthn                     *webauthn.WebAuthn
+}
+
+func NewPasskeyUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, webAuthnCredentialRepository repository.WebAuthnCredentialRepository, redis *redis.Client, webAuthn *webauthn.WebAuthn) PasskeyUseCase {
+	return &passkeyUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, WebAuthnCredentialRepository: webAuthnCredentialRepository, Redis: redis, WebAuthn: webAuthn}
+}
+
+func (u *passkeyUseCase) RegistrationOptions(ctx context.Context, request *model.PasskeyRegistrationOptionsRequest) (*webauthn.CreationOptions, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "passkey registration options").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.UserID); err != nil {
+		u.Log.WithField("action", "passkey registration options").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	credentials, err := u.WebAuthnCredentialRepository.FindAllByUserId(tx, user.ID)
+	if err != nil {
+		u.Log.WithField("action", "passkey registration options").WithError(err).Error("Failed to find passkeys")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "passkey registration options").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// passkey yang sudah terdaftar tidak bisa didaftarkan ulang di authenticator yang sama
+	exclude := make([]webauthn.CredentialDescriptor, len(credentials))
+	for i, credential := range credentials {
+		exclude[i] = credentialDescriptor(&credential)
+	}
+
+	options, err := u.WebAuthn.NewCreationOptions(webauthn.User{
+		ID:          webauthn.EncodeID(user.ID[:]),
+		Name:        user.Email,
+		DisplayName: user.Name,
+	}, exclude)
+	if err != nil {
+		u.Log.WithField("action", "passkey registration options").WithError(err).Error("Failed to create registration options")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	timeout := time.Duration(u.WebAuthn.Config.Timeout) * time.Second
+	if err = u.Redis.SetEx(ctx, "webauthn_registration:"+user.ID.String(), options.Challenge, timeout).Err(); err != nil {
+		u.Log.WithField("action", "passkey registration options").WithError(err).Error("Failed to set registration challenge in redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return options, nil
+}
+
+func (u *passkeyUseCase) Register(ctx context.Context, request *model.RegisterPasskeyRequest) (*model.PasskeyResponse, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "register passkey").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	// GETDEL membuat challenge hanya bisa dipakai satu kali
+	challenge, err := u.Redis.GetDel(ctx, "webauthn_registration:"+request.UserID.String()).Result()
+	if err != nil {
+		u.Log.WithField("action", "register passkey").WithError(err).Warn("Failed to get registration challenge from redis")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid or expired passkey challenge")
+	}
+
+	verified, err := u.WebAuthn.VerifyRegistration(&request.Credential, challenge)
+	if err != nil {
+		u.Log.WithField("action", "register passkey").WithField("user_id", request.UserID).WithError(err).Warn("Failed to verify passkey registration")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Passkey registration could not be verified")
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	credential := &entity.WebAuthnCredential{
+		UserID:       request.UserID,
+		CredentialID: webauthn.EncodeID(verified.ID),
+		PublicKey:    verified.PublicKey,
+		Algorithm:    verified.Algorithm,
+		SignCount:    verified.SignCount,
+		Name:         request.Name,
+	}
+	if len(verified.Transports) > 0 {
+		transports := strings.Join(verified.Transports, ",")
+		credential.Transports = &transports
+	}
+	if aaguid, err := uuid.FromBytes(verified.AAGUID); err == nil && aaguid != uuid.Nil {
+		value := aaguid.String()
+		credential.AAGUID = &value
+	}
+
+	existing := new(entity.WebAuthnCredential)
+	if err = u.WebAuthnCredentialRepository.FindByCredentialId(tx, existing, credential.CredentialID); err == nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "Passkey has already been registered")
+	}
+
+	if err = u.WebAuthnCredentialRepository.Create(tx, credential); err != nil {
+		u.Log.WithField("action", "register passkey").WithError(err).Error("Failed to create passkey")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "register passkey").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "register passkey").WithFields(logrus.Fields{
+		"event":      "passkey_registered",
+		"user_id":    request.UserID,
+		"passkey_id": credential.ID,
+	}).Info("Passkey registered")
+
+	return converter.PasskeyToResponse(credential), nil
+}
+
+func (u *passkeyUseCase) List(ctx context.Context, request *model.ListPasskeyRequest) ([]model.PasskeyResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "list passkey").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	credentials, err := u.WebAuthnCredentialRepository.FindAllByUserId(tx, request.UserID)
+	if err != nil {
+		u.Log.WithField("action", "list passkey").WithError(err).Error("Failed to find passkeys")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "list passkey").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.PasskeyResponse, len(credentials))
+	for i, credential := range credentials {
+		responses[i] = *converter.PasskeyToResponse(&credential)
+	}
+
+	return responses, nil
+}
+
+func (u *passkeyUseCase) Delete(ctx context.Context, request *model.DeletePasskeyRequest) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "delete passkey").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	credential := new(entity.WebAuthnCredential)
+	if err := u.WebAuthnCredentialRepository.FindByIdAndUserId(tx, credential, request.ID, request.UserID); err != nil {
+		u.Log.WithField("action", "delete passkey").WithError(err).Warn("Failed to find passkey")
+		return fiber.NewError(fiber.StatusNotFound, "Passkey not found")
+	}
+
+	if err := u.WebAuthnCredentialRepository.HardDelete(tx, credential); err != nil {
+		u.Log.WithField("action", "delete passkey").WithError(err).Error("Failed to delete passkey")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "delete passkey").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "delete passkey").WithFields(logrus.Fields{
+		"event":      "passkey_deleted",
+		"user_id":    request.UserID,
+		"passkey_id": credential.ID,
+	}).Info("Passkey deleted")
+
+	return nil
+}
+
+// LoginOptions menerbitkan challenge login. Jika email diisi hanya passkey milik user tersebut yang diterima, email yang
+// tidak terdaftar tetap mendapat options supaya tidak bisa dipakai untuk mengecek email
+func (u *passkeyUseCase) LoginOptions(ctx context.Context, request *model.PasskeyLoginOptionsRequest) (*webauthn.RequestOptions, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "passkey login options").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	userID := ""
+	var allow []webauthn.CredentialDescriptor
+	if request.Email != "" {
+		userID = uuid.Nil.String()
+
+		user := new(entity.User)
+		if err := u.UserRepository.FindByEmail(tx, user, request.Email); err == nil {
+			userID = user.ID.String()
+
+			credentials, err := u.WebAuthnCredentialRepository.FindAllByUserId(tx, user.ID)
+			if err != nil {
+				u.Log.WithField("action", "passkey login options").WithError(err).Error("Failed to find passkeys")
+				return nil, fiber.ErrInternalServerError
+			}
+			for _, credential := range credentials {
+				allow = append(allow, credentialDescriptor(&credential))
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "passkey login options").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	options, err := u.WebAuthn.NewRequestOptions(allow)
+	if err != nil {
+		u.Log.WithField("action", "passkey login options").WithError(err).Error("Failed to create login options")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	timeout := time.Duration(u.WebAuthn.Config.Timeout) * time.Second
+	if err = u.Redis.SetEx(ctx, "webauthn_login:"+options.Challenge, userID, timeout).Err(); err != nil {
+		u.Log.WithField("action", "passkey login options").WithError(err).Error("Failed to set login challenge in redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return options, nil
+}
+
+// Authenticate memverifikasi assertion passkey dan mengembalikan pemilik passkey, bool bernilai true jika authenticator
+// melakukan user verification (PIN/biometrik) sehingga passkey sudah dianggap multi-factor
+func (u *passkeyUseCase) Authenticate(ctx context.Context, request *model.PasskeyLoginRequest) (*entity.User, bool, error) {
+	challenge, err := webauthn.ClientDataChallenge(request.Credential.Response.ClientDataJSON)
+	if err != nil {
+		return nil, false, fiber.NewError(fiber.StatusBadRequest, "Invalid passkey response")
+	}
+
+	// GETDEL membuat challenge hanya bisa dipakai satu kali
+	expectedUserID, err := u.Redis.GetDel(ctx, "webauthn_login:"+challenge).Result()
+	if err != nil {
+		u.Log.WithField("action", "passkey login").WithError(err).Warn("Failed to get login challenge from redis")
+		return nil, false, fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired passkey challenge")
+	}
+
+	rawID, err := webauthn.DecodeID(request.Credential.RawID)
+	if err != nil {
+		return nil, false, fiber.NewError(fiber.StatusBadRequest, "Invalid passkey response")
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	credential := new(entity.WebAuthnCredential)
+	if err = u.WebAuthnCredentialRepository.FindByCredentialId(tx, credential, webauthn.EncodeID(rawID)); err != nil {
+		u.Log.WithField("action", "passkey login").WithError(err).Warn("Failed to find passkey")
+		return nil, false, fiber.NewError(fiber.StatusUnauthorized, "Passkey is not registered")
+	}
+
+	if expectedUserID != "" && expectedUserID != credential.UserID.String() {
+		return nil, false, fiber.NewError(fiber.StatusUnauthorized, "Passkey is not registered")
+	}
+
+	if userHandle := request.Credential.Response.UserHandle; userHandle != "" {
+		handle, err := webauthn.DecodeID(userHandle)
+		if err != nil || string(handle) != string(credential.UserID[:]) {
+			return nil, false, fiber.NewError(fiber.StatusUnauthorized, "Passkey is not registered")
+		}
+	}
+
+	assertion, err := u.WebAuthn.VerifyAssertion(&request.Credential, challenge, credential.PublicKey, credential.SignCount)
+	if errors.Is(err, webauthn.ErrSignCountInvalid) {
+		u.Log.WithField("action", "passkey login").WithFields(logrus.Fields{
+			"event":      "passkey_clone_suspected",
+			"user_id":    credential.UserID,
+			"passkey_id": credential.ID,
+		}).Error("Security event: passkey sign counter did not increase")
+		return nil, false, fiber.NewError(fiber.StatusUnauthorized, "Passkey could not be verified")
+	}
+	if err != nil {
+		u.Log.WithField("action", "passkey login").WithField("passkey_id", credential.ID).WithError(err).Warn("Failed to verify passkey assertion")
+		return nil, false, fiber.NewError(fiber.StatusUnauthorized, "Passkey could not be verified")
+	}
+
+	lastUsedAt := time.Now()
+	credential.SignCount = assertion.SignCount
+	credential.LastUsedAt = &lastUsedAt
+	if err = u.WebAuthnCredentialRepository.Update(tx, credential); err != nil {
+		u.Log.WithField("action", "passkey login").WithError(err).Error("Failed to update passkey")
+		return nil, false, fiber.ErrInternalServerError
+	}
+
+	user := new(entity.User)
+	if err = u.UserRepository.FindById(tx, user, credential.UserID); err != nil {
+		u.Log.WithField("action", "passkey login").WithError(err).Error("Failed to find user")
+		return nil, false, fiber.ErrUnauthorized
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "passkey login").WithError(err).Error("Failed to commit transaction")
+		return nil, false, fiber.ErrInternalServerError
+	}
+
+	return user, assertion.UserVerified, nil
+}
+
+func credentialDescriptor(credential *entity.WebAuthnCredential) webauthn.CredentialDescriptor {
+	descriptor := webauthn.CredentialDescriptor{Type: "public-key", ID: credential.CredentialID}
+	if credential.Transports != nil && *credential.Transports != "" {
+		descriptor.Transports = strings.Split(*credential.Transports, ",")
+	}
+
+	return descriptor
+}
@@ -0,0 +1,379 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/model/converter"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/internal/utils"
+	"github.com/alfianyulianto/pds-service/pkg/sms"
+	"github.com/alfianyulianto/pds-service/pkg/throttle"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// tujuan kode OTP, kode untuk satu tujuan tidak bisa dipakai untuk tujuan lain
+const (
+	phoneOTPVerify = "verify"
+	phoneOTPLogin  = "login"
+)
+
+type PhoneUseCase interface {
+	SendVerificationCode(ctx context.Context, request *model.SendPhoneVerificationRequest) (*model.PhoneCodeResponse, error)
+	Verify(ctx context.Context, request *model.VerifyPhoneRequest) (*model.UserResponse, error)
+	SendCode(ctx context.Context, purpose string, subject string, phone string, ip string) (*model.PhoneCodeResponse, error)
+	ReserveCode(ctx context.Context, purpose string, subject string, phone string, ip string) error
+	DeliverCode(ctx context.Context, purpose string, subject string, phone string) (*model.PhoneCodeResponse, error)
+	CheckCode(ctx context.Context, purpose string, subject string, code string) (string, error)
+}
+
+type phoneUseCase struct {
+	*BaseUseCase
+	UserRepository repository.UserRepository
+	Redis          *redis.Client
+	Throttle       *throttle.Throttle
+	SMSProvider    sms.SMSProvider
+}
+
+func NewPhoneUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, redis *redis.Client, throttle *throttle.Throttle, smsProvider sms.SMSProvider) PhoneUseCase {
+	return &phoneUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, Redis: redis, Throttle: throttle, SMSProvider: smsProvider}
+}
+
+// phoneOTP disimpan di redis selama phone.otp_expire_duration, kode hanya disimpan hash-nya
+type phoneOTP struct {
+	Phone    string `json:"phone"`
+	CodeHash string `json:"code_hash"`
+}
+
+// SendVerificationCode mengirim kode OTP ke nomor baru (jika diisi) atau nomor yang sudah tersimpan. Nomor baru baru
+// disimpan ke akun setelah kode diverifikasi
+func (u *phoneUseCase) SendVerificationCode(ctx context.Context, request *model.SendPhoneVerificationRequest) (*model.PhoneCodeResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	u.normalizePhone(request.Phone)
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "send phone verification").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.UserID); err != nil {
+		u.Log.WithField("action", "send phone verification").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	phoneNumber := request.Phone
+	if phoneNumber == nil || *phoneNumber == "" {
+		// nomor lama yang tersimpan sebelum ada normalisasi E.164 dinormalisasi dulu
+		phoneNumber = user.Phone
+		u.normalizePhone(phoneNumber)
+	}
+	if phoneNumber == nil || *phoneNumber == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Phone number is required")
+	}
+	if err := u.Validate.Var(*phoneNumber, "e164"); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Phone number is not a valid phone number")
+	}
+
+	if user.PhoneVerifiedAt != nil && samePhone(user.Phone, phoneNumber) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Phone number is already verified")
+	}
+
+	if err := u.checkPhoneAvailable(tx, user, *phoneNumber); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "send phone verification").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return u.SendCode(ctx, phoneOTPVerify, user.ID.String(), *phoneNumber, request.IPAddress)
+}
+
+func (u *phoneUseCase) Verify(ctx context.Context, request *model.VerifyPhoneRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "verify phone").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	phoneNumber, err := u.CheckCode(ctx, phoneOTPVerify, request.UserID.String(), request.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err = u.UserRepository.FindById(tx, user, request.UserID); err != nil {
+		u.Log.WithField("action", "verify phone").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	// nomor bisa sudah diverifikasi akun lain sejak kode dikirim
+	if err = u.checkPhoneAvailable(tx, user, phoneNumber); err != nil {
+		return nil, err
+	}
+
+	verifiedAt := time.Now()
+	user.Phone = &phoneNumber
+	user.PhoneVerifiedAt = &verifiedAt
+	if err = u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "verify phone").WithError(err).Error("Failed to update user phone")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "verify phone").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "verify phone").WithFields(logrus.Fields{
+		"event":   "phone_verified",
+		"user_id": user.ID,
+		"phone":   phoneNumber,
+	}).Info("Phone number verified")
+
+	return converter.UserToResponse(user), nil
+}
+
+// SendCode membuat kode OTP 6 digit untuk subject (id user atau nomor telepon) dan mengirimnya lewat SMS. Pengiriman
+// dibatasi dengan jeda phone.otp_resend_interval dan throttle sms_otp_phone/sms_otp_ip, kode sebelumnya tidak berlaku lagi
+func (u *phoneUseCase) SendCode(ctx context.Context, purpose string, subject string, phone string, ip string) (*model.PhoneCodeResponse, error) {
+	if err := u.ReserveCode(ctx, purpose, subject, phone, ip); err != nil {
+		return nil, err
+	}
+
+	response, err := u.DeliverCode(ctx, purpose, subject, phone)
+	if err != nil {
+		// kode gagal dikirim sehingga user boleh langsung meminta ulang
+		u.Redis.Del(ctx, phoneOTPKey(purpose, subject)+":cooldown")
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// ReserveCode memeriksa jeda phone.otp_resend_interval dan throttle sms_otp_phone/sms_otp_ip, lalu memulai jeda baru.
+// Dipisah dari DeliverCode supaya batas yang sama bisa diterapkan sebelum diketahui apakah nomor terdaftar
+func (u *phoneUseCase) ReserveCode(ctx context.Context, purpose string, subject string, phone string, ip string) error {
+	action := "send phone " + purpose + " code"
+	key := phoneOTPKey(purpose, subject)
+
+	resendInterval := u.Config.GetInt("phone.otp_resend_interval")
+	if resendInterval <= 0 {
+		resendInterval = 60
+	}
+	cooldown, err := u.Redis.TTL(ctx, key+":cooldown").Result()
+	if err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to check phone code cooldown in redis")
+		return fiber.ErrInternalServerError
+	}
+	if cooldown > 0 {
+		return &throttle.LockedError{RetryAfter: cooldown}
+	}
+
+	if err = u.hitSMSThrottle(ctx, action, phone, ip); err != nil {
+		return err
+	}
+
+	if err = u.Redis.SetEx(ctx, key+":cooldown", 1, time.Duration(resendInterval)*time.Second).Err(); err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to set phone code cooldown in redis")
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// DeliverCode membuat kode OTP baru dan mengirimnya lewat SMS tanpa memeriksa batas pengiriman, panggil ReserveCode
+// lebih dulu. Jeda kirim ulang tetap berlaku walaupun SMS gagal dikirim
+func (u *phoneUseCase) DeliverCode(ctx context.Context, purpose string, subject string, phone string) (*model.PhoneCodeResponse, error) {
+	action := "send phone " + purpose + " code"
+	key := phoneOTPKey(purpose, subject)
+
+	code, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to generate phone code")
+		return nil, fiber.ErrInternalServerError
+	}
+	otp := fmt.Sprintf("%06d", code.Int64())
+
+	value, err := json.Marshal(&phoneOTP{Phone: phone, CodeHash: utils.HashToken(otp)})
+	if err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to encode phone code")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	expire := u.phoneOTPExpire()
+	_, err = u.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SetEx(ctx, key, value, expire)
+		pipe.Del(ctx, key+":attempts")
+		return nil
+	})
+	if err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to set phone code in redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	message := fmt.Sprintf("Kode verifikasi %s Anda: %s. Berlaku %d menit. Jangan berikan kode ini kepada siapa pun.", u.Config.GetString("app.name"), otp, int(expire.Minutes()))
+	if err = u.SMSProvider.Send(ctx, phone, message); err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to send phone code sms")
+		u.Redis.Del(ctx, key)
+		return nil, fiber.NewError(fiber.StatusBadGateway, "Failed to send SMS, please try again later")
+	}
+
+	u.Log.WithField("action", action).WithFields(logrus.Fields{
+		"event":   "phone_code_sent",
+		"purpose": purpose,
+		"phone":   phone,
+	}).Info("Phone code sent")
+
+	return &model.PhoneCodeResponse{Phone: phone, ExpiresIn: int(expire.Seconds())}, nil
+}
+
+// CheckCode mencocokkan kode OTP dan mengembalikan nomor telepon tujuan kode. Kode dihapus setelah berhasil dipakai
+// atau setelah phone.otp_max_attempts kali salah
+func (u *phoneUseCase) CheckCode(ctx context.Context, purpose string, subject string, code string) (string, error) {
+	action := "check phone " + purpose + " code"
+	key := phoneOTPKey(purpose, subject)
+	invalid := fiber.NewError(fiber.StatusBadRequest, "Invalid or expired verification code")
+
+	value, err := u.Redis.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", invalid
+	}
+	if err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to get phone code from redis")
+		return "", fiber.ErrInternalServerError
+	}
+
+	otp := new(phoneOTP)
+	if err = json.Unmarshal([]byte(value), otp); err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to decode phone code")
+		return "", fiber.ErrInternalServerError
+	}
+
+	maxAttempts := u.Config.GetInt("phone.otp_max_attempts")
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	attempts, err := u.Redis.Incr(ctx, key+":attempts").Result()
+	if err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to count phone code attempts in redis")
+		return "", fiber.ErrInternalServerError
+	}
+	u.Redis.Expire(ctx, key+":attempts", u.phoneOTPExpire())
+
+	if attempts > int64(maxAttempts) {
+		u.Redis.Del(ctx, key, key+":attempts")
+		u.Log.WithField("action", action).WithFields(logrus.Fields{
+			"event":   "phone_code_attempts_exceeded",
+			"purpose": purpose,
+			"phone":   otp.Phone,
+		}).Warn("Too many phone code attempts")
+		return "", fiber.NewError(fiber.StatusBadRequest, "Too many incorrect attempts, please request a new code")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(otp.CodeHash), []byte(utils.HashToken(code))) != 1 {
+		return "", invalid
+	}
+
+	// hanya request yang berhasil menghapus kode yang boleh lanjut, supaya kode tidak bisa dipakai dua kali bersamaan
+	deleted, err := u.Redis.Del(ctx, key).Result()
+	if err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to delete phone code from redis")
+		return "", fiber.ErrInternalServerError
+	}
+	if deleted == 0 {
+		return "", invalid
+	}
+	u.Redis.Del(ctx, key+":attempts")
+
+	return otp.Phone, nil
+}
+
+// checkPhoneAvailable menolak nomor yang sudah diverifikasi akun lain, karena nomor terverifikasi dipakai untuk login
+func (u *phoneUseCase) checkPhoneAvailable(tx *gorm.DB, user *entity.User, phoneNumber string) error {
+	owner := new(entity.User)
+	err := u.UserRepository.FindByVerifiedPhone(tx, owner, phoneNumber)
+	if err == nil && owner.ID != user.ID {
+		return fiber.NewError(fiber.StatusConflict, "Phone number is already used by another account")
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		u.Log.WithField("action", "check phone").WithError(err).Error("Failed to find user by phone")
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// hitSMSThrottle mencatat setiap pengiriman SMS (bukan hanya yang gagal) pada nomor tujuan dan IP, karena setiap SMS berbayar
+func (u *phoneUseCase) hitSMSThrottle(ctx context.Context, action string, phone string, ip string) error {
+	subjects := map[string]string{"sms_otp_phone": phone, "sms_otp_ip": ip}
+	for rule, subject := range subjects {
+		if subject == "" {
+			continue
+		}
+
+		err := u.Throttle.Check(ctx, rule, subject)
+		var lockedError *throttle.LockedError
+		if errors.As(err, &lockedError) {
+			u.Log.WithField("action", action).WithFields(logrus.Fields{"rule": rule, "subject": subject}).Warn("Request rejected, subject is locked")
+			return err
+		}
+		if err != nil {
+			u.Log.WithField("action", action).WithError(err).Error("Failed to check throttle")
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	for rule, subject := range subjects {
+		if subject == "" {
+			continue
+		}
+
+		lockedError, err := u.Throttle.Hit(ctx, rule, subject)
+		if err != nil {
+			u.Log.WithField("action", action).WithError(err).Error("Failed to record attempt")
+			continue
+		}
+		if lockedError != nil {
+			u.Log.WithField("action", action).WithFields(logrus.Fields{
+				"event":       "lockout",
+				"rule":        rule,
+				"subject":     subject,
+				"retry_after": lockedError.Seconds(),
+			}).Warn("Security event: too many sms requests, subject locked")
+			return lockedError
+		}
+	}
+
+	return nil
+}
+
+func phoneOTPKey(purpose string, subject string) string {
+	return "phone_otp:" + purpose + ":" + subject
+}
+
+// samePhone membandingkan dua nomor telepon yang boleh kosong
+func samePhone(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/model/converter"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/internal/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// mode registrasi dari config registration.mode
+const (
+	registrationModeOpen       = "open"
+	registrationModeInviteCode = "invite_code"
+	registrationModeApproval   = "approval"
+)
+
+// status persetujuan akun yang mendaftar saat registration.mode = approval
+const (
+	approvalStatusPending  = "pending"
+	approvalStatusApproved = "approved"
+)
+
+type RegistrationCodeUseCase interface {
+	Create(ctx context.Context, request *model.CreateRegistrationCodeRequest) (*model.CreateRegistrationCodeResponse, error)
+	List(ctx context.Context) ([]model.RegistrationCodeResponse, error)
+	Revoke(ctx context.Context, request *model.RevokeRegistrationCodeRequest) error
+}
+
+type registrationCodeUseCase struct {
+	*BaseUseCase
+	RegistrationCodeRepository repository.RegistrationCodeRepository
+}
+
+func NewRegistrationCodeUseCase(baseUseCase *BaseUseCase, registrationCodeRepository repository.RegistrationCodeRepository) RegistrationCodeUseCase {
+	return &registrationCodeUseCase{BaseUseCase: baseUseCase, RegistrationCodeRepository: registrationCodeRepository}
+}
+
+func (u *registrationCodeUseCase) Create(ctx context.Context, request *model.CreateRegistrationCodeRequest) (*model.CreateRegistrationCodeResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "create registration code").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	if !request.ExpiresAt.After(time.Now()) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Expiry time must be in the future")
+	}
+
+	random := make([]byte, 10)
+	if _, err := rand.Read(random); err != nil {
+		u.Log.WithField("action", "create registration code").WithError(err).Error("Failed to generate registration code")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// 16 karakter base32 dibagi per 4 karakter supaya mudah diketik, misalnya ABCD-EFGH-IJKL-MNOP
+	raw := base32.StdEncoding.EncodeToString(random)
+	code := raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16]
+
+	registrationCode := &entity.RegistrationCode{
+		Prefix:      raw[0:4],
+		CodeHash:    utils.HashToken(normalizeRegistrationCode(code)),
+		Description: request.Description,
+		MaxUses:     request.MaxUses,
+		ExpiresAt:   request.ExpiresAt,
+		CreatedBy:   &request.CreatedBy,
+	}
+
+	if err := u.RegistrationCodeRepository.Create(tx, registrationCode); err != nil {
+		u.Log.WithField("action", "create registration code").WithError(err).Error("Failed to create registration code")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "create registration code").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "create registration code").WithFields(logrus.Fields{
+		"event":      "registration_code_created",
+		"actor_id":   request.CreatedBy,
+		"code_id":    registrationCode.ID,
+		"max_uses":   request.MaxUses,
+		"expires_at": request.ExpiresAt,
+	}).Info("Registration code created")
+
+	return &model.CreateRegistrationCodeResponse{RegistrationCodeResponse: *converter.RegistrationCodeToResponse(registrationCode), Code: code}, nil
+}
+
+func (u *registrationCodeUseCase) List(ctx context.Context) ([]model.RegistrationCodeResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	codes, err := u.RegistrationCodeRepository.FindAll(tx)
+	if err != nil {
+		u.Log.WithField("action", "list registration code").WithError(err).Error("Failed to find registration codes")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "list registration code").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.RegistrationCodeResponse, len(codes))
+	for i, code := range codes {
+		responses[i] = *converter.RegistrationCodeToResponse(&code)
+	}
+
+	return responses, nil
+}
+
+func (u *registrationCodeUseCase) Revoke(ctx context.Context, request *model.RevokeRegistrationCodeRequest) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "revoke registration code").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	code := new(entity.RegistrationCode)
+	if err := u.RegistrationCodeRepository.FindById(tx, code, request.ID); err != nil {
+		u.Log.WithField("action", "revoke registration code").WithError(err).Warn("Failed to find registration code")
+		return fiber.NewError(fiber.StatusNotFound, "Registration code not found")
+	}
+
+	if code.RevokedAt != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Registration code has already been revoked")
+	}
+
+	revokedAt := time.Now()
+	code.RevokedAt = &revokedAt
+	if err := u.RegistrationCodeRepository.Update(tx, code); err != nil {
+		u.Log.WithField("action", "revoke registration code").WithError(err).Error("Failed to revoke registration code")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "revoke registration code").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "revoke registration code").WithFields(logrus.Fields{
+		"event":   "registration_code_revoked",
+		"code_id": code.ID,
+	}).Info("Registration code revoked")
+
+	return nil
+}
+
+// registrationMode mengembalikan registration.mode, kosong dianggap open. Nilai yang tidak dikenal sudah ditolak saat aplikasi dijalankan
+func (u *BaseUseCase) registrationMode() string {
+	if mode := u.Config.GetString("registration.mode"); mode != "" {
+		return mode
+	}
+
+	return registrationModeOpen
+}
+
+// normalizeRegistrationCode mengabaikan huruf besar/kecil, spasi dan tanda hubung supaya kode yang diketik manual tetap cocok
+func normalizeRegistrationCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
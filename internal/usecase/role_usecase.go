@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/model/converter"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/pkg/auth"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// protectedRoles tidak bisa dihapus atau diganti namanya: Admin dipakai untuk administrasi, User adalah role default user baru
+var protectedRoles = []string{"Admin", "User"}
+
+type RoleUseCase interface {
+	List(ctx context.Context) ([]model.RoleResponse, error)
+	FindById(ctx context.Context, id any) (*model.RoleResponse, error)
+	Create(ctx context.Context, request *model.CreateRoleRequest) (*model.RoleResponse, error)
+	Update(ctx context.Context, request *model.UpdateRoleRequest) (*model.RoleResponse, error)
+	Delete(ctx context.Context, id any) error
+	ListPermissions(ctx context.Context) ([]model.PermissionResponse, error)
+	AssignRole(ctx context.Context, request *model.AssignRoleRequest) (*model.UserResponse, error)
+	Permissions(ctx context.Context, role string) ([]string, error)
+}
+
+type roleUseCase struct {
+	*BaseUseCase
+	RoleRepository       repository.RoleRepository
+	PermissionRepository repository.PermissionRepository
+	UserRepository       repository.UserRepository
+	Redis                *redis.Client
+	JwtService           *auth.JWTService
+}
+
+func NewRoleUseCase(baseUseCase *BaseUseCase, roleRepository repository.RoleRepository, permissionRepository repository.PermissionRepository, userRepository repository.UserRepository, redis *redis.Client, jwtService *auth.JWTService) RoleUseCase {
+	return &roleUseCase{BaseUseCase: baseUseCase, RoleRepository: roleRepository, PermissionRepository: permissionRepository, UserRepository: userRepository, Redis: redis, JwtService: jwtService}
+}
+
+func (u *roleUseCase) List(ctx context.Context) ([]model.RoleResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	roles, err := u.RoleRepository.FindAll(tx)
+	if err != nil {
+		u.Log.WithField("action", "list role").WithError(err).Error("Failed to find roles")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "list role").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.RoleResponse, len(roles))
+	for i, role := range roles {
+		responses[i] = *converter.RoleToResponse(&role)
+	}
+
+	return responses, nil
+}
+
+func (u *roleUseCase) FindById(ctx context.Context, id any) (*model.RoleResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	role := new(entity.Role)
+	if err := u.RoleRepository.FindById(tx, role, id); err != nil {
+		u.Log.WithField("action", "find role").WithError(err).Error("Failed to find role")
+		return nil, fiber.NewError(fiber.StatusNotFound, "Role not found")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "find role").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.RoleToResponse(role), nil
+}
+
+func (u *roleUseCase) Create(ctx context.Context, request *model.CreateRoleRequest) (*model.RoleResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "create role").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	permissions, err := u.PermissionRepository.FindByNames(tx, request.Permissions)
+	if err != nil {
+		u.Log.WithField("action", "create role").WithError(err).Error("Failed to find permissions")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	role := &entity.Role{Name: request.Name, Description: request.Description}
+	if err = u.RoleRepository.Create(tx, role); err != nil {
+		u.Log.WithField("action", "create role").WithError(err).Error("Failed to create role")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.RoleRepository.ReplacePermissions(tx, role, permissions); err != nil {
+		u.Log.WithField("action", "create role").WithError(err).Error("Failed to create role permissions")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "create role").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	role.Permissions = permissions
+	return converter.RoleToResponse(role), nil
+}
+
+func (u *roleUseCase) Update(ctx context.Context, request *model.UpdateRoleRequest) (*model.RoleResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	role := new(entity.Role)
+	if err := u.RoleRepository.FindById(tx, role, request.ID); err != nil {
+		u.Log.WithField("action", "update role").WithError(err).Error("Failed to find role")
+		return nil, fiber.NewError(fiber.StatusNotFound, "Role not found")
+	}
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "update role").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	oldName := role.Name
+	if oldName != request.Name && slices.Contains(protectedRoles, oldName) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Built-in role cannot be renamed")
+	}
+
+	permissions, err := u.PermissionRepository.FindByNames(tx, request.Permissions)
+	if err != nil {
+		u.Log.WithField("action", "update role").WithError(err).Error("Failed to find permissions")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	role.Name = request.Name
+	role.Description = request.Description
+	role.Permissions = nil
+	if err = u.RoleRepository.Update(tx, role); err != nil {
+		u.Log.WithField("action", "update role").WithError(err).Error("Failed to update role")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.RoleRepository.ReplacePermissions(tx, role, permissions); err != nil {
+		u.Log.WithField("action", "update role").WithError(err).Error("Failed to update role permissions")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if oldName != role.Name {
+		if err = u.RoleRepository.RenameUsersRole(tx, oldName, role.Name); err != nil {
+			u.Log.WithField("action", "update role").WithError(err).Error("Failed to rename role of users")
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "update role").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.invalidate(ctx, "update role", oldName, role.Name)
+	u.Log.WithField("action", "update role").WithFields(logrus.Fields{"event": "role_updated", "role": role.Name, "permissions": request.Permissions}).Info("Role updated")
+
+	role.Permissions = permissions
+	return converter.RoleToResponse(role), nil
+}
+
+func (u *roleUseCase) Delete(ctx context.Context, id any) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	role := new(entity.Role)
+	if err := u.RoleRepository.FindById(tx, role, id); err != nil {
+		u.Log.WithField("action", "delete role").WithError(err).Error("Failed to find role")
+		return fiber.NewError(fiber.StatusNotFound, "Role not found")
+	}
+
+	if slices.Contains(protectedRoles, role.Name) {
+		return fiber.NewError(fiber.StatusBadRequest, "Built-in role cannot be deleted")
+	}
+
+	count, err := u.RoleRepository.CountUsers(tx, role.Name)
+	if err != nil {
+		u.Log.WithField("action", "delete role").WithError(err).Error("Failed to count users of role")
+		return fiber.ErrInternalServerError
+	}
+
+	if count > 0 {
+		return fiber.NewError(fiber.StatusConflict, "Role is still assigned to users")
+	}
+
+	if err = u.RoleRepository.HardDelete(tx, role); err != nil {
+		u.Log.WithField("action", "delete role").WithError(err).Error("Failed to delete role")
+		return fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "delete role").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	u.invalidate(ctx, "delete role", role.Name)
+
+	return nil
+}
+
+func (u *roleUseCase) ListPermissions(ctx context.Context) ([]model.PermissionResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	permissions, err := u.PermissionRepository.FindAll(tx)
+	if err != nil {
+		u.Log.WithField("action", "list permission").WithError(err).Error("Failed to find permissions")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "list permission").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := make([]model.PermissionResponse, len(permissions))
+	for i, permission := range permissions {
+		responses[i] = *converter.PermissionToResponse(&permission)
+	}
+
+	return responses, nil
+}
+
+func (u *roleUseCase) AssignRole(ctx context.Context, request *model.AssignRoleRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "assign role").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	if request.ActorID == request.ID {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "You cannot change your own role")
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "assign role").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	oldRole := user.Role
+	user.Role = request.Role
+	if err := u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "assign role").WithError(err).Error("Failed to update user role")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "assign role").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if oldRole != user.Role {
+		if err := u.JwtService.InvalidateTokens(ctx, user.ID, ""); err != nil {
+			u.Log.WithField("action", "assign role").WithError(err).Error("Failed to invalidate user tokens")
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	u.Log.WithField("action", "assign role").WithFields(logrus.Fields{
+		"event":    "role_assigned",
+		"actor_id": request.ActorID,
+		"user_id":  user.ID,
+		"old_role": oldRole,
+		"new_role": user.Role,
+	}).Info("User role changed")
+
+	return converter.UserToResponse(user), nil
+}
+
+// Permissions mengembalikan daftar permission milik role, di cache di redis dan dihapus setiap kali role berubah
+func (u *roleUseCase) Permissions(ctx context.Context, role string) ([]string, error) {
+	key := "role_permissions:" + role
+
+	var permissions []string
+	cached, err := u.Redis.Get(ctx, key).Bytes()
+	if err == nil && json.Unmarshal(cached, &permissions) == nil {
+		return permissions, nil
+	}
+
+	entityRole := new(entity.Role)
+	err = u.RoleRepository.FindByName(u.DB.WithContext(ctx), entityRole, role)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		u.Log.WithField("action", "role permissions").WithError(err).Error("Failed to find role")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	permissions = converter.RoleToResponse(entityRole).Permissions
+	value, err := json.Marshal(permissions)
+	if err == nil {
+		err = u.Redis.SetEx(ctx, key, value, time.Hour).Err()
+	}
+	if err != nil {
+		u.Log.WithField("action", "role permissions").WithError(err).Warn("Failed to cache role permissions in redis")
+	}
+
+	return permissions, nil
+}
+
+func (u *roleUseCase) invalidate(ctx context.Context, action string, roles ...string) {
+	keys := make([]string, len(roles))
+	for i, role := range roles {
+		keys[i] = "role_permissions:" + role
+	}
+
+	if err := u.Redis.Del(ctx, keys...).Err(); err != nil {
+		u.Log.WithField("action", action).WithError(err).Error("Failed to invalidate role permissions cache")
+	}
+}
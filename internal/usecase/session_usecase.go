@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/pkg/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionUseCase interface {
+	List(ctx context.Context, request *model.ListSessionRequest) ([]model.SessionResponse, error)
+	Revoke(ctx context.Context, request *model.RevokeSessionRequest) error
+	RevokeOthers(ctx context.Context, request *model.ListSessionRequest) (int, error)
+}
+
+type sessionUseCase struct {
+	*BaseUseCase
+	JwtService *auth.JWTService
+}
+
+func NewSessionUseCase(baseUseCase *BaseUseCase, jwtService *auth.JWTService) SessionUseCase {
+	return &sessionUseCase{BaseUseCase: baseUseCase, JwtService: jwtService}
+}
+
+func (u *sessionUseCase) List(ctx context.Context, request *model.ListSessionRequest) ([]model.SessionResponse, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "list session").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	sessions, err := u.JwtService.ListSessions(ctx, request.UserID)
+	if err != nil {
+		u.Log.WithField("action", "list session").WithError(err).Error("Failed to list sessions")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == request.CurrentSessionID
+	}
+
+	return sessions, nil
+}
+
+func (u *sessionUseCase) Revoke(ctx context.Context, request *model.RevokeSessionRequest) error {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "revoke session").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	if request.ID == request.CurrentSessionID {
+		return fiber.NewError(fiber.StatusBadRequest, "Use logout to end the current session")
+	}
+
+	err := u.JwtService.RevokeSession(ctx, request.UserID, request.ID)
+	if errors.Is(err, auth.ErrSessionNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Session not found")
+	}
+	if err != nil {
+		u.Log.WithField("action", "revoke session").WithError(err).Error("Failed to revoke session")
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (u *sessionUseCase) RevokeOthers(ctx context.Context, request *model.ListSessionRequest) (int, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "revoke other sessions").WithError(err).Warn("Failed to validate request body")
+		return 0, err
+	}
+
+	revoked, err := u.JwtService.RevokeOtherSessions(ctx, request.UserID, request.CurrentSessionID)
+	if err != nil {
+		u.Log.WithField("action", "revoke other sessions").WithError(err).Error("Failed to revoke other sessions")
+		return 0, fiber.ErrInternalServerError
+	}
+
+	return revoked, nil
+}
@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/pkg/encryption"
+	"github.com/alfianyulianto/pds-service/pkg/totp"
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const recoveryCodeCount = 10
+
+type TwoFactorUseCase interface {
+	Setup(ctx context.Context, request *model.GetUserRequest) (*model.TwoFactorSetupResponse, error)
+	Confirm(ctx context.Context, request *model.ConfirmTwoFactorRequest) (*model.RecoveryCodesResponse, error)
+	Disable(ctx context.Context, request *model.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, request *model.RegenerateRecoveryCodesRequest) (*model.RecoveryCodesResponse, error)
+	ValidateCode(ctx context.Context, user *entity.User, code string) error
+}
+
+type twoFactorUseCase struct {
+	*BaseUseCase
+	UserRepository         repository.UserRepository
+	RecoveryCodeRepository repository.RecoveryCodeRepository
+	Redis                  *redis.Client
+	Cipher                 *encryption.Cipher
+}
+
+func NewTwoFactorUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, recoveryCodeRepository repository.RecoveryCodeRepository, redis *redis.Client, cipher *encryption.Cipher) TwoFactorUseCase {
+	return &twoFactorUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, RecoveryCodeRepository: recoveryCodeRepository, Redis: redis, Cipher: cipher}
+}
+
+func (u *twoFactorUseCase) Setup(ctx context.Context, request *model.GetUserRequest) (*model.TwoFactorSetupResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "setup two factor").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "setup two factor").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "setup two factor").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if user.TwoFactorEnabledAt != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		u.Log.WithField("action", "setup two factor").WithError(err).Error("Failed to generate totp secret")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// secret disimpan sementara sampai user mengkonfirmasi dengan kode pertama dari aplikasi authenticator
+	err = u.Redis.SetEx(ctx, "two_factor_setup:"+user.ID.String(), secret, 10*time.Minute).Err()
+	if err != nil {
+		u.Log.WithField("action", "setup two factor").WithError(err).Error("Failed to set two factor setup secret in redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(u.Config.GetString("app.name"), user.Email, secret),
+	}, nil
+}
+
+func (u *twoFactorUseCase) Confirm(ctx context.Context, request *model.ConfirmTwoFactorRequest) (*model.RecoveryCodesResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "confirm two factor").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "confirm two factor").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if user.TwoFactorEnabledAt != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Two-factor authentication is already enabled")
+	}
+
+	setupKey := "two_factor_setup:" + user.ID.String()
+	secret, err := u.Redis.Get(ctx, setupKey).Result()
+	if err != nil {
+		u.Log.WithField("action", "confirm two factor").WithError(err).Warn("Failed to get two factor setup secret from redis")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Two-factor setup has expired, please start again")
+	}
+
+	if !totp.Validate(secret, request.Code, time.Now(), 1) {
+		u.Log.WithField("action", "confirm two factor").Warn("Invalid two factor code")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid two-factor authentication code")
+	}
+
+	// secret disimpan terenkripsi, jika database bocor secret TOTP tidak bisa dipakai tanpa app.encryption_key
+	encryptedSecret, err := u.Cipher.Encrypt(secret, user.ID.String())
+	if err != nil {
+		u.Log.WithField("action", "confirm two factor").WithError(err).Error("Failed to encrypt totp secret")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	enabledAt := time.Now()
+	user.TwoFactorSecret = &encryptedSecret
+	user.TwoFactorEnabledAt = &enabledAt
+	if err = u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "confirm two factor").WithError(err).Error("Failed to enable two factor")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	codes, err := u.replaceRecoveryCodes(tx, user)
+	if err != nil {
+		u.Log.WithField("action", "confirm two factor").WithError(err).Error("Failed to create recovery codes")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "confirm two factor").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.Redis.Del(ctx, setupKey).Err(); err != nil {
+		u.Log.WithField("action", "confirm two factor").WithError(err).Error("Failed to delete two factor setup secret from redis")
+	}
+
+	return &model.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (u *twoFactorUseCase) Disable(ctx context.Context, request *model.DisableTwoFactorRequest) error {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "disable two factor").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(u.DB.WithContext(ctx), user, request.ID); err != nil {
+		u.Log.WithField("action", "disable two factor").WithError(err).Error("Failed to find user")
+		return fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if user.TwoFactorEnabledAt == nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Two-factor authentication is not enabled")
+	}
+
+	if err := u.ValidateCode(ctx, user, request.Code); err != nil {
+		return err
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user.TwoFactorSecret = nil
+	user.TwoFactorEnabledAt = nil
+	if err := u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "disable two factor").WithError(err).Error("Failed to disable two factor")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := u.RecoveryCodeRepository.DeleteByUserId(tx, user.ID); err != nil {
+		u.Log.WithField("action", "disable two factor").WithError(err).Error("Failed to delete recovery codes")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "disable two factor").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (u *twoFactorUseCase) RegenerateRecoveryCodes(ctx context.Context, request *model.RegenerateRecoveryCodesRequest) (*model.RecoveryCodesResponse, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "regenerate recovery codes").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(u.DB.WithContext(ctx), user, request.ID); err != nil {
+		u.Log.WithField("action", "regenerate recovery codes").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if user.TwoFactorEnabledAt == nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Two-factor authentication is not enabled")
+	}
+
+	if err := u.ValidateCode(ctx, user, request.Code); err != nil {
+		return nil, err
+	}
+
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	codes, err := u.replaceRecoveryCodes(tx, user)
+	if err != nil {
+		u.Log.WithField("action", "regenerate recovery codes").WithError(err).Error("Failed to create recovery codes")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "regenerate recovery codes").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// ValidateCode menerima kode TOTP 6 digit atau recovery code, recovery code yang valid langsung ditandai terpakai
+func (u *twoFactorUseCase) ValidateCode(ctx context.Context, user *entity.User, code string) error {
+	invalidCode := fiber.NewError(fiber.StatusUnauthorized, "Invalid two-factor authentication code")
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits {
+		if user.TwoFactorSecret == nil {
+			u.Log.WithField("action", "validate two factor code").WithField("user_id", user.ID).Warn("Invalid totp code")
+			return invalidCode
+		}
+
+		secret, err := u.decryptSecret(user)
+		if err != nil {
+			u.Log.WithField("action", "validate two factor code").WithField("user_id", user.ID).WithError(err).Error("Failed to decrypt totp secret")
+			return fiber.ErrInternalServerError
+		}
+
+		if !totp.Validate(secret, code, time.Now(), 1) {
+			u.Log.WithField("action", "validate two factor code").WithField("user_id", user.ID).Warn("Invalid totp code")
+			return invalidCode
+		}
+
+		// kode yang sama tidak boleh dipakai dua kali selama masih dalam jendela waktu validasi
+		usedKey := "two_factor_used:" + user.ID.String() + ":" + code
+		fresh, err := u.Redis.SetNX(ctx, usedKey, 1, 3*totp.Period).Result()
+		if err != nil {
+			u.Log.WithField("action", "validate two factor code").WithError(err).Error("Failed to mark totp code as used in redis")
+			return fiber.ErrInternalServerError
+		}
+
+		if !fresh {
+			u.Log.WithField("action", "validate two factor code").WithField("user_id", user.ID).Warn("Totp code has already been used")
+			return invalidCode
+		}
+
+		// secret lama yang masih plaintext dienkripsi saat kode berhasil divalidasi
+		if !encryption.IsEncrypted(*user.TwoFactorSecret) {
+			if encryptedSecret, err := u.Cipher.Encrypt(secret, user.ID.String()); err != nil {
+				u.Log.WithField("action", "validate two factor code").WithError(err).Error("Failed to encrypt totp secret")
+			} else {
+				user.TwoFactorSecret = &encryptedSecret
+				if err = u.UserRepository.Update(u.DB.WithContext(ctx), user); err != nil {
+					u.Log.WithField("action", "validate two factor code").WithError(err).Error("Failed to save encrypted totp secret")
+				}
+			}
+		}
+
+		return nil
+	}
+
+	consumed, err := u.RecoveryCodeRepository.Consume(u.DB.WithContext(ctx), user.ID, hashRecoveryCode(code))
+	if err != nil {
+		u.Log.WithField("action", "validate two factor code").WithError(err).Error("Failed to mark recovery code as used")
+		return fiber.ErrInternalServerError
+	}
+	if !consumed {
+		u.Log.WithField("action", "validate two factor code").WithField("user_id", user.ID).Warn("Invalid recovery code")
+		return invalidCode
+	}
+
+	remaining, err := u.RecoveryCodeRepository.CountUnused(u.DB.WithContext(ctx), user.ID)
+	if err == nil && remaining <= 2 {
+		u.Log.WithField("action", "validate two factor code").WithField("user_id", user.ID).Warnf("User has %d recovery codes left", remaining)
+	}
+
+	return nil
+}
+
+// decryptSecret mengembalikan secret TOTP user, secret lama yang disimpan sebelum enkripsi dikembalikan apa adanya
+func (u *twoFactorUseCase) decryptSecret(user *entity.User) (string, error) {
+	if !encryption.IsEncrypted(*user.TwoFactorSecret) {
+		return *user.TwoFactorSecret, nil
+	}
+
+	return u.Cipher.Decrypt(*user.TwoFactorSecret, user.ID.String())
+}
+
+func (u *twoFactorUseCase) replaceRecoveryCodes(tx *gorm.DB, user *entity.User) ([]string, error) {
+	if err := u.RecoveryCodeRepository.DeleteByUserId(tx, user.ID); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = u.RecoveryCodeRepository.Create(tx, &entity.RecoveryCode{UserID: user.ID, CodeHash: hashRecoveryCode(code)}); err != nil {
+			return nil, err
+		}
+		codes[i] = code
+	}
+
+	return codes, nil
+}
+
+func generateRecoveryCode() (string, error) {
+	random := make([]byte, 10)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.EncodeToString(random))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
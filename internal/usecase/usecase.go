@@ -1,11 +1,18 @@
 package usecase
 
 import (
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/internal/utils"
+	"github.com/alfianyulianto/pds-service/pkg/password"
+	"github.com/alfianyulianto/pds-service/pkg/phone"
 	"github.com/alfianyulianto/pds-service/pkg/storage"
 	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"gorm.io/gorm"
+	"time"
 )
 
 type BaseUseCase struct {
@@ -14,8 +21,73 @@ type BaseUseCase struct {
 	Storage  storage.StorageProvider
 	Config   *viper.Viper
 	Log      *logrus.Entry
+	Hasher   password.Hasher
 }
 
-func NewBaseUseCase(DB *gorm.DB, validate *validator.Validate, storage storage.StorageProvider, config *viper.Viper, log *logrus.Entry) *BaseUseCase {
-	return &BaseUseCase{DB: DB, Validate: validate, Storage: storage, Config: config, Log: log}
+func NewBaseUseCase(DB *gorm.DB, validate *validator.Validate, storage storage.StorageProvider, config *viper.Viper, log *logrus.Entry, hasher password.Hasher) *BaseUseCase {
+	return &BaseUseCase{DB: DB, Validate: validate, Storage: storage, Config: config, Log: log, Hasher: hasher}
+}
+
+// checkAccountStatus menolak akun yang undangannya belum diterima, belum disetujui admin, dinonaktifkan atau sedang di suspend
+func checkAccountStatus(user *entity.User) error {
+	if user.InvitationPending() {
+		return fiber.NewError(fiber.StatusForbidden, "Your account invitation has not been accepted yet")
+	}
+
+	if user.ApprovalPending() {
+		return fiber.NewError(fiber.StatusForbidden, "Your registration is waiting for administrator approval")
+	}
+
+	if !user.IsActive {
+		return fiber.NewError(fiber.StatusForbidden, "Your account has been deactivated")
+	}
+
+	if user.Suspended() {
+		message := "Your account has been suspended"
+		if user.SuspendedUntil != nil {
+			message += " until " + utils.FormatTime(*user.SuspendedUntil)
+		}
+		if user.SuspensionReason != nil {
+			message += ". Reason: " + *user.SuspensionReason
+		}
+		return fiber.NewError(fiber.StatusForbidden, message)
+	}
+
+	return nil
+}
+
+// savePasswordHistory mencatat hash password user saat ini ke riwayat, hanya password_policy.history_count riwayat terakhir yang disimpan
+func (u *BaseUseCase) savePasswordHistory(tx *gorm.DB, historyRepository repository.PasswordHistoryRepository, user *entity.User) error {
+	keep := u.Config.GetInt("password_policy.history_count")
+	if keep <= 0 {
+		return nil
+	}
+
+	if err := historyRepository.Create(tx, &entity.PasswordHistory{UserID: user.ID, Password: user.Password}); err != nil {
+		return err
+	}
+
+	return historyRepository.Prune(tx, user.ID, keep)
+}
+
+// normalizePhone mengubah nomor telepon request ke format E.164 sebelum divalidasi, nomor yang tidak bisa dinormalisasi
+// dibiarkan supaya ditolak validator e164
+func (u *BaseUseCase) normalizePhone(phoneNumber *string) {
+	if phoneNumber == nil || *phoneNumber == "" {
+		return
+	}
+
+	if normalized, err := phone.Normalize(*phoneNumber, u.Config.GetString("phone.default_country_code")); err == nil {
+		*phoneNumber = normalized
+	}
+}
+
+// phoneOTPExpire adalah masa berlaku kode OTP yang dikirim lewat SMS
+func (u *BaseUseCase) phoneOTPExpire() time.Duration {
+	expireDuration := u.Config.GetInt("phone.otp_expire_duration")
+	if expireDuration <= 0 {
+		expireDuration = 300
+	}
+
+	return time.Duration(expireDuration) * time.Second
 }
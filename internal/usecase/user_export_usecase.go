@@ -0,0 +1,405 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/entity"
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/internal/repository"
+	"github.com/alfianyulianto/pds-service/pkg/spreadsheet"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// status export user yang dijalankan di background
+const (
+	exportStatusQueued     = "queued"
+	exportStatusProcessing = "processing"
+	exportStatusCompleted  = "completed"
+	exportStatusFailed     = "failed"
+)
+
+// exportColumns adalah kolom yang bisa dipilih saat export, kolom sensitif (password, token) sengaja tidak tersedia
+var exportColumns = map[string]func(user *entity.User) any{
+	"id":                 func(user *entity.User) any { return user.ID.String() },
+	"name":               func(user *entity.User) any { return user.Name },
+	"email":              func(user *entity.User) any { return user.Email },
+	"email_verified_at":  func(user *entity.User) any { return exportTime(user.EmailVerifiedAt) },
+	"phone":              func(user *entity.User) any { return exportString(user.Phone) },
+	"phone_verified_at":  func(user *entity.User) any { return exportTime(user.PhoneVerifiedAt) },
+	"role":               func(user *entity.User) any { return user.Role },
+	"is_active":          func(user *entity.User) any { return user.IsActive },
+	"two_factor_enabled": func(user *entity.User) any { return user.TwoFactorEnabledAt != nil },
+	"suspended":          func(user *entity.User) any { return user.Suspended() },
+	"suspended_until":    func(user *entity.User) any { return exportTime(user.SuspendedUntil) },
+	"invitation_status":  func(user *entity.User) any { return user.InvitationStatus() },
+	"approval_status":    func(user *entity.User) any { return exportString(user.ApprovalStatus) },
+	"last_login_at":      func(user *entity.User) any { return exportTime(user.LastLoginAt) },
+	"created_at":         func(user *entity.User) any { return user.CreatedAt },
+	"updated_at":         func(user *entity.User) any { return user.UpdatedAt },
+}
+
+var defaultExportColumns = []string{"id", "name", "email", "phone", "role", "is_active", "email_verified_at", "last_login_at", "created_at"}
+
+type UserExportUseCase interface {
+	Export(ctx context.Context, request *model.ExportUserRequest) (*model.UserExportResponse, error)
+	Stream(ctx context.Context, request *model.ExportUserRequest, w io.Writer) error
+	FindById(ctx context.Context, request *model.UserExportRequest) (*model.UserExportResponse, error)
+	Download(ctx context.Context, request *model.UserExportRequest) (string, *model.UserExportResponse, error)
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type userExportUseCase struct {
+	*BaseUseCase
+	UserRepository repository.UserRepository
+	Redis          *redis.Client
+}
+
+func NewUserExportUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, redis *redis.Client) UserExportUseCase {
+	return &userExportUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, Redis: redis}
+}
+
+// Export memvalidasi request dan menghitung jumlah baris. Jika baris lebih dari export.background_threshold (atau
+// request.Background) export dijalankan di background dan job dikembalikan, jika tidak dikembalikan nil dan hasil
+// export langsung di stream lewat Stream
+func (u *userExportUseCase) Export(ctx context.Context, request *model.ExportUserRequest) (*model.UserExportResponse, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "export user").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	columns, err := parseExportColumns(request.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := u.UserRepository.Count(u.DB.WithContext(ctx), &request.SearchUserRequest)
+	if err != nil {
+		u.Log.WithField("action", "export user").WithError(err).Error("Failed to count users")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	threshold := u.Config.GetInt64("export.background_threshold")
+	if threshold <= 0 {
+		threshold = 10000
+	}
+	if !request.Background && total <= threshold {
+		return nil, nil
+	}
+
+	now := time.Now()
+	job := &model.UserExportResponse{
+		ID:         uuid.NewString(),
+		Status:     exportStatusQueued,
+		Format:     request.Format,
+		Columns:    columns,
+		TotalRows:  total,
+		FileName:   ExportFileName(request.Format),
+		ExportedBy: request.ExportedBy,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(u.exportTTL()),
+	}
+
+	if err = u.save(ctx, job); err != nil {
+		u.Log.WithField("action", "export user").WithError(err).Error("Failed to save export job in redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// request context selesai saat response dikirim, proses background memakai context sendiri
+	go u.run(context.Background(), job, request)
+
+	return job, nil
+}
+
+// Stream menulis hasil export langsung ke w per batch, dipakai untuk export yang cukup kecil untuk dikirim di response
+func (u *userExportUseCase) Stream(ctx context.Context, request *model.ExportUserRequest, w io.Writer) error {
+	columns, err := parseExportColumns(request.Columns)
+	if err != nil {
+		return err
+	}
+
+	processed, err := u.write(ctx, request, columns, w, nil)
+	if err != nil {
+		return err
+	}
+
+	u.Log.WithField("action", "export user").WithFields(logrus.Fields{
+		"event":    "users_exported",
+		"actor_id": request.ExportedBy,
+		"format":   request.Format,
+		"rows":     processed,
+	}).Info("User export streamed")
+
+	return nil
+}
+
+func (u *userExportUseCase) FindById(ctx context.Context, request *model.UserExportRequest) (*model.UserExportResponse, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "find user export").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	value, err := u.Redis.Get(ctx, "user_export:"+request.ID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Export data not found")
+	}
+	if err != nil {
+		u.Log.WithField("action", "find user export").WithError(err).Error("Failed to get export job from redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	job := new(model.UserExportResponse)
+	if err = json.Unmarshal([]byte(value), job); err != nil {
+		u.Log.WithField("action", "find user export").WithError(err).Error("Failed to decode export job")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// file export berisi data pribadi, hanya bisa dilihat dan diunduh oleh yang membuat export
+	if job.ExportedBy != request.ActorID {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Export data not found")
+	}
+
+	return job, nil
+}
+
+// Download mengembalikan path file export yang sudah selesai
+func (u *userExportUseCase) Download(ctx context.Context, request *model.UserExportRequest) (string, *model.UserExportResponse, error) {
+	job, err := u.FindById(ctx, request)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if job.Status != exportStatusCompleted {
+		return "", nil, fiber.NewError(fiber.StatusConflict, "Export is not completed yet")
+	}
+
+	path := u.exportPath(job)
+	if _, err = os.Stat(path); err != nil {
+		u.Log.WithField("action", "download user export").WithError(err).Warn("Export file not found")
+		return "", nil, fiber.NewError(fiber.StatusNotFound, "Export file not found")
+	}
+
+	u.Log.WithField("action", "download user export").WithFields(logrus.Fields{
+		"event":     "users_export_downloaded",
+		"actor_id":  request.ActorID,
+		"export_id": job.ID,
+	}).Info("User export downloaded")
+
+	return path, job, nil
+}
+
+// PurgeExpired menghapus file export yang lebih lama dari export.result_ttl
+func (u *userExportUseCase) PurgeExpired(ctx context.Context) (int, error) {
+	entries, err := os.ReadDir(u.exportDirectory())
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	before := time.Now().Add(-u.exportTTL())
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || info.ModTime().After(before) {
+			continue
+		}
+
+		if err = os.Remove(filepath.Join(u.exportDirectory(), entry.Name())); err != nil {
+			u.Log.WithField("action", "purge user export").WithError(err).Error("Failed to delete export file")
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// run menulis export ke file di export.directory, progress disimpan ke redis supaya bisa dipantau lewat FindById
+func (u *userExportUseCase) run(ctx context.Context, job *model.UserExportResponse, request *model.ExportUserRequest) {
+	job.Status = exportStatusProcessing
+	u.saveProgress(ctx, job)
+
+	err := u.writeFile(ctx, job, request)
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err != nil {
+		u.Log.WithField("action", "export user").WithField("export_id", job.ID).WithError(err).Error("Failed to export users")
+		_ = os.Remove(u.exportPath(job))
+		job.Status = exportStatusFailed
+		job.Error = "Failed to export users, please try again"
+		u.saveProgress(ctx, job)
+		return
+	}
+
+	job.Status = exportStatusCompleted
+	job.DownloadURL = fmt.Sprintf("/api/users/export/%s/download", job.ID)
+	u.saveProgress(ctx, job)
+
+	u.Log.WithField("action", "export user").WithFields(logrus.Fields{
+		"event":     "users_exported",
+		"actor_id":  job.ExportedBy,
+		"export_id": job.ID,
+		"format":    job.Format,
+		"rows":      job.ProcessedRows,
+	}).Info("User export completed")
+}
+
+func (u *userExportUseCase) writeFile(ctx context.Context, job *model.UserExportResponse, request *model.ExportUserRequest) error {
+	if err := os.MkdirAll(u.exportDirectory(), 0o700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(u.exportPath(job), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = u.write(ctx, request, job.Columns, file, func(processed int64) {
+		job.ProcessedRows = processed
+		u.saveProgress(ctx, job)
+	})
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+// write membaca user per batch dan langsung menulisnya ke w, progress dipanggil setiap satu batch selesai ditulis
+func (u *userExportUseCase) write(ctx context.Context, request *model.ExportUserRequest, columns []string, w io.Writer, progress func(processed int64)) (int64, error) {
+	writer, err := spreadsheet.NewWriter(request.Format, w, columns)
+	if err != nil {
+		return 0, err
+	}
+
+	batchSize := u.Config.GetInt("export.batch_size")
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	var processed int64
+	values := make([]any, len(columns))
+	err = u.UserRepository.FindInBatches(u.DB.WithContext(ctx), &request.SearchUserRequest, batchSize, func(users []entity.User) error {
+		for i := range users {
+			for j, column := range columns {
+				values[j] = exportColumns[column](&users[i])
+			}
+			if err := writer.Write(values); err != nil {
+				return err
+			}
+		}
+		processed += int64(len(users))
+
+		// response stream memakai bufio.Writer, di flush per batch supaya client langsung menerima data
+		if flusher, ok := w.(interface{ Flush() error }); ok {
+			if err := flusher.Flush(); err != nil {
+				return err
+			}
+		}
+		if progress != nil {
+			progress(processed)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return processed, err
+	}
+
+	return processed, writer.Close()
+}
+
+func (u *userExportUseCase) save(ctx context.Context, job *model.UserExportResponse) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return u.Redis.SetEx(ctx, "user_export:"+job.ID, value, u.exportTTL()).Err()
+}
+
+// saveProgress hanya mencatat error di log, kegagalan menyimpan progress tidak menghentikan export
+func (u *userExportUseCase) saveProgress(ctx context.Context, job *model.UserExportResponse) {
+	if err := u.save(ctx, job); err != nil {
+		u.Log.WithField("action", "export user").WithField("export_id", job.ID).WithError(err).Error("Failed to save export progress in redis")
+	}
+}
+
+func (u *userExportUseCase) exportTTL() time.Duration {
+	ttl := u.Config.GetInt("export.result_ttl")
+	if ttl <= 0 {
+		ttl = 86400
+	}
+
+	return time.Duration(ttl) * time.Second
+}
+
+// exportDirectory tidak boleh di dalam ./uploads karena folder tersebut bisa diakses publik
+func (u *userExportUseCase) exportDirectory() string {
+	directory := u.Config.GetString("export.directory")
+	if directory == "" {
+		directory = "./storage/exports"
+	}
+
+	return directory
+}
+
+func (u *userExportUseCase) exportPath(job *model.UserExportResponse) string {
+	return filepath.Join(u.exportDirectory(), job.ID+"."+job.Format)
+}
+
+// ExportFileName adalah nama file yang diberikan ke client, misalnya users-20251224-153000.csv
+func ExportFileName(format string) string {
+	return "users-" + time.Now().Format("20060102-150405") + "." + format
+}
+
+// parseExportColumns membaca daftar kolom yang dipisah koma, kolom duplikat dibuang dan kosong berarti kolom default
+func parseExportColumns(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return defaultExportColumns, nil
+	}
+
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if column == "" || slices.Contains(columns, column) {
+			continue
+		}
+		if _, ok := exportColumns[column]; !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Unknown export column %q", column))
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, nil
+}
+
+func exportString(value *string) any {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
+
+func exportTime(value *time.Time) any {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alfianyulianto/pds-service/internal/model"
+	"github.com/alfianyulianto/pds-service/pkg/spreadsheet"
+	"github.com/alfianyulianto/pds-service/pkg/validators"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// status proses import user
+const (
+	importStatusQueued     = "queued"
+	importStatusProcessing = "processing"
+	importStatusCompleted  = "completed"
+)
+
+// field yang bisa diisi dari file import, password dan is_active hanya untuk import dengan password, role hanya untuk undangan
+var (
+	importFields         = []string{"name", "email", "phone", "password", "is_active", "role"}
+	importPasswordFields = []string{"name", "email", "phone", "password", "is_active"}
+	importInviteFields   = []string{"name", "email", "phone", "role"}
+)
+
+type UserImportUseCase interface {
+	Import(ctx context.Context, request *model.ImportUserRequest) (*model.UserImportResponse, error)
+	FindById(ctx context.Context, request *model.GetUserImportRequest) (*model.UserImportResponse, error)
+}
+
+type userImportUseCase struct {
+	*BaseUseCase
+	UserUseCase UserUseCase
+	Redis       *redis.Client
+}
+
+func NewUserImportUseCase(baseUseCase *BaseUseCase, userUseCase UserUseCase, redis *redis.Client) UserImportUseCase {
+	return &userImportUseCase{BaseUseCase: baseUseCase, UserUseCase: userUseCase, Redis: redis}
+}
+
+type importRow struct {
+	Number int // nomor baris di file
+	Values []string
+}
+
+// Import membaca file CSV/XLSX dan membuat user per baris lewat UserUseCase.Create (atau Invite jika request.Invite),
+// sehingga setiap baris divalidasi dengan aturan yang sama. Baris yang gagal tidak membatalkan baris lain. File dengan
+// baris lebih dari import.background_threshold diproses di background, hasilnya diambil lewat FindById
+func (u *userImportUseCase) Import(ctx context.Context, request *model.ImportUserRequest) (*model.UserImportResponse, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "import user").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	file, err := request.File.Open()
+	if err != nil {
+		u.Log.WithField("action", "import user").WithError(err).Error("Failed to open import file")
+		return nil, fiber.ErrInternalServerError
+	}
+	defer file.Close()
+
+	maxRows := u.Config.GetInt("import.max_rows")
+	if maxRows <= 0 {
+		maxRows = 5000
+	}
+
+	// baris data setelah batas ditolak saat membaca file (header + maxRows), sebelum seluruh baris dialokasikan
+	rows, err := spreadsheet.Read(request.File.Filename, file, request.File.Size, maxRows+1)
+	if errors.Is(err, spreadsheet.ErrUnsupported) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "File must be a csv or xlsx file")
+	}
+	if errors.Is(err, spreadsheet.ErrTooManyRows) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("File must not contain more than %d rows", maxRows))
+	}
+	if err != nil {
+		u.Log.WithField("action", "import user").WithError(err).Warn("Failed to read import file")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Failed to read file, make sure the file is a valid csv or xlsx file")
+	}
+	if len(rows) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "File is empty")
+	}
+
+	columns, err := importColumns(rows[0], request)
+	if err != nil {
+		return nil, err
+	}
+
+	var dataRows []importRow
+	for i, values := range rows[1:] {
+		if len(values) == 0 {
+			continue
+		}
+		dataRows = append(dataRows, importRow{Number: i + 2, Values: values})
+	}
+	if len(dataRows) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "File has no data rows")
+	}
+
+	if len(dataRows) > maxRows {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("File must not contain more than %d rows", maxRows))
+	}
+
+	job := &model.UserImportResponse{
+		ID:         uuid.NewString(),
+		Status:     importStatusQueued,
+		FileName:   request.File.Filename,
+		DryRun:     request.DryRun,
+		Invite:     request.Invite,
+		TotalRows:  len(dataRows),
+		Errors:     []model.ImportRowError{},
+		ImportedBy: request.ImportedBy,
+		CreatedAt:  time.Now(),
+	}
+
+	threshold := u.Config.GetInt("import.background_threshold")
+	if threshold <= 0 {
+		threshold = 100
+	}
+	if len(dataRows) <= threshold {
+		u.process(ctx, job, dataRows, columns)
+		return job, nil
+	}
+
+	if err = u.save(ctx, job); err != nil {
+		u.Log.WithField("action", "import user").WithError(err).Error("Failed to save import job in redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// request context selesai saat response dikirim, proses background memakai context sendiri
+	go u.process(context.Background(), job, dataRows, columns)
+
+	return job, nil
+}
+
+func (u *userImportUseCase) FindById(ctx context.Context, request *model.GetUserImportRequest) (*model.UserImportResponse, error) {
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "find user import").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	value, err := u.Redis.Get(ctx, "user_import:"+request.ID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Import data not found")
+	}
+	if err != nil {
+		u.Log.WithField("action", "find user import").WithError(err).Error("Failed to get import job from redis")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	job := new(model.UserImportResponse)
+	if err = json.Unmarshal([]byte(value), job); err != nil {
+		u.Log.WithField("action", "find user import").WithError(err).Error("Failed to decode import job")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return job, nil
+}
+
+// process menjalankan import baris per baris, progress disimpan ke redis secara berkala supaya bisa dipantau lewat FindById
+func (u *userImportUseCase) process(ctx context.Context, job *model.UserImportResponse, rows []importRow, columns map[string]int) {
+	job.Status = importStatusProcessing
+	u.saveProgress(ctx, job)
+
+	// email yang sama di beberapa baris tidak tertangkap validasi unique saat dry run karena belum ada yang disimpan
+	seen := make(map[string]int, len(rows))
+	for i, row := range rows {
+		email := strings.ToLower(cellValue(row, columns, "email"))
+		var rowErrors map[string]string
+		if first, ok := seen[email]; ok && email != "" {
+			rowErrors = map[string]string{"email": fmt.Sprintf("email is duplicated with row %d.", first)}
+		} else {
+			if email != "" {
+				seen[email] = row.Number
+			}
+			rowErrors = u.importRow(ctx, job, row, columns)
+		}
+
+		job.ProcessedRows++
+		if rowErrors != nil {
+			job.FailedRows++
+			job.Errors = append(job.Errors, model.ImportRowError{Row: row.Number, Email: email, Errors: rowErrors})
+		} else {
+			job.ValidRows++
+			if !job.DryRun {
+				job.CreatedRows++
+			}
+		}
+
+		if (i+1)%50 == 0 {
+			u.saveProgress(ctx, job)
+		}
+	}
+
+	finishedAt := time.Now()
+	job.Status = importStatusCompleted
+	job.FinishedAt = &finishedAt
+	u.saveProgress(ctx, job)
+
+	u.Log.WithField("action", "import user").WithFields(logrus.Fields{
+		"event":        "users_imported",
+		"actor_id":     job.ImportedBy,
+		"import_id":    job.ID,
+		"file_name":    job.FileName,
+		"dry_run":      job.DryRun,
+		"invite":       job.Invite,
+		"total_rows":   job.TotalRows,
+		"created_rows": job.CreatedRows,
+		"failed_rows":  job.FailedRows,
+	}).Info("User import completed")
+}
+
+// importRow memvalidasi (dry run) atau membuat user dari satu baris, error dikembalikan per field
+func (u *userImportUseCase) importRow(ctx context.Context, job *model.UserImportResponse, row importRow, columns map[string]int) map[string]string {
+	var phone *string
+	if value := cellValue(row, columns, "phone"); value != "" {
+		phone = &value
+	}
+
+	var err error
+	if job.Invite {
+		request := &model.InviteUserRequest{
+			InvitedBy: job.ImportedBy,
+			Name:      cellValue(row, columns, "name"),
+			Email:     cellValue(row, columns, "email"),
+			Phone:     phone,
+			Role:      cellValue(row, columns, "role"),
+		}
+
+		if job.DryRun {
+			u.normalizePhone(request.Phone)
+			err = u.Validate.Struct(request)
+		} else {
+			_, err = u.UserUseCase.Invite(ctx, request)
+		}
+		return importRowErrors(err)
+	}
+
+	// kolom is_active kosong atau tidak dipetakan berarti user langsung aktif
+	isActive := true
+	if value := cellValue(row, columns, "is_active"); value != "" {
+		if isActive, err = strconv.ParseBool(value); err != nil {
+			return map[string]string{"is_active": "is_active field must be true or false."}
+		}
+	}
+
+	password := cellValue(row, columns, "password")
+	request := &model.CreateUserRequest{
+		Name:            cellValue(row, columns, "name"),
+		Email:           cellValue(row, columns, "email"),
+		Password:        password,
+		ConfirmPassword: password,
+		Phone:           phone,
+		IsActive:        isActive,
+	}
+
+	if job.DryRun {
+		u.normalizePhone(request.Phone)
+		err = u.Validate.Struct(request)
+	} else {
+		_, err = u.UserUseCase.Create(ctx, request)
+	}
+	return importRowErrors(err)
+}
+
+func (u *userImportUseCase) save(ctx context.Context, job *model.UserImportResponse) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	ttl := u.Config.GetInt("import.result_ttl")
+	if ttl <= 0 {
+		ttl = 86400
+	}
+
+	return u.Redis.SetEx(ctx, "user_import:"+job.ID, value, time.Duration(ttl)*time.Second).Err()
+}
+
+// saveProgress hanya mencatat error di log, kegagalan menyimpan progress tidak menghentikan import
+func (u *userImportUseCase) saveProgress(ctx context.Context, job *model.UserImportResponse) {
+	if err := u.save(ctx, job); err != nil {
+		u.Log.WithField("action", "import user").WithField("import_id", job.ID).WithError(err).Error("Failed to save import progress in redis")
+	}
+}
+
+// importColumns memetakan field import ke index kolom dari baris header, berdasarkan request.Mapping atau nama kolom
+// yang sama dengan nama field (tidak case sensitive)
+func importColumns(header []string, request *model.ImportUserRequest) (map[string]int, error) {
+	allowed := importPasswordFields
+	if request.Invite {
+		allowed = importInviteFields
+	}
+
+	mapping := make(map[string]string)
+	if request.Mapping != "" {
+		if err := json.Unmarshal([]byte(request.Mapping), &mapping); err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Mapping must be a JSON object of field and column name")
+		}
+	}
+	for field := range mapping {
+		if !slices.Contains(importFields, field) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Unknown import field %q", field))
+		}
+		if !slices.Contains(allowed, field) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Field %q can not be imported in this mode", field))
+		}
+	}
+
+	columns := make(map[string]int)
+	for _, field := range allowed {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+
+		index := slices.IndexFunc(header, func(column string) bool {
+			return strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name))
+		})
+		if index < 0 {
+			if mapped {
+				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Column %q for field %q not found", name, field))
+			}
+			continue
+		}
+		columns[field] = index
+	}
+
+	required := []string{"name", "email"}
+	if !request.Invite {
+		required = append(required, "password")
+	}
+	for _, field := range required {
+		if _, ok := columns[field]; !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Column for field %q is required", field))
+		}
+	}
+
+	return columns, nil
+}
+
+func cellValue(row importRow, columns map[string]int, field string) string {
+	index, ok := columns[field]
+	if !ok || index >= len(row.Values) {
+		return ""
+	}
+
+	return strings.TrimSpace(row.Values[index])
+}
+
+// importRowErrors mengubah error validasi atau error use case menjadi pesan per field
+func importRowErrors(err error) map[string]string {
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		rowErrors := make(map[string]string)
+		for field, message := range validators.ParseErrors(validationErrors) {
+			rowErrors[field] = message.Message
+		}
+		return rowErrors
+	}
+
+	var fiberError *fiber.Error
+	if errors.As(err, &fiberError) {
+		return map[string]string{"row": fiberError.Message}
+	}
+
+	return map[string]string{"row": err.Error()}
+}
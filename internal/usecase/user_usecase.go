@@ -2,14 +2,23 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"github.com/alfianyulianto/pds-service/internal/entity"
 	"github.com/alfianyulianto/pds-service/internal/model"
 	"github.com/alfianyulianto/pds-service/internal/model/converter"
 	"github.com/alfianyulianto/pds-service/internal/repository"
 	"github.com/alfianyulianto/pds-service/internal/utils"
+	"github.com/alfianyulianto/pds-service/pkg/auth"
+	"github.com/alfianyulianto/pds-service/pkg/email"
 	"github.com/alfianyulianto/pds-service/pkg/response"
+	"github.com/alfianyulianto/pds-service/pkg/throttle"
 	"github.com/gofiber/fiber/v2"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"strings"
+	"time"
 )
 
 type UserUseCase interface {
@@ -18,15 +27,29 @@ type UserUseCase interface {
 	List(ctx context.Context, request *model.SearchUserRequest) (*[]model.UserResponse, *response.Pagination, error)
 	FindById(ctx context.Context, id any) (*model.UserResponse, error)
 	Delete(ctx context.Context, id any) error
+	Unlock(ctx context.Context, id any) error
+	Suspend(ctx context.Context, request *model.SuspendUserRequest) (*model.UserResponse, error)
+	Reinstate(ctx context.Context, request *model.ReinstateUserRequest) (*model.UserResponse, error)
+	Invite(ctx context.Context, request *model.InviteUserRequest) (*model.UserResponse, error)
+	ResendInvitation(ctx context.Context, request *model.InvitationRequest) (*model.UserResponse, error)
+	RevokeInvitation(ctx context.Context, request *model.InvitationRequest) error
+	AcceptInvitation(ctx context.Context, request *model.AcceptInvitationRequest) (*model.UserResponse, error)
+	ApproveRegistration(ctx context.Context, request *model.ApproveRegistrationRequest) (*model.UserResponse, error)
+	RejectRegistration(ctx context.Context, request *model.RejectRegistrationRequest) error
 }
 
 type userUseCase struct {
 	*BaseUseCase
-	UserRepository repository.UserRepository
+	UserRepository            repository.UserRepository
+	Throttle                  *throttle.Throttle
+	JwtService                *auth.JWTService
+	PasswordHistoryRepository repository.PasswordHistoryRepository
+	EmailService              *email.EmailService
+	RoleUseCase               RoleUseCase
 }
 
-func NewUserUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository) UserUseCase {
-	return &userUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository}
+func NewUserUseCase(baseUseCase *BaseUseCase, userRepository repository.UserRepository, throttle *throttle.Throttle, jwtService *auth.JWTService, passwordHistoryRepository repository.PasswordHistoryRepository, emailService *email.EmailService, roleUseCase RoleUseCase) UserUseCase {
+	return &userUseCase{BaseUseCase: baseUseCase, UserRepository: userRepository, Throttle: throttle, JwtService: jwtService, PasswordHistoryRepository: passwordHistoryRepository, EmailService: emailService, RoleUseCase: roleUseCase}
 }
 
 func (u *userUseCase) Create(ctx context.Context, request *model.CreateUserRequest) (*model.UserResponse, error) {
@@ -36,6 +59,7 @@ func (u *userUseCase) Create(ctx context.Context, request *model.CreateUserReque
 	tx := u.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
+	u.normalizePhone(request.Phone)
 	if err := u.Validate.Struct(request); err != nil {
 		u.Log.WithField("action", "create user").WithError(err).Warn("Failed to validate request body")
 		return nil, err
@@ -55,19 +79,26 @@ func (u *userUseCase) Create(ctx context.Context, request *model.CreateUserReque
 		user.Avatar = &filePath
 	}
 
-	password, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hash, err := u.Hasher.Hash(user.Password)
 	if err != nil {
 		u.Log.WithField("action", "login").WithError(err).Error("Failed to hash password")
 		return nil, fiber.ErrInternalServerError
 	}
 
-	user.Password = string(password)
+	user.Password = hash
+	now := time.Now()
+	user.PasswordChangedAt = &now
 
 	if err = u.UserRepository.Create(tx, user); err != nil {
 		u.Log.WithField("action", "create user").WithError(err).Error("Failed to create user")
 		return nil, fiber.ErrInternalServerError
 	}
 
+	if err = u.savePasswordHistory(tx, u.PasswordHistoryRepository, user); err != nil {
+		u.Log.WithField("action", "create user").WithError(err).Error("Failed to save password history")
+		return nil, fiber.ErrInternalServerError
+	}
+
 	if err = tx.Commit().Error; err != nil {
 		u.Log.WithField("action", "create user").WithError(err).Error("Failed to commit transaction")
 		return nil, fiber.ErrInternalServerError
@@ -92,13 +123,21 @@ func (u *userUseCase) Update(ctx context.Context, request *model.UpdateUserReque
 		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
 	}
 
+	u.normalizePhone(request.Phone)
 	if err := u.Validate.Struct(request); err != nil {
 		u.Log.WithField("action", "update user").WithError(err).Warn("Failed to validate request body")
 		return nil, err
 	}
 
+	wasActive := user.IsActive
+	oldPhone := user.Phone
 	user = converter.UpdateRequestToUser(user, request)
 
+	// nomor baru harus diverifikasi ulang oleh user
+	if !samePhone(oldPhone, user.Phone) {
+		user.PhoneVerifiedAt = nil
+	}
+
 	if request.Avatar != nil {
 		var err error
 		filePath, err = u.Storage.UploadFile(request.Avatar, "user")
@@ -113,13 +152,15 @@ func (u *userUseCase) Update(ctx context.Context, request *model.UpdateUserReque
 	}
 
 	if request.Password != "" {
-		password, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+		hash, err := u.Hasher.Hash(user.Password)
 		if err != nil {
 			u.Log.WithField("action", "login").WithError(err).Error("Failed to hash password")
 			return nil, fiber.ErrInternalServerError
 		}
 
-		user.Password = string(password)
+		user.Password = hash
+		now := time.Now()
+		user.PasswordChangedAt = &now
 	}
 
 	if err := u.UserRepository.Update(tx, user); err != nil {
@@ -127,14 +168,30 @@ func (u *userUseCase) Update(ctx context.Context, request *model.UpdateUserReque
 		return nil, fiber.ErrInternalServerError
 	}
 
+	if request.Password != "" {
+		if err := u.savePasswordHistory(tx, u.PasswordHistoryRepository, user); err != nil {
+			u.Log.WithField("action", "update user").WithError(err).Error("Failed to save password history")
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		u.Log.WithField("action", "update user").WithError(err).Error("Failed to commit transaction")
 		return nil, fiber.ErrInternalServerError
 	}
 
-	user.Avatar = utils.BuildFileURL(u.Config, filePath)
-
+	// perubahan sudah tersimpan, avatar baru tidak boleh dihapus walaupun revoke token di bawah gagal
 	success = true
+
+	// password diganti admin atau user dinonaktifkan, semua token lama tidak berlaku lagi
+	if request.Password != "" || (wasActive && !user.IsActive) {
+		if err := u.JwtService.InvalidateTokens(ctx, user.ID, ""); err != nil {
+			u.Log.WithField("action", "update user").WithError(err).Error("Failed to invalidate user tokens")
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	user.Avatar = utils.BuildFileURL(u.Config, filePath)
 	return converter.UserToResponse(user), nil
 }
 
@@ -205,5 +262,536 @@ func (u *userUseCase) Delete(ctx context.Context, id any) error {
 		return fiber.ErrInternalServerError
 	}
 
+	if err := u.JwtService.InvalidateTokens(ctx, user.ID, ""); err != nil {
+		u.Log.WithField("action", "delete user").WithError(err).Error("Failed to invalidate user tokens")
+		return fiber.ErrInternalServerError
+	}
+
 	return nil
 }
+
+func (u *userUseCase) Unlock(ctx context.Context, id any) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, id); err != nil {
+		u.Log.WithField("action", "unlock user").WithError(err).Error("Failed to find user")
+		return fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "unlock user").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	userEmail := strings.ToLower(user.Email)
+	for _, rule := range []string{"login_email", "reset_password_email"} {
+		if err := u.Throttle.Clear(ctx, rule, userEmail); err != nil {
+			u.Log.WithField("action", "unlock user").WithError(err).Error("Failed to clear throttle")
+			return fiber.ErrInternalServerError
+		}
+	}
+	if err := u.Throttle.Clear(ctx, "two_factor_user", user.ID.String()); err != nil {
+		u.Log.WithField("action", "unlock user").WithError(err).Error("Failed to clear throttle")
+		return fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "unlock user").WithField("user_id", user.ID).Info("User account unlocked")
+	return nil
+}
+
+func (u *userUseCase) Suspend(ctx context.Context, request *model.SuspendUserRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "suspend user").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	if request.ActorID == request.ID {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "You cannot suspend yourself")
+	}
+
+	if request.Until != nil && !request.Until.After(time.Now()) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Suspension end time must be in the future")
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "suspend user").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	user.SuspendedUntil = request.Until
+	user.SuspensionReason = &request.Reason
+	user.SuspendedBy = &request.ActorID
+	if err := u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "suspend user").WithError(err).Error("Failed to suspend user")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "suspend user").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := u.JwtService.InvalidateTokens(ctx, user.ID, ""); err != nil {
+		u.Log.WithField("action", "suspend user").WithError(err).Error("Failed to invalidate user tokens")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "suspend user").WithFields(logrus.Fields{
+		"event":           "user_suspended",
+		"actor_id":        request.ActorID,
+		"user_id":         user.ID,
+		"reason":          request.Reason,
+		"suspended_until": request.Until,
+	}).Info("User suspended")
+
+	go func() {
+		suspendedUntil := "diaktifkan kembali oleh administrator"
+		if user.SuspendedUntil != nil {
+			suspendedUntil = utils.FormatTime(*user.SuspendedUntil)
+		}
+
+		err := email.QuickSendAccountSuspended(u.EmailService, user.Email, user.Name, request.Reason, suspendedUntil)
+		if err != nil {
+			u.Log.WithField("action", "suspend user").WithError(err).Error("Failed to send account suspended email")
+		}
+	}()
+
+	return converter.UserToResponse(user), nil
+}
+
+// Reinstate mencabut suspend dan mengaktifkan kembali user yang dinonaktifkan
+func (u *userUseCase) Reinstate(ctx context.Context, request *model.ReinstateUserRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "reinstate user").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "reinstate user").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if user.IsActive && !user.Suspended() {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "User is not suspended")
+	}
+
+	user.IsActive = true
+	user.SuspendedUntil = nil
+	user.SuspensionReason = nil
+	user.SuspendedBy = nil
+	if err := u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "reinstate user").WithError(err).Error("Failed to reinstate user")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "reinstate user").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "reinstate user").WithFields(logrus.Fields{
+		"event":    "user_reinstated",
+		"actor_id": request.ActorID,
+		"user_id":  user.ID,
+	}).Info("User reinstated")
+
+	go func() {
+		err := email.QuickSendAccountReinstated(u.EmailService, user.Email, user.Name)
+		if err != nil {
+			u.Log.WithField("action", "reinstate user").WithError(err).Error("Failed to send account reinstated email")
+		}
+	}()
+
+	return converter.UserToResponse(user), nil
+}
+
+// Invite membuat user tanpa password yang belum aktif, lalu mengirim link undangan ke email user.
+// User baru bisa login setelah membuat password lewat AcceptInvitation. Role selain role default User hanya boleh
+// diberikan jika pengundang juga punya permission roles.assign
+func (u *userUseCase) Invite(ctx context.Context, request *model.InviteUserRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	u.normalizePhone(request.Phone)
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "invite user").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	inviter := new(entity.User)
+	if err := u.UserRepository.FindById(tx, inviter, request.InvitedBy); err != nil {
+		u.Log.WithField("action", "invite user").WithError(err).Error("Failed to find inviter")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if request.Role != "" && request.Role != "User" {
+		permissions, err := u.RoleUseCase.Permissions(ctx, inviter.Role)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(permissions, "roles.assign") {
+			u.Log.WithField("action", "invite user").WithFields(logrus.Fields{
+				"event":    "invite_role_denied",
+				"actor_id": inviter.ID,
+				"email":    request.Email,
+				"role":     request.Role,
+			}).Warn("Attempt to invite user with a role without roles.assign permission")
+			return nil, fiber.NewError(fiber.StatusForbidden, "You are not allowed to assign this role")
+		}
+	}
+
+	user := converter.InviteRequestToUser(request)
+	token, err := u.issueInvitation(user)
+	if err != nil {
+		u.Log.WithField("action", "invite user").WithError(err).Error("Failed to generate invitation token")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.UserRepository.Create(tx, user); err != nil {
+		u.Log.WithField("action", "invite user").WithError(err).Error("Failed to create invited user")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "invite user").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "invite user").WithFields(logrus.Fields{
+		"event":    "user_invited",
+		"actor_id": inviter.ID,
+		"user_id":  user.ID,
+		"email":    user.Email,
+		"role":     user.Role,
+	}).Info("User invited")
+
+	u.sendInvitation(user, inviter, token, "invite user")
+
+	return converter.UserToResponse(user), nil
+}
+
+// ResendInvitation menerbitkan token undangan baru dan memperpanjang masa berlaku, link undangan sebelumnya tidak berlaku lagi
+func (u *userUseCase) ResendInvitation(ctx context.Context, request *model.InvitationRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "resend invitation").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "resend invitation").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if !user.InvitationPending() {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "User has no pending invitation")
+	}
+
+	inviter := new(entity.User)
+	if err := u.UserRepository.FindById(tx, inviter, request.ActorID); err != nil {
+		u.Log.WithField("action", "resend invitation").WithError(err).Error("Failed to find inviter")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	token, err := u.issueInvitation(user)
+	if err != nil {
+		u.Log.WithField("action", "resend invitation").WithError(err).Error("Failed to generate invitation token")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "resend invitation").WithError(err).Error("Failed to update invitation")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "resend invitation").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "resend invitation").WithFields(logrus.Fields{
+		"event":    "user_invitation_resent",
+		"actor_id": request.ActorID,
+		"user_id":  user.ID,
+	}).Info("User invitation resent")
+
+	u.sendInvitation(user, inviter, token, "resend invitation")
+
+	return converter.UserToResponse(user), nil
+}
+
+// RevokeInvitation menghapus permanen user yang undangannya belum diterima, sehingga email bisa diundang atau didaftarkan ulang
+func (u *userUseCase) RevokeInvitation(ctx context.Context, request *model.InvitationRequest) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "revoke invitation").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "revoke invitation").WithError(err).Error("Failed to find user")
+		return fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if !user.InvitationPending() {
+		return fiber.NewError(fiber.StatusBadRequest, "User has no pending invitation")
+	}
+
+	if err := u.UserRepository.DeleteRelations(tx, user); err != nil {
+		u.Log.WithField("action", "revoke invitation").WithError(err).Error("Failed to delete user relations")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := u.UserRepository.HardDelete(tx, user); err != nil {
+		u.Log.WithField("action", "revoke invitation").WithError(err).Error("Failed to delete invited user")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "revoke invitation").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "revoke invitation").WithFields(logrus.Fields{
+		"event":    "user_invitation_revoked",
+		"actor_id": request.ActorID,
+		"user_id":  user.ID,
+		"email":    user.Email,
+	}).Info("User invitation revoked")
+
+	return nil
+}
+
+// AcceptInvitation membuat password dan mengaktifkan user yang diundang. Link undangan dikirim ke email user,
+// jadi email langsung dianggap terverifikasi
+func (u *userUseCase) AcceptInvitation(ctx context.Context, request *model.AcceptInvitationRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	// password divalidasi setelah user diketahui, supaya password yang mengandung nama atau email ditolak
+	if err := u.Validate.StructExcept(request, "Password"); err != nil {
+		u.Log.WithField("action", "accept invitation").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindByInvitationTokenHash(tx, user, utils.HashToken(request.Token)); err != nil {
+		u.Log.WithField("action", "accept invitation").WithError(err).Warn("Failed to find user by invitation token")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid or expired invitation token")
+	}
+
+	if user.InvitationStatus() != "pending" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid or expired invitation token")
+	}
+
+	request.ID = user.ID
+	if err := u.Validate.StructPartial(request, "Password"); err != nil {
+		u.Log.WithField("action", "accept invitation").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	hash, err := u.Hasher.Hash(request.Password)
+	if err != nil {
+		u.Log.WithField("action", "accept invitation").WithError(err).Error("Failed to hash password")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	now := time.Now()
+	user.Password = hash
+	user.PasswordChangedAt = &now
+	user.EmailVerifiedAt = &now
+	user.IsActive = true
+	user.InvitationTokenHash = nil
+	user.InvitationAcceptedAt = &now
+	if err = u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "accept invitation").WithError(err).Error("Failed to accept invitation")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = u.savePasswordHistory(tx, u.PasswordHistoryRepository, user); err != nil {
+		u.Log.WithField("action", "accept invitation").WithError(err).Error("Failed to save password history")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "accept invitation").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "accept invitation").WithFields(logrus.Fields{
+		"event":   "user_invitation_accepted",
+		"user_id": user.ID,
+	}).Info("User invitation accepted")
+
+	go func() {
+		err := email.QuickSendWelcome(u.EmailService, user.Email, user.Name)
+		if err != nil {
+			u.Log.WithField("action", "accept invitation").WithError(err).Error("Failed to send welcome email")
+		}
+	}()
+
+	return converter.UserToResponse(user), nil
+}
+
+// ApproveRegistration menyetujui pendaftaran yang dibuat saat registration.mode = approval, user bisa login setelahnya
+func (u *userUseCase) ApproveRegistration(ctx context.Context, request *model.ApproveRegistrationRequest) (*model.UserResponse, error) {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "approve registration").WithError(err).Warn("Failed to validate request body")
+		return nil, err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "approve registration").WithError(err).Error("Failed to find user")
+		return nil, fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if !user.ApprovalPending() {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "User has no pending registration")
+	}
+
+	status := approvalStatusApproved
+	approvedAt := time.Now()
+	user.ApprovalStatus = &status
+	user.ApprovedBy = &request.ActorID
+	user.ApprovedAt = &approvedAt
+	if err := u.UserRepository.Update(tx, user); err != nil {
+		u.Log.WithField("action", "approve registration").WithError(err).Error("Failed to approve registration")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "approve registration").WithError(err).Error("Failed to commit transaction")
+		return nil, fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "approve registration").WithFields(logrus.Fields{
+		"event":    "registration_approved",
+		"actor_id": request.ActorID,
+		"user_id":  user.ID,
+	}).Info("Registration approved")
+
+	go func() {
+		err := email.QuickSendRegistrationApproved(u.EmailService, user.Email, user.Name)
+		if err != nil {
+			u.Log.WithField("action", "approve registration").WithError(err).Error("Failed to send registration approved email")
+		}
+	}()
+
+	return converter.UserToResponse(user), nil
+}
+
+// RejectRegistration menolak pendaftaran yang menunggu persetujuan, user dihapus permanen sehingga email bisa didaftarkan ulang
+func (u *userUseCase) RejectRegistration(ctx context.Context, request *model.RejectRegistrationRequest) error {
+	tx := u.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := u.Validate.Struct(request); err != nil {
+		u.Log.WithField("action", "reject registration").WithError(err).Warn("Failed to validate request body")
+		return err
+	}
+
+	user := new(entity.User)
+	if err := u.UserRepository.FindById(tx, user, request.ID); err != nil {
+		u.Log.WithField("action", "reject registration").WithError(err).Error("Failed to find user")
+		return fiber.NewError(fiber.StatusNotFound, "User data not found")
+	}
+
+	if !user.ApprovalPending() {
+		return fiber.NewError(fiber.StatusBadRequest, "User has no pending registration")
+	}
+
+	if err := u.UserRepository.DeleteRelations(tx, user); err != nil {
+		u.Log.WithField("action", "reject registration").WithError(err).Error("Failed to delete user relations")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := u.UserRepository.HardDelete(tx, user); err != nil {
+		u.Log.WithField("action", "reject registration").WithError(err).Error("Failed to delete rejected user")
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		u.Log.WithField("action", "reject registration").WithError(err).Error("Failed to commit transaction")
+		return fiber.ErrInternalServerError
+	}
+
+	u.Log.WithField("action", "reject registration").WithFields(logrus.Fields{
+		"event":    "registration_rejected",
+		"actor_id": request.ActorID,
+		"user_id":  user.ID,
+		"email":    user.Email,
+		"reason":   request.Reason,
+	}).Info("Registration rejected")
+
+	go func() {
+		err := email.QuickSendRegistrationRejected(u.EmailService, user.Email, user.Name, request.Reason)
+		if err != nil {
+			u.Log.WithField("action", "reject registration").WithError(err).Error("Failed to send registration rejected email")
+		}
+	}()
+
+	return nil
+}
+
+// issueInvitation membuat token undangan baru untuk user, yang disimpan hanya hash-nya
+func (u *userUseCase) issueInvitation(user *entity.User) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	now := time.Now()
+	expiresAt := now.Add(u.invitationExpire())
+	tokenHash := utils.HashToken(token)
+	user.InvitedAt = &now
+	user.InvitationTokenHash = &tokenHash
+	user.InvitationExpiresAt = &expiresAt
+
+	return token, nil
+}
+
+func (u *userUseCase) invitationExpire() time.Duration {
+	expireDuration := u.Config.GetInt("invitation.expire_duration")
+	if expireDuration <= 0 {
+		expireDuration = 259200
+	}
+
+	return time.Duration(expireDuration) * time.Second
+}
+
+func (u *userUseCase) sendInvitation(user *entity.User, inviter *entity.User, token string, action string) {
+	expire := u.invitationExpire()
+	expireIn := fmt.Sprintf("%d jam", int(expire.Hours()))
+	if expire < time.Hour {
+		expireIn = fmt.Sprintf("%d menit", int(expire.Minutes()))
+	}
+
+	go func() {
+		acceptURL := fmt.Sprintf("https://alfian.my.id/accounts/invitation/accept?token=%s", token)
+		err := email.QuickSendInvitation(u.EmailService, user.Email, user.Name, inviter.Name, acceptURL, expireIn)
+		if err != nil {
+			u.Log.WithField("action", action).WithError(err).Error("Failed to send invitation email")
+		}
+	}()
+}
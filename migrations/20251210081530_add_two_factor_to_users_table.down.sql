@@ -0,0 +1,5 @@
+drop table if exists user_recovery_codes;
+
+alter table users
+    drop column two_factor_enabled_at,
+    drop column two_factor_secret;
@@ -0,0 +1,13 @@
+alter table users
+    add column two_factor_secret varchar(64) null after password,
+    add column two_factor_enabled_at timestamp null after two_factor_secret;
+
+create table if not exists user_recovery_codes (
+    id char(36) primary key,
+    user_id char(36) not null,
+    code_hash char(64) not null,
+    used_at timestamp null,
+    created_at timestamp not null default current_timestamp,
+    index idx_user_recovery_codes_user_id (user_id),
+    constraint fk_user_recovery_codes_user_id foreign key (user_id) references users (id) on delete cascade
+)engine = InnoDB;
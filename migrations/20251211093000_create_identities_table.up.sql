@@ -0,0 +1,12 @@
+create table if not exists identities (
+    id char(36) primary key,
+    user_id char(36) not null,
+    provider varchar(50) not null,
+    subject varchar(255) not null,
+    email varchar(255) null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp on update current_timestamp,
+    unique index idx_identities_provider_subject (provider, subject),
+    index idx_identities_user_id (user_id),
+    constraint fk_identities_user_id foreign key (user_id) references users (id) on delete cascade
+)engine = InnoDB;
@@ -0,0 +1,17 @@
+create table if not exists api_keys (
+    id char(36) primary key,
+    user_id char(36) not null,
+    name varchar(100) not null,
+    prefix varchar(20) not null,
+    key_hash char(64) not null,
+    scopes varchar(500) not null,
+    last_used_at timestamp null,
+    last_used_ip varchar(45) null,
+    expires_at timestamp null,
+    revoked_at timestamp null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp on update current_timestamp,
+    unique index idx_api_keys_key_hash (key_hash),
+    index idx_api_keys_user_id (user_id),
+    constraint fk_api_keys_user_id foreign key (user_id) references users (id) on delete cascade
+)engine = InnoDB;
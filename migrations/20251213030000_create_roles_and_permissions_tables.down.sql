@@ -0,0 +1,8 @@
+update users set role = 'User' where role not in ('Admin', 'User');
+
+alter table users
+    modify column role enum('Admin', 'User') default 'User';
+
+drop table if exists role_permissions;
+drop table if exists permissions;
+drop table if exists roles;
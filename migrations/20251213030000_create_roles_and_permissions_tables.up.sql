@@ -0,0 +1,42 @@
+create table if not exists roles (
+    id char(36) primary key,
+    name varchar(50) not null unique,
+    description varchar(255) null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp on update current_timestamp
+)engine = InnoDB;
+
+create table if not exists permissions (
+    id char(36) primary key,
+    name varchar(100) not null unique,
+    description varchar(255) null,
+    created_at timestamp not null default current_timestamp
+)engine = InnoDB;
+
+create table if not exists role_permissions (
+    role_id char(36) not null,
+    permission_id char(36) not null,
+    primary key (role_id, permission_id),
+    constraint fk_role_permissions_role_id foreign key (role_id) references roles (id) on delete cascade,
+    constraint fk_role_permissions_permission_id foreign key (permission_id) references permissions (id) on delete cascade
+)engine = InnoDB;
+
+insert into roles (id, name, description) values
+    (uuid(), 'Admin', 'Administrator dengan semua permission'),
+    (uuid(), 'User', 'Role default untuk user yang mendaftar');
+
+insert into permissions (id, name, description) values
+    (uuid(), 'users.read', 'Melihat daftar dan detail user'),
+    (uuid(), 'users.create', 'Membuat user'),
+    (uuid(), 'users.update', 'Mengubah user'),
+    (uuid(), 'users.delete', 'Menghapus user'),
+    (uuid(), 'users.unlock', 'Membuka kunci akun user'),
+    (uuid(), 'roles.read', 'Melihat daftar role dan permission'),
+    (uuid(), 'roles.manage', 'Membuat, mengubah dan menghapus role'),
+    (uuid(), 'roles.assign', 'Mengganti role user');
+
+insert into role_permissions (role_id, permission_id)
+select roles.id, permissions.id from roles cross join permissions where roles.name = 'Admin';
+
+alter table users
+    modify column role varchar(50) not null default 'User';
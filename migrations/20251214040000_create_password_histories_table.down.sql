@@ -0,0 +1,4 @@
+drop table if exists password_histories;
+
+alter table users
+    drop column password_changed_at;
@@ -0,0 +1,13 @@
+alter table users
+    add column password_changed_at timestamp null after password;
+
+update users set password_changed_at = created_at;
+
+create table if not exists password_histories (
+    id char(36) primary key,
+    user_id char(36) not null,
+    password varchar(255) not null,
+    created_at timestamp not null default current_timestamp,
+    index idx_password_histories_user_id (user_id),
+    constraint fk_password_histories_user_id foreign key (user_id) references users (id) on delete cascade
+)engine = InnoDB;
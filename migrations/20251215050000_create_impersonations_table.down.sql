@@ -0,0 +1,3 @@
+delete from permissions where name = 'users.impersonate';
+
+drop table if exists impersonations;
@@ -0,0 +1,21 @@
+create table if not exists impersonations (
+    id char(36) primary key,
+    impersonator_id char(36) not null,
+    user_id char(36) not null,
+    reason varchar(255) null,
+    ip_address varchar(45) null,
+    user_agent varchar(255) null,
+    expires_at timestamp not null,
+    ended_at timestamp null,
+    created_at timestamp not null default current_timestamp,
+    index idx_impersonations_impersonator_id (impersonator_id),
+    index idx_impersonations_user_id (user_id),
+    constraint fk_impersonations_impersonator_id foreign key (impersonator_id) references users (id) on delete cascade,
+    constraint fk_impersonations_user_id foreign key (user_id) references users (id) on delete cascade
+)engine = InnoDB;
+
+insert into permissions (id, name, description) values
+    (uuid(), 'users.impersonate', 'Masuk sebagai user lain untuk keperluan support');
+
+insert into role_permissions (role_id, permission_id)
+select roles.id, permissions.id from roles cross join permissions where roles.name = 'Admin' and permissions.name = 'users.impersonate';
@@ -0,0 +1,6 @@
+delete from permissions where name = 'users.suspend';
+
+alter table users
+    drop column suspended_by,
+    drop column suspension_reason,
+    drop column suspended_until;
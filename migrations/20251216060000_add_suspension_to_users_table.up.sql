@@ -0,0 +1,10 @@
+alter table users
+    add column suspended_until timestamp null after is_active,
+    add column suspension_reason varchar(255) null after suspended_until,
+    add column suspended_by char(36) null after suspension_reason;
+
+insert into permissions (id, name, description) values
+    (uuid(), 'users.suspend', 'Suspend dan mengaktifkan kembali user');
+
+insert into role_permissions (role_id, permission_id)
+select roles.id, permissions.id from roles cross join permissions where roles.name = 'Admin' and permissions.name = 'users.suspend';
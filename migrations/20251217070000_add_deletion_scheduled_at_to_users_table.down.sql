@@ -0,0 +1,3 @@
+alter table users
+    drop index idx_users_deletion_scheduled_at,
+    drop column deletion_scheduled_at;
@@ -0,0 +1,3 @@
+alter table users
+    add column deletion_scheduled_at timestamp null after suspended_by,
+    add index idx_users_deletion_scheduled_at (deletion_scheduled_at);
@@ -0,0 +1,17 @@
+create table if not exists webauthn_credentials (
+    id char(36) primary key,
+    user_id char(36) not null,
+    credential_id varchar(1400) not null,
+    public_key blob not null,
+    algorithm int not null,
+    sign_count int unsigned not null default 0,
+    transports varchar(255) null,
+    aaguid char(36) null,
+    name varchar(100) not null,
+    last_used_at timestamp null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp on update current_timestamp,
+    unique index idx_webauthn_credentials_credential_id (credential_id(255)),
+    index idx_webauthn_credentials_user_id (user_id),
+    constraint fk_webauthn_credentials_user_id foreign key (user_id) references users (id) on delete cascade
+)engine = InnoDB;
@@ -0,0 +1,3 @@
+delete from permissions where name = 'oauth_clients.manage';
+
+drop table if exists oauth_clients;
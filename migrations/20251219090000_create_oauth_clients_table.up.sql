@@ -0,0 +1,20 @@
+create table if not exists oauth_clients (
+    id char(36) primary key,
+    client_id varchar(64) not null,
+    secret_hash char(64) not null,
+    name varchar(100) not null,
+    scopes varchar(1000) not null,
+    created_by char(36) null,
+    last_used_at timestamp null,
+    revoked_at timestamp null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp on update current_timestamp,
+    unique index idx_oauth_clients_client_id (client_id),
+    constraint fk_oauth_clients_created_by foreign key (created_by) references users (id) on delete set null
+)engine = InnoDB;
+
+insert into permissions (id, name, description) values
+    (uuid(), 'oauth_clients.manage', 'Mengelola OAuth client untuk komunikasi antar service');
+
+insert into role_permissions (role_id, permission_id)
+select roles.id, permissions.id from roles cross join permissions where roles.name = 'Admin' and permissions.name = 'oauth_clients.manage';
@@ -0,0 +1,3 @@
+delete from permissions where name = 'login_events.read';
+
+drop table if exists login_events;
@@ -0,0 +1,27 @@
+create table if not exists login_events (
+    id char(36) primary key,
+    user_id char(36) null,
+    email varchar(100) null,
+    method varchar(20) not null,
+    success tinyint(1) not null,
+    failure_reason varchar(50) null,
+    ip_address varchar(45) not null,
+    user_agent varchar(255) null,
+    browser varchar(50) null,
+    browser_version varchar(32) null,
+    os varchar(50) null,
+    os_version varchar(32) null,
+    device varchar(20) not null,
+    created_at timestamp not null default current_timestamp,
+    index idx_login_events_user_id_created_at (user_id, created_at),
+    index idx_login_events_email (email),
+    index idx_login_events_ip_address (ip_address),
+    index idx_login_events_created_at (created_at),
+    constraint fk_login_events_user_id foreign key (user_id) references users (id) on delete cascade
+)engine = InnoDB;
+
+insert into permissions (id, name, description) values
+    (uuid(), 'login_events.read', 'Melihat riwayat login semua user');
+
+insert into role_permissions (role_id, permission_id)
+select roles.id, permissions.id from roles cross join permissions where roles.name = 'Admin' and permissions.name = 'login_events.read';
@@ -0,0 +1,10 @@
+delete from permissions where name = 'users.invite';
+
+alter table users
+    drop index idx_users_invited_at,
+    drop index idx_users_invitation_token_hash,
+    drop column invitation_accepted_at,
+    drop column invitation_expires_at,
+    drop column invitation_token_hash,
+    drop column invited_at,
+    drop column invited_by;
@@ -0,0 +1,14 @@
+alter table users
+    add column invited_by char(36) null after deletion_scheduled_at,
+    add column invited_at timestamp null after invited_by,
+    add column invitation_token_hash char(64) null after invited_at,
+    add column invitation_expires_at timestamp null after invitation_token_hash,
+    add column invitation_accepted_at timestamp null after invitation_expires_at,
+    add unique index idx_users_invitation_token_hash (invitation_token_hash),
+    add index idx_users_invited_at (invited_at);
+
+insert into permissions (id, name, description) values
+    (uuid(), 'users.invite', 'Mengundang user dan mengelola undangan yang belum diterima');
+
+insert into role_permissions (role_id, permission_id)
+select roles.id, permissions.id from roles cross join permissions where roles.name = 'Admin' and permissions.name = 'users.invite';
@@ -0,0 +1,11 @@
+delete from permissions where name = 'registration.manage';
+
+alter table users
+    drop foreign key fk_users_registration_code_id,
+    drop index idx_users_approval_status,
+    drop column registration_code_id,
+    drop column approved_at,
+    drop column approved_by,
+    drop column approval_status;
+
+drop table if exists registration_codes;
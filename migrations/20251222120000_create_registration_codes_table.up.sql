@@ -0,0 +1,29 @@
+create table if not exists registration_codes (
+    id char(36) primary key,
+    prefix varchar(10) not null,
+    code_hash char(64) not null,
+    description varchar(255) null,
+    max_uses int null,
+    uses int not null default 0,
+    expires_at timestamp not null,
+    created_by char(36) null,
+    revoked_at timestamp null,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp on update current_timestamp,
+    unique index idx_registration_codes_code_hash (code_hash),
+    constraint fk_registration_codes_created_by foreign key (created_by) references users (id) on delete set null
+)engine = InnoDB;
+
+alter table users
+    add column approval_status varchar(20) null after invitation_accepted_at,
+    add column approved_by char(36) null after approval_status,
+    add column approved_at timestamp null after approved_by,
+    add column registration_code_id char(36) null after approved_at,
+    add index idx_users_approval_status (approval_status),
+    add constraint fk_users_registration_code_id foreign key (registration_code_id) references registration_codes (id) on delete set null;
+
+insert into permissions (id, name, description) values
+    (uuid(), 'registration.manage', 'Mengelola kode undangan registrasi serta menyetujui dan menolak pendaftaran');
+
+insert into role_permissions (role_id, permission_id)
+select roles.id, permissions.id from roles cross join permissions where roles.name = 'Admin' and permissions.name = 'registration.manage';
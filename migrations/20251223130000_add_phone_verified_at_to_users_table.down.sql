@@ -0,0 +1,3 @@
+alter table users
+    drop index idx_users_phone,
+    drop column phone_verified_at;
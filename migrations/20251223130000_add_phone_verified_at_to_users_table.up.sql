@@ -0,0 +1,3 @@
+alter table users
+    add column phone_verified_at timestamp null after phone,
+    add index idx_users_phone (phone);
@@ -0,0 +1,5 @@
+insert into permissions (id, name, description) values
+    (uuid(), 'users.import', 'Import user dari file CSV atau XLSX, dengan password atau lewat undangan email');
+
+insert into role_permissions (role_id, permission_id)
+select roles.id, permissions.id from roles cross join permissions where roles.name = 'Admin' and permissions.name = 'users.import';
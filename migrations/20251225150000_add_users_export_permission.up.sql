@@ -0,0 +1,5 @@
+insert into permissions (id, name, description) values
+    (uuid(), 'users.export', 'Export data user ke CSV, XLSX atau NDJSON');
+
+insert into role_permissions (role_id, permission_id)
+select roles.id, permissions.id from roles cross join permissions where roles.name = 'Admin' and permissions.name = 'users.export';
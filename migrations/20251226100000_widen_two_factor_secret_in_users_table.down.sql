@@ -0,0 +1,2 @@
+alter table users
+    modify column two_factor_secret varchar(64) null;
@@ -0,0 +1,2 @@
+alter table users
+    modify column password varchar(255) not null;
@@ -4,18 +4,49 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
 	"github.com/alfianyulianto/pds-service/internal/model"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	"time"
 )
 
+var (
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	ErrTokenInvalidated   = errors.New("token was issued before the user's tokens were invalidated")
+)
+
+// touchSession hanya memperbarui last_used_at jika session masih ada, supaya session yang sudah di revoke tidak hidup lagi tanpa TTL
+var touchSession = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return redis.call("HSET", KEYS[1], "last_used_at", ARGV[1])
+end
+return 0
+`)
+
+// consumeRefreshToken memakai refresh token secara atomic: token yang valid ditandai sudah di rotasi (return 1),
+// token yang sudah pernah di rotasi berarti dipakai ulang (return 2), selain itu token tidak dikenal (return 0)
+var consumeRefreshToken = redis.NewScript(`
+if redis.call("DEL", KEYS[1]) == 1 then
+	redis.call("DEL", KEYS[2])
+	redis.call("SET", KEYS[3], ARGV[1], "EX", ARGV[2])
+	return 1
+end
+if redis.call("EXISTS", KEYS[3]) == 1 then
+	return 2
+end
+return 0
+`)
+
 type JWTConfig struct {
 	AppName               string
 	ExpireDuration        int
 	RefreshExpireDuration int
-	SecreteKey            string
+	Keys                  *KeySet
 }
 
 type JWTService struct {
@@ -27,48 +58,184 @@ func NewJWTService(JWTConfig *JWTConfig, redis *redis.Client) *JWTService {
 	return &JWTService{JWTConfig: JWTConfig, Redis: redis}
 }
 
-func (s *JWTService) CreateToken(ctx context.Context, claims *model.UserClaimToken) (*model.AuthResponse, error) {
-	claims.Type = "access"
-	claims.RegisteredClaims = jwt.RegisteredClaims{
-		Issuer:    s.AppName,
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(s.ExpireDuration) * time.Second)),
-		ID:        uuid.NewString(), // tujuaannya untuk membedakan token satu dengan yang lain, sehingga bisa di revoke satu per satu, multi device bisa
+// CreateToken menerbitkan pasangan access dan refresh token baru dengan jti baru. Satu session adalah satu token family,
+// jika claims.SessionID kosong maka session (family) baru akan dibuat
+func (s *JWTService) CreateToken(ctx context.Context, claims *model.UserClaimToken, client *model.ClientInfo) (*model.AuthResponse, error) {
+	now := time.Now()
+	sessionID := claims.SessionID
+	newSession := sessionID == ""
+	if newSession {
+		sessionID = uuid.NewString()
 	}
 
-	mySigningKey := []byte(s.SecreteKey)
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedAccessToken, err := accessToken.SignedString(mySigningKey)
+	jti := uuid.NewString() // tujuaannya untuk membedakan token satu dengan yang lain, sehingga bisa di revoke satu per satu, multi device bisa
+	accessExpire := time.Duration(s.ExpireDuration) * time.Second
+	refreshExpire := time.Duration(s.RefreshExpireDuration) * time.Second
+	accessExpiresAt := now.Add(accessExpire)
+	refreshExpiresAt := now.Add(refreshExpire)
+
+	accessClaims := &model.UserClaimToken{
+		ID:         claims.ID,
+		Role:       claims.Role,
+		Type:       "access",
+		SessionID:  sessionID,
+		IssuedAtMs: now.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.AppName,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(accessExpiresAt),
+			ID:        jti,
+		},
+	}
+	signedAccessToken, err := s.Keys.Sign(accessClaims)
 	if err != nil {
 		return nil, err
 	}
-	key := fmt.Sprintf("access_token:%s", claims.RegisteredClaims.ID)
-	if err = s.Redis.SetEx(ctx, key, claims.ID, time.Duration(s.ExpireDuration)*time.Second).Err(); err != nil {
+
+	refreshClaims := *accessClaims
+	refreshClaims.Type = "refresh"
+	refreshClaims.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(refreshExpiresAt)
+	signedRefreshToken, err := s.Keys.Sign(&refreshClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	session := map[string]any{
+		"user_id":      claims.ID.String(),
+		"jti":          jti,
+		"last_used_at": now.Unix(),
+	}
+	if newSession {
+		session["created_at"] = now.Unix()
+	}
+	if client != nil {
+		session["device"] = client.Device
+		session["ip_address"] = client.IPAddress
+		session["user_agent"] = client.UserAgent
+	}
+
+	pipe := s.Redis.TxPipeline()
+	pipe.SetEx(ctx, fmt.Sprintf("access_token:%s", jti), claims.ID, accessExpire)
+	pipe.SetEx(ctx, fmt.Sprintf("refresh_token:%s", jti), claims.ID, refreshExpire)
+	pipe.HSet(ctx, sessionKey(sessionID), session)
+	pipe.Expire(ctx, sessionKey(sessionID), refreshExpire)
+	pipe.SAdd(ctx, userSessionsKey(claims.ID), sessionID)
+	pipe.Expire(ctx, userSessionsKey(claims.ID), refreshExpire)
+	if _, err = pipe.Exec(ctx); err != nil {
 		return nil, err
 	}
 
-	claims.Type = "refresh"
-	claims.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Duration(s.RefreshExpireDuration) * time.Second))
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedRefreshToken, err := refreshToken.SignedString(mySigningKey)
+	return &model.AuthResponse{
+		AccessToken:           signedAccessToken,
+		RefreshToken:          signedRefreshToken,
+		TokenType:             "Bearer",
+		ExpiresIn:             s.ExpireDuration,
+		AccessTokenExpiresAt:  &accessExpiresAt,
+		RefreshExpiresIn:      s.RefreshExpireDuration,
+		RefreshTokenExpiresAt: &refreshExpiresAt,
+	}, nil
+}
+
+// CreateImpersonationToken menerbitkan access token tanpa refresh token dan tanpa session untuk admin yang masuk sebagai user,
+// jti diambil dari claims.RegisteredClaims.ID supaya token bisa dihubungkan dengan catatan impersonation
+func (s *JWTService) CreateImpersonationToken(ctx context.Context, claims *model.UserClaimToken, expire time.Duration) (*model.AuthResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(expire)
+
+	accessClaims := &model.UserClaimToken{
+		ID:           claims.ID,
+		Role:         claims.Role,
+		Type:         "access",
+		Impersonator: claims.Impersonator,
+		IssuedAtMs:   now.UnixMilli(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.AppName,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        claims.RegisteredClaims.ID,
+		},
+	}
+	signedAccessToken, err := s.Keys.Sign(accessClaims)
 	if err != nil {
 		return nil, err
 	}
-	key = fmt.Sprintf("refresh_token:%s", claims.RegisteredClaims.ID)
-	if err = s.Redis.SetEx(ctx, key, claims.ID, time.Duration(s.RefreshExpireDuration)*time.Second).Err(); err != nil {
+
+	if err = s.Redis.SetEx(ctx, fmt.Sprintf("access_token:%s", accessClaims.RegisteredClaims.ID), claims.ID, expire).Err(); err != nil {
 		return nil, err
 	}
 
 	return &model.AuthResponse{
-		AccessToken:  signedAccessToken,
-		RefreshToken: signedRefreshToken,
+		AccessToken:          signedAccessToken,
+		TokenType:            "Bearer",
+		ExpiresIn:            int(expire.Seconds()),
+		AccessTokenExpiresAt: &expiresAt,
 	}, nil
 }
 
-func (s *JWTService) ParseAccessToken(ctx context.Context, accessToken string) (*model.UserClaimToken, error) {
-	token, err := jwt.ParseWithClaims(accessToken, new(model.UserClaimToken), func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.SecreteKey), nil
+// CreateClientToken menerbitkan access token untuk OAuth client (grant client_credentials) tanpa refresh token dan tanpa session,
+// claims berisi client_id dan scopes, bukan ID user
+func (s *JWTService) CreateClientToken(ctx context.Context, clientID string, scopes []string, expire time.Duration) (string, error) {
+	now := time.Now()
+	claims := &model.UserClaimToken{
+		Type:     "client",
+		ClientID: clientID,
+		Scopes:   scopes,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.AppName,
+			Subject:   clientID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+			ID:        uuid.NewString(),
+		},
+	}
+	signedToken, err := s.Keys.Sign(claims)
+	if err != nil {
+		return "", err
+	}
+
+	if err = s.Redis.SetEx(ctx, fmt.Sprintf("access_token:%s", claims.RegisteredClaims.ID), clientID, expire).Err(); err != nil {
+		return "", err
+	}
+
+	return signedToken, nil
+}
+
+// SignToken menandatangani token sekali pakai (misalnya magic link) dengan key aktif, mengembalikan token dan jti-nya.
+// Status sekali pakai disimpan oleh pemanggil berdasarkan jti
+func (s *JWTService) SignToken(tokenType string, userID uuid.UUID, expire time.Duration) (string, string, error) {
+	now := time.Now()
+	jti := uuid.NewString()
+	token, err := s.Keys.Sign(&model.UserClaimToken{
+		ID:   userID,
+		Type: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.AppName,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+			ID:        jti,
+		},
 	})
+
+	return token, jti, err
+}
+
+// ParseToken memverifikasi signature dan masa berlaku token yang dibuat dengan SignToken
+func (s *JWTService) ParseToken(signedToken string, tokenType string) (*model.UserClaimToken, error) {
+	token, err := jwt.ParseWithClaims(signedToken, new(model.UserClaimToken), s.Keys.Keyfunc, jwt.WithValidMethods(s.Keys.Methods()), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*model.UserClaimToken)
+	if !ok || !token.Valid || claims.Type != tokenType {
+		return nil, fmt.Errorf("Invalid %s token", tokenType)
+	}
+
+	return claims, nil
+}
+
+func (s *JWTService) ParseAccessToken(ctx context.Context, accessToken string) (*model.UserClaimToken, error) {
+	token, err := jwt.ParseWithClaims(accessToken, new(model.UserClaimToken), s.Keys.Keyfunc, jwt.WithValidMethods(s.Keys.Methods()))
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +245,10 @@ func (s *JWTService) ParseAccessToken(ctx context.Context, accessToken string) (
 		return nil, errors.New("Invalid access token")
 	}
 
+	if err = s.checkValidAfter(ctx, claims); err != nil {
+		return nil, err
+	}
+
 	key := fmt.Sprintf("access_token:%s", claims.RegisteredClaims.ID)
 	result, err := s.Redis.Exists(ctx, key).Result()
 	if err != nil {
@@ -88,14 +259,41 @@ func (s *JWTService) ParseAccessToken(ctx context.Context, accessToken string) (
 		return nil, errors.New("Invalid access token, not found in redis")
 	}
 
+	if claims.SessionID != "" {
+		if err = touchSession.Run(ctx, s.Redis, []string{sessionKey(claims.SessionID)}, time.Now().Unix()).Err(); err != nil {
+			return nil, err
+		}
+	}
+
 	return claims, nil
 }
 
-func (s *JWTService) ParseRefreshToken(ctx context.Context, refreshToken string) (*model.UserClaimToken, error) {
-	token, err := jwt.ParseWithClaims(refreshToken, new(model.UserClaimToken), func(token *jwt.Token) (interface{}, error) {
-		return []byte(s.SecreteKey), nil
-	})
+// ParseClientToken memverifikasi access token OAuth client yang dibuat dengan CreateClientToken
+func (s *JWTService) ParseClientToken(ctx context.Context, accessToken string) (*model.UserClaimToken, error) {
+	token, err := jwt.ParseWithClaims(accessToken, new(model.UserClaimToken), s.Keys.Keyfunc, jwt.WithValidMethods(s.Keys.Methods()), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
 
+	claims, ok := token.Claims.(*model.UserClaimToken)
+	if !ok || !token.Valid || claims.Type != "client" || claims.ClientID == "" {
+		return nil, errors.New("Invalid client token")
+	}
+
+	result, err := s.Redis.Exists(ctx, fmt.Sprintf("access_token:%s", claims.RegisteredClaims.ID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if result == 0 {
+		return nil, errors.New("Invalid client token, not found in redis")
+	}
+
+	return claims, nil
+}
+
+func (s *JWTService) ParseRefreshToken(ctx context.Context, refreshToken string) (*model.UserClaimToken, error) {
+	token, err := jwt.ParseWithClaims(refreshToken, new(model.UserClaimToken), s.Keys.Keyfunc, jwt.WithValidMethods(s.Keys.Methods()))
 	if err != nil {
 		return nil, err
 	}
@@ -105,36 +303,237 @@ func (s *JWTService) ParseRefreshToken(ctx context.Context, refreshToken string)
 		return nil, errors.New("Invalid refresh token")
 	}
 
-	key := fmt.Sprintf("refresh_token:%s", claims.RegisteredClaims.ID)
-	result, err := s.Redis.Exists(ctx, key).Result()
+	if err = s.checkValidAfter(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	// jti lama ditandai sudah di rotasi sampai refresh token tersebut kadaluarsa, supaya pemakaian ulang bisa dideteksi
+	ttl := int64(s.RefreshExpireDuration)
+	if claims.ExpiresAt != nil {
+		ttl = int64(time.Until(claims.ExpiresAt.Time).Seconds()) + 1
+	}
+	keys := []string{
+		fmt.Sprintf("refresh_token:%s", claims.RegisteredClaims.ID),
+		fmt.Sprintf("access_token:%s", claims.RegisteredClaims.ID),
+		fmt.Sprintf("rotated_refresh_token:%s", claims.RegisteredClaims.ID),
+	}
+	result, err := consumeRefreshToken.Run(ctx, s.Redis, keys, claims.SessionID, ttl).Int()
 	if err != nil {
 		return nil, err
 	}
 
-	if result == 0 {
+	switch result {
+	case 1:
+		return claims, nil
+	case 2:
+		// refresh token yang sudah di rotasi dipakai lagi, kemungkinan token dicuri: seluruh family (session) di revoke
+		if err = s.RevokeSession(ctx, claims.ID, claims.SessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return claims, err
+		}
+		return claims, ErrRefreshTokenReused
+	default:
 		return nil, errors.New("Invalid refresh token, not found in redis")
 	}
+}
+
+func (s *JWTService) RevokeToken(ctx context.Context, claims *model.UserClaimToken) error {
+	keys := []string{
+		fmt.Sprintf("access_token:%s", claims.RegisteredClaims.ID),
+		fmt.Sprintf("refresh_token:%s", claims.RegisteredClaims.ID),
+	}
+	if err := s.Redis.Del(ctx, keys...).Err(); err != nil {
+		return err
+	}
+
+	if claims.SessionID == "" {
+		return nil
+	}
+
+	err := s.RevokeSession(ctx, claims.ID, claims.SessionID)
+	if err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return err
+	}
+
+	return nil
+}
 
-	key = fmt.Sprintf("access_token:%s", claims.RegisteredClaims.ID)
-	if err = s.Redis.Del(ctx, key).Err(); err != nil {
+// ListSessions mengembalikan semua session aktif milik user, diurutkan dari yang terakhir dipakai
+func (s *JWTService) ListSessions(ctx context.Context, userID uuid.UUID) ([]model.SessionResponse, error) {
+	sessionIDs, err := s.Redis.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
 		return nil, err
 	}
 
-	key = fmt.Sprintf("refresh_token:%s", claims.RegisteredClaims.ID)
-	if err = s.Redis.Del(ctx, key).Err(); err != nil {
+	pipe := s.Redis.Pipeline()
+	commands := make([]*redis.MapStringStringCmd, len(sessionIDs))
+	for i, sessionID := range sessionIDs {
+		commands[i] = pipe.HGetAll(ctx, sessionKey(sessionID))
+	}
+	if _, err = pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
 		return nil, err
 	}
 
-	return claims, nil
+	sessions := make([]model.SessionResponse, 0, len(sessionIDs))
+	var expired []any
+	for i, sessionID := range sessionIDs {
+		values := commands[i].Val()
+		if len(values) == 0 {
+			expired = append(expired, sessionID)
+			continue
+		}
+
+		sessions = append(sessions, model.SessionResponse{
+			ID:         sessionID,
+			Device:     values["device"],
+			IPAddress:  values["ip_address"],
+			UserAgent:  values["user_agent"],
+			CreatedAt:  parseUnix(values["created_at"]),
+			LastUsedAt: parseUnix(values["last_used_at"]),
+		})
+	}
+
+	if len(expired) > 0 {
+		if err = s.Redis.SRem(ctx, userSessionsKey(userID), expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+
+	return sessions, nil
 }
 
-func (s *JWTService) RevokeToken(ctx context.Context, claims *model.UserClaimToken) error {
-	keys := s.Redis.Keys(ctx, fmt.Sprintf("*_token:%s", claims.RegisteredClaims.ID)).Val()
-	if len(keys) > 0 {
-		if err := s.Redis.Del(ctx, keys...).Err(); err != nil {
-			return err
+// RevokeSession menghapus session beserta access dan refresh token yang sedang aktif di session tersebut
+func (s *JWTService) RevokeSession(ctx context.Context, userID uuid.UUID, sessionID string) error {
+	isMember, err := s.Redis.SIsMember(ctx, userSessionsKey(userID), sessionID).Result()
+	if err != nil {
+		return err
+	}
+
+	if !isMember {
+		return ErrSessionNotFound
+	}
+
+	jti, err := s.Redis.HGet(ctx, sessionKey(sessionID), "jti").Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	pipe := s.Redis.TxPipeline()
+	if jti != "" {
+		pipe.Del(ctx, fmt.Sprintf("access_token:%s", jti), fmt.Sprintf("refresh_token:%s", jti))
+	}
+	pipe.Del(ctx, sessionKey(sessionID))
+	pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+	_, err = pipe.Exec(ctx)
+
+	return err
+}
+
+// RevokeOtherSessions menghapus semua session milik user kecuali session keepSessionID, mengembalikan jumlah session yang dihapus
+func (s *JWTService) RevokeOtherSessions(ctx context.Context, userID uuid.UUID, keepSessionID string) (int, error) {
+	sessionIDs, err := s.Redis.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, sessionID := range sessionIDs {
+		if sessionID == keepSessionID {
+			continue
+		}
+
+		err = s.RevokeSession(ctx, userID, sessionID)
+		if err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return revoked, err
 		}
+		revoked++
+	}
+
+	return revoked, nil
+}
+
+// InvalidateTokens membuat semua token user yang diterbitkan sebelum saat ini tidak berlaku (dicek di ParseAccessToken dan
+// ParseRefreshToken) dan menghapus semua session. Jika keepSessionID diisi, token di session tersebut tetap berlaku.
+// Waktu disimpan dalam milidetik supaya token yang diterbitkan di detik yang sama sebelum invalidasi ikut ditolak
+func (s *JWTService) InvalidateTokens(ctx context.Context, userID uuid.UUID, keepSessionID string) error {
+	key := tokensValidAfterKey(userID)
+	pipe := s.Redis.TxPipeline()
+	pipe.HSet(ctx, key, map[string]any{"valid_after_ms": time.Now().UnixMilli(), "keep_session": keepSessionID})
+	pipe.HDel(ctx, key, "valid_after")
+	pipe.Expire(ctx, key, time.Duration(s.RefreshExpireDuration)*time.Second)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	_, err := s.RevokeOtherSessions(ctx, userID, keepSessionID)
+	return err
+}
+
+// checkValidAfter menolak token yang diterbitkan pada atau sebelum InvalidateTokens terakhir, kecuali token di session yang
+// dipertahankan. Token lama tanpa iat_ms dibandingkan dengan iat (dibulatkan ke bawah), sehingga token di detik yang
+// sama ikut ditolak
+func (s *JWTService) checkValidAfter(ctx context.Context, claims *model.UserClaimToken) error {
+	values, err := s.Redis.HMGet(ctx, tokensValidAfterKey(claims.ID), "valid_after_ms", "valid_after", "keep_session").Result()
+	if err != nil {
+		return err
+	}
+
+	// valid_after dalam detik ditulis sebelum valid_after_ms ada, dibaca sampai key-nya kadaluarsa
+	var validAfter time.Time
+	if value, _ := values[0].(string); value != "" {
+		validAfter = parseUnixMilli(value)
+	} else if value, _ := values[1].(string); value != "" {
+		validAfter = parseUnix(value)
+	}
+	if validAfter.IsZero() || claims.IssuedAt == nil {
+		return nil
+	}
+
+	keepSession, _ := values[2].(string)
+	if keepSession != "" && keepSession == claims.SessionID {
+		return nil
+	}
+
+	issuedAt := claims.IssuedAt.Time
+	if claims.IssuedAtMs > 0 {
+		issuedAt = time.UnixMilli(claims.IssuedAtMs)
+	}
+	if !issuedAt.After(validAfter) {
+		return ErrTokenInvalidated
 	}
 
 	return nil
 }
+
+func tokensValidAfterKey(userID uuid.UUID) string {
+	return fmt.Sprintf("tokens_valid_after:%s", userID)
+}
+
+func sessionKey(sessionID string) string {
+	return fmt.Sprintf("session:%s", sessionID)
+}
+
+func userSessionsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user_sessions:%s", userID)
+}
+
+func parseUnixMilli(value string) time.Time {
+	unixMilli, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(unixMilli)
+}
+
+func parseUnix(value string) time.Time {
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.Unix(unix, 0)
+}
@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeyConfig adalah konfigurasi satu signing key di config.json (jwt.keys)
+type KeyConfig struct {
+	ID             string `mapstructure:"id"`
+	Algorithm      string `mapstructure:"algorithm"`        // HS256, RS256, ES256 atau EdDSA
+	Secret         string `mapstructure:"secret"`           // khusus HS256
+	PrivateKey     string `mapstructure:"private_key"`      // PEM inline
+	PrivateKeyFile string `mapstructure:"private_key_file"` // path file PEM
+	PublicKey      string `mapstructure:"public_key"`       // PEM inline, untuk key lama yang hanya dipakai verifikasi
+	PublicKeyFile  string `mapstructure:"public_key_file"`
+	RetireAt       string `mapstructure:"retire_at"` // RFC3339, setelah waktu ini token dengan kid ini ditolak
+}
+
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	Method     jwt.SigningMethod
+	Secret     []byte
+	PrivateKey crypto.Signer
+	PublicKey  crypto.PublicKey
+	RetireAt   *time.Time
+}
+
+// KeySet berisi key aktif untuk menandatangani token dan semua key yang masih diterima saat verifikasi
+type KeySet struct {
+	Active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// NewKeySet membangun key set dari konfigurasi, jika configs kosong maka dipakai HS256 dengan secret (perilaku lama tanpa kid).
+// Jika configs di set, token lama tanpa kid hanya diterima jika secretRetireAt (RFC3339) di set dan belum lewat
+func NewKeySet(configs []KeyConfig, activeKeyID string, secret string, secretRetireAt string) (*KeySet, error) {
+	keySet := &KeySet{keys: make(map[string]*SigningKey)}
+
+	if len(configs) == 0 {
+		if secret == "" {
+			return nil, errors.New("jwt secret_key or jwt keys must be configured")
+		}
+
+		keySet.Active = &SigningKey{Algorithm: jwt.SigningMethodHS256.Alg(), Method: jwt.SigningMethodHS256, Secret: []byte(secret)}
+		keySet.keys[""] = keySet.Active
+		return keySet, nil
+	}
+
+	for _, config := range configs {
+		key, err := parseSigningKey(config)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", config.ID, err)
+		}
+
+		if _, exists := keySet.keys[key.ID]; exists {
+			return nil, fmt.Errorf("jwt key %q: duplicate key id", key.ID)
+		}
+		keySet.keys[key.ID] = key
+	}
+
+	active, ok := keySet.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("jwt active key %q is not configured", activeKeyID)
+	}
+
+	if active.Secret == nil && active.PrivateKey == nil {
+		return nil, fmt.Errorf("jwt active key %q has no private key", activeKeyID)
+	}
+	keySet.Active = active
+
+	// token lama tanpa kid hanya diterima jika diminta secara eksplisit, dan seperti key lain berhenti diterima setelah retire
+	if secret != "" && secretRetireAt != "" {
+		retireAt, err := time.Parse(time.RFC3339, secretRetireAt)
+		if err != nil {
+			return nil, fmt.Errorf("jwt secret_key_retire_at: %w", err)
+		}
+		keySet.keys[""] = &SigningKey{Algorithm: jwt.SigningMethodHS256.Alg(), Method: jwt.SigningMethodHS256, Secret: []byte(secret), RetireAt: &retireAt}
+	}
+
+	return keySet, nil
+}
+
+// Sign menandatangani claims dengan key aktif dan menambahkan header kid
+func (k *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.Active.Method, claims)
+	if k.Active.ID != "" {
+		token.Header["kid"] = k.Active.ID
+	}
+
+	if k.Active.Secret != nil {
+		return token.SignedString(k.Active.Secret)
+	}
+
+	return token.SignedString(k.Active.PrivateKey)
+}
+
+// Keyfunc memilih key verifikasi berdasarkan header kid dan menolak algoritma yang tidak sesuai dengan key tersebut
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s for key %q", token.Method.Alg(), kid)
+	}
+
+	if key.RetireAt != nil && time.Now().After(*key.RetireAt) {
+		return nil, fmt.Errorf("signing key %q has been retired", kid)
+	}
+
+	if key.Secret != nil {
+		return key.Secret, nil
+	}
+
+	return key.PublicKey, nil
+}
+
+// Methods mengembalikan daftar algoritma yang diterima saat parsing token
+func (k *KeySet) Methods() []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, key := range k.keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			methods = append(methods, key.Algorithm)
+		}
+	}
+
+	return methods
+}
+
+func parseSigningKey(config KeyConfig) (*SigningKey, error) {
+	if config.ID == "" {
+		return nil, errors.New("id is required")
+	}
+
+	method := jwt.GetSigningMethod(config.Algorithm)
+	key := &SigningKey{ID: config.ID, Algorithm: config.Algorithm, Method: method}
+
+	if config.RetireAt != "" {
+		retireAt, err := time.Parse(time.RFC3339, config.RetireAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retire_at: %w", err)
+		}
+		key.RetireAt = &retireAt
+	}
+
+	switch config.Algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if config.Secret == "" {
+			return nil, errors.New("secret is required for HS256")
+		}
+		key.Secret = []byte(config.Secret)
+		return key, nil
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg(), jwt.SigningMethodEdDSA.Alg():
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", config.Algorithm)
+	}
+
+	privatePEM, err := readPEM(config.PrivateKey, config.PrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if privatePEM != nil {
+		key.PrivateKey, err = parsePrivateKey(privatePEM)
+		if err != nil {
+			return nil, err
+		}
+		key.PublicKey = key.PrivateKey.Public()
+	} else {
+		publicPEM, err := readPEM(config.PublicKey, config.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if publicPEM == nil {
+			return nil, errors.New("private_key or public_key is required")
+		}
+
+		key.PublicKey, err = x509.ParsePKIXPublicKey(publicPEM.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+	}
+
+	if err = checkKeyType(config.Algorithm, key.PublicKey); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func readPEM(inline, file string) (*pem.Block, error) {
+	data := []byte(inline)
+	if inline == "" && file != "" {
+		var err error
+		data, err = os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	return block, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("private key cannot be used for signing")
+	}
+
+	return signer, nil
+}
+
+func checkKeyType(algorithm string, publicKey crypto.PublicKey) error {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		if algorithm == jwt.SigningMethodRS256.Alg() {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		if algorithm == jwt.SigningMethodES256.Alg() && key.Curve == elliptic.P256() {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if algorithm == jwt.SigningMethodEdDSA.Alg() {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("key type %T does not match algorithm %s", publicKey, algorithm)
+}
@@ -0,0 +1,78 @@
+package clientip
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Resolver menentukan IP asli client dari header X-Forwarded-For. Header hanya dipercaya jika request datang dari
+// trusted proxy, dan dibaca dari kanan ke kiri supaya IP palsu yang ditambahkan client di awal header diabaikan
+type Resolver struct {
+	trusted []netip.Prefix
+}
+
+// New membuat resolver dari daftar IP atau CIDR trusted proxy, daftar kosong berarti header tidak pernah dipercaya
+func New(trustedProxies []string) (*Resolver, error) {
+	resolver := &Resolver{}
+	for _, proxy := range trustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			resolver.trusted = append(resolver.trusted, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		resolver.trusted = append(resolver.trusted, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return resolver, nil
+}
+
+// Resolve mengembalikan IP client berdasarkan IP koneksi (remoteIP) dan isi header X-Forwarded-For
+func (r *Resolver) Resolve(remoteIP string, forwardedFor string) string {
+	remote, err := netip.ParseAddr(remoteIP)
+	if err != nil || !r.isTrusted(remote) || forwardedFor == "" {
+		return remoteIP
+	}
+
+	// setiap proxy menambahkan IP yang terhubung kepadanya di akhir header, IP pertama dari kanan yang bukan trusted proxy adalah client
+	hops := strings.Split(forwardedFor, ",")
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+
+		client = hop.Unmap()
+		if !r.isTrusted(client) {
+			break
+		}
+	}
+
+	return client.String()
+}
+
+func (r *Resolver) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -132,6 +132,34 @@ Jika Anda yang melakukan permintaan ini, silakan klik tombol di bawah untuk memb
 		Build()
 }
 
+// EmailChangeConfirmationEmailTemplate membuat template konfirmasi perubahan email yang dikirim ke alamat email baru
+func EmailChangeConfirmationEmailTemplate(name, newEmail, confirmURL string) EmailTemplateData {
+	return NewEmailTemplate().
+		SetName(name).
+		SetSubject("Konfirmasi Perubahan Email - Nyinauni Golang").
+		SetMessage(fmt.Sprintf(`Kami menerima permintaan untuk mengganti email akun Anda menjadi %s.
+
+Silakan klik tombol di bawah untuk mengkonfirmasi bahwa alamat email ini milik Anda. Email akun baru akan diganti setelah konfirmasi. Link ini akan kadaluarsa dalam 24 jam.`, newEmail)).
+		AddButton("Konfirmasi Email", confirmURL).
+		AddHighlight("Link konfirmasi berlaku selama 24 jam!").
+		AddNote("Jika Anda tidak meminta perubahan email, abaikan email ini.").
+		Build()
+}
+
+// EmailChangeRequestedEmailTemplate membuat template pemberitahuan perubahan email yang dikirim ke alamat email lama
+func EmailChangeRequestedEmailTemplate(name, newEmail, cancelURL string) EmailTemplateData {
+	return NewEmailTemplate().
+		SetName(name).
+		SetSubject("Permintaan Perubahan Email - Nyinauni Golang").
+		SetMessage(fmt.Sprintf(`Kami menerima permintaan untuk mengganti email akun Anda menjadi %s.
+
+Email akun akan diganti setelah pemilik alamat email baru melakukan konfirmasi.`, newEmail)).
+		AddButton("Batalkan Perubahan", cancelURL).
+		AddInfoBox("Bukan Anda?", "Batalkan perubahan email dengan tombol di atas, lalu segera ganti password akun Anda.").
+		AddNote("Jika Anda yang meminta perubahan ini, abaikan email ini.").
+		Build()
+}
+
 // WelcomeEmailTemplate membuat template email selamat datang
 func WelcomeEmailTemplate(name string) EmailTemplateData {
 	return NewEmailTemplate().
@@ -197,6 +225,102 @@ Jika ini bukan Anda, segera hubungi tim support kami untuk mengamankan akun Anda
 		Build()
 }
 
+// AccountLockedEmailTemplate membuat template notifikasi akun terkunci sementara karena terlalu banyak percobaan login
+func AccountLockedEmailTemplate(name, lockedUntil string) EmailTemplateData {
+	return NewEmailTemplate().
+		SetName(name).
+		SetSubject("Akun Anda Dikunci Sementara - Nyinauni Golang").
+		SetMessage(fmt.Sprintf(`Kami mendeteksi terlalu banyak percobaan login yang gagal ke akun Anda.
+
+Untuk melindungi akun Anda, login dikunci sementara sampai %s. Setelah itu Anda dapat mencoba login kembali.`, lockedUntil)).
+		AddHighlight("Jika bukan Anda, segera ubah password Anda!").
+		AddInfoBox("Tips Keamanan", "Gunakan password yang kuat dan unik, serta aktifkan 2-Factor Authentication (2FA) untuk perlindungan tambahan.").
+		AddNote("Jika Anda membutuhkan akses segera, hubungi administrator untuk membuka kunci akun Anda.").
+		Build()
+}
+
+// AccountSuspendedEmailTemplate membuat template notifikasi akun di suspend oleh administrator
+func AccountSuspendedEmailTemplate(name, reason, suspendedUntil string) EmailTemplateData {
+	return NewEmailTemplate().
+		SetName(name).
+		SetSubject("Akun Anda Ditangguhkan - Nyinauni Golang").
+		SetMessage(fmt.Sprintf(`Akun Anda telah ditangguhkan oleh administrator sampai %s.
+
+Selama ditangguhkan, Anda tidak dapat login dan semua session yang aktif telah diakhiri.`, suspendedUntil)).
+		AddInfoBox("Alasan", reason).
+		AddNote("Jika menurut Anda ini adalah kesalahan, hubungi tim support kami.").
+		Build()
+}
+
+// AccountReinstatedEmailTemplate membuat template notifikasi akun diaktifkan kembali setelah di suspend
+func AccountReinstatedEmailTemplate(name string) EmailTemplateData {
+	return NewEmailTemplate().
+		SetName(name).
+		SetSubject("Akun Anda Telah Diaktifkan Kembali - Nyinauni Golang").
+		SetMessage(`Penangguhan akun Anda telah dicabut oleh administrator.
+
+Anda sudah dapat login kembali seperti biasa.`).
+		AddInfoBox("Tips Keamanan", "Gunakan password yang kuat dan unik, serta aktifkan 2-Factor Authentication (2FA) untuk perlindungan tambahan.").
+		Build()
+}
+
+// MagicLinkEmailTemplate membuat template email login tanpa password
+func MagicLinkEmailTemplate(name, loginURL, expireIn string) EmailTemplateData {
+	return NewEmailTemplate().
+		SetName(name).
+		SetSubject("Link Login Anda - Nyinauni Golang").
+		SetMessage(fmt.Sprintf(`Kami menerima permintaan login ke akun Anda tanpa password.
+
+Klik tombol di bawah untuk masuk. Link ini hanya bisa dipakai satu kali dan akan kadaluarsa dalam %s.`, expireIn)).
+		AddButton("Login Sekarang", loginURL).
+		AddHighlight(fmt.Sprintf("Link login berlaku %s!", expireIn)).
+		AddNote("Jika Anda tidak meminta link login, abaikan email ini. Jangan teruskan email ini kepada siapa pun.").
+		Build()
+}
+
+// InvitationEmailTemplate membuat template undangan untuk user yang dibuat oleh administrator
+func InvitationEmailTemplate(name, inviterName, acceptURL, expireIn string) EmailTemplateData {
+	return NewEmailTemplate().
+		SetName(name).
+		SetSubject("Undangan Bergabung - Nyinauni Golang").
+		SetMessage(fmt.Sprintf(`%s mengundang Anda untuk bergabung di Nyinauni Golang.
+
+Klik tombol di bawah untuk membuat password dan mengaktifkan akun Anda. Link ini hanya bisa dipakai satu kali dan akan kadaluarsa dalam %s.`, inviterName, expireIn)).
+		AddButton("Terima Undangan", acceptURL).
+		AddHighlight(fmt.Sprintf("Undangan berlaku %s!", expireIn)).
+		AddNote("Jika Anda tidak mengenal pengirim undangan ini, abaikan email ini. Akun tidak akan aktif sebelum undangan diterima.").
+		Build()
+}
+
+// RegistrationApprovedEmailTemplate membuat template pemberitahuan pendaftaran yang disetujui administrator
+func RegistrationApprovedEmailTemplate(name string) EmailTemplateData {
+	return NewEmailTemplate().
+		SetName(name).
+		SetSubject("Pendaftaran Disetujui - Nyinauni Golang").
+		SetMessage(`Kabar baik! Pendaftaran akun Anda telah disetujui oleh administrator.
+
+Anda sudah dapat login menggunakan email dan password yang Anda daftarkan.`).
+		AddInfoBox("Tips Keamanan", "Gunakan password yang kuat dan unik, serta aktifkan 2-Factor Authentication (2FA) untuk perlindungan tambahan.").
+		Build()
+}
+
+// RegistrationRejectedEmailTemplate membuat template pemberitahuan pendaftaran yang ditolak administrator
+func RegistrationRejectedEmailTemplate(name, reason string) EmailTemplateData {
+	builder := NewEmailTemplate().
+		SetName(name).
+		SetSubject("Pendaftaran Ditolak - Nyinauni Golang").
+		SetMessage(`Mohon maaf, pendaftaran akun Anda tidak disetujui oleh administrator.
+
+Data pendaftaran Anda telah dihapus dari sistem kami.`)
+	if reason != "" {
+		builder.AddInfoBox("Alasan", reason)
+	}
+
+	return builder.
+		AddNote("Jika Anda merasa ini adalah kesalahan, silakan hubungi administrator.").
+		Build()
+}
+
 // AccountDeletedEmailTemplate membuat template konfirmasi penghapusan akun
 func AccountDeletedEmailTemplate(name string) EmailTemplateData {
 	return NewEmailTemplate().
@@ -209,6 +333,19 @@ Semua data personal Anda telah dihapus secara permanen. Kami sedih melihat Anda
 		Build()
 }
 
+// AccountDeletionScheduledEmailTemplate membuat template pemberitahuan penghapusan akun yang dijadwalkan
+func AccountDeletionScheduledEmailTemplate(name, deleteAt string) EmailTemplateData {
+	return NewEmailTemplate().
+		SetName(name).
+		SetSubject("Penghapusan Akun Dijadwalkan - Nyinauni Golang").
+		SetMessage(fmt.Sprintf(`Kami telah menerima permintaan untuk menghapus akun Anda.
+
+Akun Anda beserta semua data personal akan dihapus secara permanen pada %s. Semua session yang aktif telah diakhiri.`, deleteAt)).
+		AddInfoBox("Berubah Pikiran?", "Login kembali ke akun Anda sebelum waktu tersebut untuk membatalkan penghapusan akun.").
+		AddNote("Jika Anda tidak meminta penghapusan akun, segera login dan ganti password Anda.").
+		Build()
+}
+
 // PaymentSuccessEmailTemplate membuat template konfirmasi pembayaran
 func PaymentSuccessEmailTemplate(name, orderID, amount, item string) EmailTemplateData {
 	message := fmt.Sprintf(`Pembayaran Anda telah berhasil diproses! 🎉
@@ -261,3 +398,69 @@ func QuickPasswordChangedEmail(service *EmailService, to, name, changeTime strin
 	data := PasswordChangedEmailTemplate(name, changeTime)
 	return service.SendTemplateEmail([]string{to}, data.Subject, data)
 }
+
+// QuickSendMagicLink shortcut untuk mengirim email link login
+func QuickSendMagicLink(service *EmailService, to, name, loginURL, expireIn string) error {
+	data := MagicLinkEmailTemplate(name, loginURL, expireIn)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendAccountLocked shortcut untuk mengirim notifikasi akun terkunci
+func QuickSendAccountLocked(service *EmailService, to, name, lockedUntil string) error {
+	data := AccountLockedEmailTemplate(name, lockedUntil)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendAccountSuspended shortcut untuk mengirim notifikasi akun di suspend
+func QuickSendAccountSuspended(service *EmailService, to, name, reason, suspendedUntil string) error {
+	data := AccountSuspendedEmailTemplate(name, reason, suspendedUntil)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendAccountReinstated shortcut untuk mengirim notifikasi akun diaktifkan kembali
+func QuickSendAccountReinstated(service *EmailService, to, name string) error {
+	data := AccountReinstatedEmailTemplate(name)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendAccountDeletionScheduled shortcut untuk mengirim pemberitahuan penghapusan akun yang dijadwalkan
+func QuickSendAccountDeletionScheduled(service *EmailService, to, name, deleteAt string) error {
+	data := AccountDeletionScheduledEmailTemplate(name, deleteAt)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendAccountDeleted shortcut untuk mengirim konfirmasi penghapusan akun
+func QuickSendAccountDeleted(service *EmailService, to, name string) error {
+	data := AccountDeletedEmailTemplate(name)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendEmailChangeConfirmation shortcut untuk mengirim link konfirmasi perubahan email ke alamat email baru
+func QuickSendEmailChangeConfirmation(service *EmailService, to, name, newEmail, confirmURL string) error {
+	data := EmailChangeConfirmationEmailTemplate(name, newEmail, confirmURL)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendEmailChangeRequested shortcut untuk mengirim pemberitahuan perubahan email ke alamat email lama
+func QuickSendEmailChangeRequested(service *EmailService, to, name, newEmail, cancelURL string) error {
+	data := EmailChangeRequestedEmailTemplate(name, newEmail, cancelURL)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendInvitation shortcut untuk mengirim email undangan
+func QuickSendInvitation(service *EmailService, to, name, inviterName, acceptURL, expireIn string) error {
+	data := InvitationEmailTemplate(name, inviterName, acceptURL, expireIn)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendRegistrationApproved shortcut untuk mengirim pemberitahuan pendaftaran disetujui
+func QuickSendRegistrationApproved(service *EmailService, to, name string) error {
+	data := RegistrationApprovedEmailTemplate(name)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
+
+// QuickSendRegistrationRejected shortcut untuk mengirim pemberitahuan pendaftaran ditolak
+func QuickSendRegistrationRejected(service *EmailService, to, name, reason string) error {
+	data := RegistrationRejectedEmailTemplate(name, reason)
+	return service.SendTemplateEmail([]string{to}, data.Subject, data)
+}
@@ -0,0 +1,80 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// prefix menandai nilai yang sudah dienkripsi, sehingga data lama yang masih plaintext bisa dibedakan dan dimigrasi
+const prefix = "enc:v1:"
+
+var (
+	ErrInvalidKey        = errors.New("encryption key must be 32 bytes encoded in base64")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// Cipher mengenkripsi data rahasia yang harus bisa dibaca kembali (mis. secret TOTP) dengan AES-256-GCM
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher membuat Cipher dari key 32 byte dalam base64, prefix "base64:" boleh disertakan
+func NewCipher(key string) (*Cipher, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(key, "base64:"))
+	if err != nil || len(raw) != 32 {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt mengenkripsi plaintext, additionalData (mis. id pemilik data) ikut diautentikasi sehingga ciphertext tidak
+// bisa dipindahkan ke baris lain
+func (c *Cipher) Encrypt(plaintext string, additionalData string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), []byte(additionalData))
+	return prefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt membuka nilai hasil Encrypt dengan additionalData yang sama
+func (c *Cipher) Decrypt(value string, additionalData string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", ErrInvalidCiphertext
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, prefix))
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(additionalData))
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(plaintext), nil
+}
+
+// IsEncrypted mengembalikan true jika value adalah hasil Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
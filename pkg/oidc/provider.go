@@ -0,0 +1,397 @@
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ProviderConfig adalah konfigurasi satu provider di config.json (oidc.providers.<nama>). Jika issuer di set, endpoint
+// diambil dari discovery document, endpoint yang di set manual (misalnya untuk GitHub yang bukan OIDC) akan dipakai apa adanya
+type ProviderConfig struct {
+	Issuer                string   `mapstructure:"issuer"`
+	ClientID              string   `mapstructure:"client_id"`
+	ClientSecret          string   `mapstructure:"client_secret"`
+	RedirectURL           string   `mapstructure:"redirect_url"`
+	Scopes                []string `mapstructure:"scopes"`
+	AuthorizationEndpoint string   `mapstructure:"authorization_endpoint"`
+	TokenEndpoint         string   `mapstructure:"token_endpoint"`
+	UserInfoEndpoint      string   `mapstructure:"userinfo_endpoint"`
+	JWKSURI               string   `mapstructure:"jwks_uri"`
+	SubjectClaim          string   `mapstructure:"subject_claim"`
+	EmailClaim            string   `mapstructure:"email_claim"`
+	NameClaim             string   `mapstructure:"name_claim"`
+	TrustEmail            bool     `mapstructure:"trust_email"` // anggap email terverifikasi walaupun provider tidak mengirim email_verified
+}
+
+// UserInfo adalah identitas user dari provider setelah code berhasil ditukar
+type UserInfo struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+type Provider struct {
+	Name       string
+	Config     ProviderConfig
+	HTTPClient *http.Client
+
+	mu          sync.Mutex
+	discovered  bool
+	keys        map[string]any
+	keysFetched time.Time
+}
+
+func NewProvider(name string, config ProviderConfig) *Provider {
+	if config.SubjectClaim == "" {
+		config.SubjectClaim = "sub"
+	}
+	if config.EmailClaim == "" {
+		config.EmailClaim = "email"
+	}
+	if config.NameClaim == "" {
+		config.NameClaim = "name"
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{Name: name, Config: config, HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// NewProviders membuat semua provider dari konfigurasi oidc.providers
+func NewProviders(configs map[string]ProviderConfig) map[string]*Provider {
+	providers := make(map[string]*Provider, len(configs))
+	for name, config := range configs {
+		providers[name] = NewProvider(name, config)
+	}
+
+	return providers
+}
+
+// AuthCodeURL membuat URL halaman login provider dengan state, nonce dan PKCE code challenge (S256)
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	if err := p.discover(ctx); err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.Config.ClientID)
+	query.Set("redirect_uri", p.Config.RedirectURL)
+	query.Set("scope", strings.Join(p.Config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(p.Config.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return p.Config.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange menukar authorization code menjadi identitas user. ID token diverifikasi (signature, issuer, audience,
+// expiry dan nonce), jika provider tidak mengembalikan ID token maka userinfo endpoint yang dipakai
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*UserInfo, error) {
+	if err := p.discover(ctx); err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.Config.RedirectURL)
+	form.Set("client_id", p.Config.ClientID)
+	form.Set("client_secret", p.Config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.Config.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	token := new(tokenResponse)
+	if err = p.doJSON(request, token); err != nil {
+		return nil, fmt.Errorf("token exchange failed: %w", err)
+	}
+
+	if token.Error != "" {
+		return nil, fmt.Errorf("token exchange failed: %s %s", token.Error, token.Description)
+	}
+
+	var claims map[string]any
+	if token.IDToken != "" {
+		claims, err = p.verifyIDToken(ctx, token.IDToken, nonce)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if p.Config.UserInfoEndpoint == "" || token.AccessToken == "" {
+			return nil, errors.New("provider returned neither id_token nor usable access_token")
+		}
+
+		claims, err = p.userInfo(ctx, token.AccessToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	info := &UserInfo{
+		Subject:       stringClaim(claims[p.Config.SubjectClaim]),
+		Email:         stringClaim(claims[p.Config.EmailClaim]),
+		EmailVerified: boolClaim(claims["email_verified"]),
+		Name:          stringClaim(claims[p.Config.NameClaim]),
+	}
+	if p.Config.TrustEmail && info.Email != "" {
+		info.EmailVerified = true
+	}
+
+	if info.Subject == "" {
+		return nil, fmt.Errorf("claim %q is missing", p.Config.SubjectClaim)
+	}
+
+	return info, nil
+}
+
+func (p *Provider) discover(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovered || p.Config.Issuer == "" {
+		return p.checkEndpoints()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.Config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return err
+	}
+
+	document := new(discoveryDocument)
+	if err = p.doJSON(request, document); err != nil {
+		return fmt.Errorf("discovery failed: %w", err)
+	}
+
+	if document.Issuer != p.Config.Issuer {
+		return fmt.Errorf("discovery issuer mismatch: expected %q got %q", p.Config.Issuer, document.Issuer)
+	}
+
+	p.Config.AuthorizationEndpoint = firstNonEmpty(p.Config.AuthorizationEndpoint, document.AuthorizationEndpoint)
+	p.Config.TokenEndpoint = firstNonEmpty(p.Config.TokenEndpoint, document.TokenEndpoint)
+	p.Config.UserInfoEndpoint = firstNonEmpty(p.Config.UserInfoEndpoint, document.UserInfoEndpoint)
+	p.Config.JWKSURI = firstNonEmpty(p.Config.JWKSURI, document.JWKSURI)
+	p.discovered = true
+
+	return p.checkEndpoints()
+}
+
+func (p *Provider) checkEndpoints() error {
+	if p.Config.AuthorizationEndpoint == "" || p.Config.TokenEndpoint == "" {
+		return fmt.Errorf("provider %q has no authorization or token endpoint", p.Name)
+	}
+
+	return nil
+}
+
+func (p *Provider) verifyIDToken(ctx context.Context, idToken, nonce string) (map[string]any, error) {
+	claims := jwt.MapClaims{}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "PS256", "EdDSA"}),
+		jwt.WithAudience(p.Config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	}
+	if p.Config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(p.Config.Issuer))
+	}
+
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, kid)
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if stringClaim(claims["nonce"]) != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+// publicKey mencari key berdasarkan kid, JWKS diambil ulang jika kid belum dikenal (maksimal sekali per menit)
+func (p *Provider) publicKey(ctx context.Context, kid string) (any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	if p.Config.JWKSURI == "" {
+		return nil, errors.New("provider has no jwks_uri")
+	}
+
+	if time.Since(p.keysFetched) < time.Minute {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Config.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	set := new(jsonWebKeySet)
+	if err = p.doJSON(request, set); err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	p.keys = set.publicKeys()
+	p.keysFetched = time.Now()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (p *Provider) lookupKey(kid string) (any, bool) {
+	if key, ok := p.keys[kid]; ok {
+		return key, true
+	}
+
+	// provider dengan satu key kadang tidak mengirim kid
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+func (p *Provider) userInfo(ctx context.Context, accessToken string) (map[string]any, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Config.UserInfoEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	request.Header.Set("Accept", "application/json")
+
+	claims := make(map[string]any)
+	if err = p.doJSON(request, &claims); err != nil {
+		return nil, fmt.Errorf("userinfo request failed: %w", err)
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) doJSON(request *http.Request, target any) error {
+	response, err := p.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	// token endpoint mengembalikan 400/401 dengan body error JSON, body tersebut tetap di decode
+	if response.StatusCode >= 300 && response.StatusCode != http.StatusBadRequest && response.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+// GenerateRandom membuat string acak url-safe untuk state, nonce dan PKCE code verifier
+func GenerateRandom() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// CodeChallenge menghitung PKCE code challenge metode S256 (RFC 7636)
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func stringClaim(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func boolClaim(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,387 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "test-client"
+	testClientSecret = "test-secret"
+	testRedirectURL  = "https://app.example.com/callback"
+	testKeyID        = "test-key"
+)
+
+// mockIssuer adalah OIDC issuer lokal yang melayani discovery, JWKS, authorization dan token endpoint. Claims ID token
+// bisa diubah per test lewat idToken untuk mensimulasikan token yang tidak valid
+type mockIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]authorization
+
+	// idToken mengubah claims dan kid sebelum ID token ditandatangani
+	idToken func(claims jwt.MapClaims, header map[string]any)
+}
+
+type authorization struct {
+	codeChallenge string
+	nonce         string
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+
+	issuer := &mockIssuer{t: t, key: key, codes: make(map[string]authorization)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("/jwks", issuer.jwks)
+	mux.HandleFunc("/authorize", issuer.authorize)
+	mux.HandleFunc("/token", issuer.token)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (m *mockIssuer) provider() *Provider {
+	provider := NewProvider("mock", ProviderConfig{
+		Issuer:       m.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+	provider.HTTPClient = m.server.Client()
+
+	return provider
+}
+
+func (m *mockIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 m.server.URL,
+		"authorization_endpoint": m.server.URL + "/authorize",
+		"token_endpoint":         m.server.URL + "/token",
+		"jwks_uri":               m.server.URL + "/jwks",
+	})
+}
+
+func (m *mockIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
+
+// authorize langsung menyetujui login dan redirect ke redirect_uri dengan code dan state seperti provider sungguhan
+func (m *mockIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != testClientID || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString(m.t)
+	m.mu.Lock()
+	m.codes[code] = authorization{codeChallenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	m.mu.Unlock()
+
+	callback := query.Get("redirect_uri") + "?" + url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, callback, http.StatusFound)
+}
+
+func (m *mockIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	if r.PostForm.Get("client_id") != testClientID || r.PostForm.Get("client_secret") != testClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// code hanya bisa dipakai sekali dan hanya dengan code verifier pasangan code challenge saat authorize
+	m.mu.Lock()
+	auth, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	if !ok || r.PostForm.Get("redirect_uri") != testRedirectURL || CodeChallenge(r.PostForm.Get("code_verifier")) != auth.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "invalid code or code verifier"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            m.server.URL,
+		"aud":            testClientID,
+		"sub":            "user-123",
+		"email":          "user@example.com",
+		"email_verified": true,
+		"name":           "Test User",
+		"nonce":          auth.nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+	header := map[string]any{"kid": testKeyID}
+	if m.idToken != nil {
+		m.idToken(claims, header)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	for name, value := range header {
+		token.Header[name] = value
+	}
+
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		m.t.Errorf("failed to sign id token: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"access_token": "access-token", "token_type": "Bearer", "id_token": idToken})
+}
+
+// login menjalankan authorization request ke mock issuer dan mengembalikan code dan state dari redirect callback
+func (m *mockIssuer) login(provider *Provider, state, nonce, codeVerifier string) (string, string) {
+	m.t.Helper()
+
+	authorizationURL, err := provider.AuthCodeURL(context.Background(), state, nonce, codeVerifier)
+	if err != nil {
+		m.t.Fatalf("AuthCodeURL returned error: %v", err)
+	}
+
+	client := m.server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	response, err := client.Get(authorizationURL)
+	if err != nil {
+		m.t.Fatalf("authorization request failed: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		m.t.Fatalf("authorization request returned status %d", response.StatusCode)
+	}
+
+	callback, err := url.Parse(response.Header.Get("Location"))
+	if err != nil {
+		m.t.Fatalf("invalid callback url: %v", err)
+	}
+
+	return callback.Query().Get("code"), callback.Query().Get("state")
+}
+
+func TestExchange(t *testing.T) {
+	issuer := newMockIssuer(t)
+	provider := issuer.provider()
+
+	state, nonce, codeVerifier := randomString(t), randomString(t), randomString(t)
+	code, callbackState := issuer.login(provider, state, nonce, codeVerifier)
+	if callbackState != state {
+		t.Fatalf("callback state = %q, want %q", callbackState, state)
+	}
+
+	info, err := provider.Exchange(context.Background(), code, codeVerifier, nonce)
+	if err != nil {
+		t.Fatalf("Exchange returned error: %v", err)
+	}
+
+	want := UserInfo{Subject: "user-123", Email: "user@example.com", EmailVerified: true, Name: "Test User"}
+	if *info != want {
+		t.Errorf("Exchange = %+v, want %+v", *info, want)
+	}
+
+	// code sekali pakai
+	if _, err = provider.Exchange(context.Background(), code, codeVerifier, nonce); err == nil {
+		t.Error("Exchange with a used code returned no error")
+	}
+}
+
+func TestExchangeRejectsInvalidResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		idToken      func(claims jwt.MapClaims, header map[string]any)
+		codeVerifier string // jika diisi dipakai saat exchange menggantikan code verifier yang benar
+		nonce        string // jika diisi dipakai saat exchange menggantikan nonce yang benar
+		wantErr      string
+	}{
+		{
+			name:    "wrong nonce",
+			nonce:   "another-nonce",
+			wantErr: "nonce mismatch",
+		},
+		{
+			name:         "bad pkce verifier",
+			codeVerifier: "another-code-verifier",
+			wantErr:      "invalid_grant",
+		},
+		{
+			name:    "wrong audience",
+			idToken: func(claims jwt.MapClaims, header map[string]any) { claims["aud"] = "another-client" },
+			wantErr: "audience",
+		},
+		{
+			name:    "wrong issuer",
+			idToken: func(claims jwt.MapClaims, header map[string]any) { claims["iss"] = "https://evil.example.com" },
+			wantErr: "issuer",
+		},
+		{
+			name: "expired id token",
+			idToken: func(claims jwt.MapClaims, header map[string]any) {
+				claims["iat"] = time.Now().Add(-time.Hour).Unix()
+				claims["exp"] = time.Now().Add(-10 * time.Minute).Unix()
+			},
+			wantErr: "expired",
+		},
+		{
+			name:    "missing expiry",
+			idToken: func(claims jwt.MapClaims, header map[string]any) { delete(claims, "exp") },
+			wantErr: "exp",
+		},
+		{
+			name:    "unknown kid",
+			idToken: func(claims jwt.MapClaims, header map[string]any) { header["kid"] = "unknown-key" },
+			wantErr: "unknown signing key",
+		},
+		{
+			name:    "missing subject",
+			idToken: func(claims jwt.MapClaims, header map[string]any) { delete(claims, "sub") },
+			wantErr: "sub",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issuer := newMockIssuer(t)
+			issuer.idToken = test.idToken
+			provider := issuer.provider()
+
+			nonce, codeVerifier := randomString(t), randomString(t)
+			code, _ := issuer.login(provider, randomString(t), nonce, codeVerifier)
+
+			if test.codeVerifier != "" {
+				codeVerifier = test.codeVerifier
+			}
+			if test.nonce != "" {
+				nonce = test.nonce
+			}
+
+			info, err := provider.Exchange(context.Background(), code, codeVerifier, nonce)
+			if err == nil {
+				t.Fatalf("Exchange = %+v, want error containing %q", info, test.wantErr)
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Exchange error = %q, want error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsTokenSignedByAnotherKey(t *testing.T) {
+	issuer := newMockIssuer(t)
+	provider := issuer.provider()
+
+	// token ditandatangani key lain tetapi memakai kid yang dipublikasikan di JWKS
+	anotherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate rsa key: %v", err)
+	}
+	issuer.key, anotherKey = anotherKey, issuer.key
+	provider.keys = map[string]any{testKeyID: &anotherKey.PublicKey}
+	provider.keysFetched = time.Now()
+
+	nonce, codeVerifier := randomString(t), randomString(t)
+	code, _ := issuer.login(provider, randomString(t), nonce, codeVerifier)
+
+	if _, err = provider.Exchange(context.Background(), code, codeVerifier, nonce); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("Exchange error = %v, want signature error", err)
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	issuer := newMockIssuer(t)
+	provider := issuer.provider()
+	provider.Config.Issuer = issuer.server.URL + "/"
+
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); err == nil || !strings.Contains(err.Error(), "issuer mismatch") {
+		t.Errorf("AuthCodeURL error = %v, want issuer mismatch", err)
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	issuer := newMockIssuer(t)
+	provider := issuer.provider()
+
+	authorizationURL, err := provider.AuthCodeURL(context.Background(), "the-state", "the-nonce", "the-verifier")
+	if err != nil {
+		t.Fatalf("AuthCodeURL returned error: %v", err)
+	}
+
+	parsed, err := url.Parse(authorizationURL)
+	if err != nil {
+		t.Fatalf("invalid authorization url: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte("the-verifier"))
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          testRedirectURL,
+		"scope":                 "openid email profile",
+		"state":                 "the-state",
+		"nonce":                 "the-nonce",
+		"code_challenge":        base64.RawURLEncoding.EncodeToString(sum[:]),
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := parsed.Query().Get(name); got != value {
+			t.Errorf("query %s = %q, want %q", name, got, value)
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomString(t *testing.T) string {
+	t.Helper()
+
+	value, err := GenerateRandom()
+	if err != nil {
+		t.Fatalf("failed to generate random string: %v", err)
+	}
+
+	return value
+}
@@ -0,0 +1,130 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+)
+
+// BreachedList membaca file hash SHA-1 password yang bocor (format "HASH:COUNT" yang diurutkan berdasarkan hash,
+// seperti file pwned passwords "ordered by hash") tanpa memuat seluruh file ke memory
+type BreachedList struct {
+	Path string
+}
+
+func NewBreachedList(path string) (*BreachedList, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	return &BreachedList{Path: path}, nil
+}
+
+// Contains mengecek password dengan model k-anonymity: hanya 5 karakter awal hash yang dipakai untuk mencari range,
+// lalu sisa hash dicocokkan dengan hasil range tersebut
+func (b *BreachedList) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	suffixes, err := b.Range(prefix)
+	if err != nil {
+		return false, err
+	}
+
+	for _, line := range suffixes {
+		if strings.HasPrefix(line, suffix+":") || line == suffix {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Range mengembalikan semua baris "SUFFIX:COUNT" yang hash-nya diawali prefix, sama seperti endpoint range pwned passwords
+func (b *BreachedList) Range(prefix string) ([]string, error) {
+	prefix = strings.ToUpper(prefix)
+
+	file, err := os.Open(b.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+
+	// binary search offset terkecil yang baris berikutnya memiliki prefix >= prefix yang dicari
+	low, high := int64(0), size
+	for low < high {
+		mid := low + (high-low)/2
+		_, line, err := nextLine(file, mid, size)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if err == io.EOF || hashPrefix(line) >= prefix {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	start, _, err := nextLine(file, low, size)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var suffixes []string
+	scanner := bufio.NewScanner(io.NewSectionReader(file, start, size-start))
+	for scanner.Scan() {
+		line := strings.ToUpper(strings.TrimSpace(scanner.Text()))
+		if hashPrefix(line) != prefix {
+			break
+		}
+		suffixes = append(suffixes, line[len(prefix):])
+	}
+
+	return suffixes, scanner.Err()
+}
+
+// nextLine mengembalikan baris pertama yang dimulai pada atau setelah offset
+func nextLine(file *os.File, offset, size int64) (int64, string, error) {
+	start := offset
+	if offset > 0 {
+		reader := bufio.NewReader(io.NewSectionReader(file, offset-1, size-offset+1))
+		skipped, err := reader.ReadString('\n')
+		if err != nil {
+			return 0, "", err
+		}
+		start = offset - 1 + int64(len(skipped))
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(file, start, size-start))
+	line, err := reader.ReadString('\n')
+	if line == "" {
+		return 0, "", io.EOF
+	}
+	if err != nil && err != io.EOF {
+		return 0, "", err
+	}
+
+	return start, strings.ToUpper(strings.TrimSpace(line)), nil
+}
+
+func hashPrefix(line string) string {
+	if len(line) < 5 {
+		return line
+	}
+
+	return line[:5]
+}
@@ -0,0 +1,251 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxHashLength adalah panjang kolom users.password dan password_histories.password
+const MaxHashLength = 255
+
+var (
+	ErrMismatchedPassword = errors.New("password does not match the hash")
+	ErrInvalidHash        = errors.New("invalid password hash")
+	ErrUnknownAlgorithm   = errors.New("unknown password hash algorithm")
+	ErrHashTooLong        = errors.New("password hash parameters produce a hash longer than the password column")
+)
+
+// Hasher membuat dan memverifikasi hash password dalam format yang menyebutkan algoritma dan parameternya
+// (PHC string untuk argon2id, modular crypt format untuk bcrypt)
+type Hasher interface {
+	Hash(password string) (string, error)
+	Verify(encoded string, password string) error
+	NeedsRehash(encoded string) bool
+}
+
+type HasherConfig struct {
+	Algorithm string         `mapstructure:"algorithm"` // argon2id atau bcrypt
+	Argon2id  Argon2idParams `mapstructure:"argon2id"`
+	Bcrypt    BcryptParams   `mapstructure:"bcrypt"`
+}
+
+type Argon2idParams struct {
+	Memory      uint32 `mapstructure:"memory"` // KiB
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  uint32 `mapstructure:"salt_length"`
+	KeyLength   uint32 `mapstructure:"key_length"`
+}
+
+type BcryptParams struct {
+	Cost int `mapstructure:"cost"`
+}
+
+type hasher struct {
+	current  Hasher
+	argon2id Hasher
+	bcrypt   Hasher
+}
+
+// NewHasher membuat hash baru dengan algoritma yang dikonfigurasi, tetapi tetap bisa memverifikasi hash dari algoritma lain
+// sehingga hash lama bisa dimigrasi saat user login (lihat NeedsRehash)
+func NewHasher(config HasherConfig) (Hasher, error) {
+	h := &hasher{argon2id: NewArgon2idHasher(config.Argon2id), bcrypt: NewBcryptHasher(config.Bcrypt)}
+
+	// hash yang terpotong oleh kolom users.password tidak akan pernah cocok lagi, parameter yang terlalu besar ditolak saat start
+	if length := h.argon2id.(*argon2idHasher).encodedLength(); length > MaxHashLength {
+		return nil, fmt.Errorf("%w: %d > %d, reduce argon2id salt_length or key_length", ErrHashTooLong, length, MaxHashLength)
+	}
+
+	switch config.Algorithm {
+	case "", "argon2id":
+		h.current = h.argon2id
+	case "bcrypt":
+		h.current = h.bcrypt
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAlgorithm, config.Algorithm)
+	}
+
+	return h, nil
+}
+
+func (h *hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+func (h *hasher) Verify(encoded string, password string) error {
+	algorithm, err := h.algorithm(encoded)
+	if err != nil {
+		return err
+	}
+
+	return algorithm.Verify(encoded, password)
+}
+
+// NeedsRehash bernilai true jika hash dibuat dengan algoritma lain atau parameter yang sudah tidak dipakai
+func (h *hasher) NeedsRehash(encoded string) bool {
+	algorithm, err := h.algorithm(encoded)
+	if err != nil || algorithm != h.current {
+		return true
+	}
+
+	return algorithm.NeedsRehash(encoded)
+}
+
+func (h *hasher) algorithm(encoded string) (Hasher, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return h.argon2id, nil
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return h.bcrypt, nil
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+}
+
+type argon2idHasher struct {
+	Params Argon2idParams
+}
+
+func NewArgon2idHasher(params Argon2idParams) Hasher {
+	if params.Memory == 0 {
+		params.Memory = 64 * 1024
+	}
+	if params.Iterations == 0 {
+		params.Iterations = 3
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = 2
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = 16
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = 32
+	}
+
+	return &argon2idHasher{Params: params}
+}
+
+// encodedLength mengembalikan panjang PHC string yang dihasilkan Hash dengan parameter ini
+func (h *argon2idHasher) encodedLength() int {
+	prefix := fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$", argon2.Version, h.Params.Memory, h.Params.Iterations, h.Params.Parallelism)
+	return len(prefix) + base64.RawStdEncoding.EncodedLen(int(h.Params.SaltLength)) + 1 + base64.RawStdEncoding.EncodedLen(int(h.Params.KeyLength))
+}
+
+// Hash menghasilkan PHC string: $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.Params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Params.Iterations, h.Params.Memory, h.Params.Parallelism, h.Params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.Params.Memory, h.Params.Iterations,
+		h.Params.Parallelism, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *argon2idHasher) Verify(encoded string, password string) error {
+	params, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedPassword
+	}
+
+	return nil
+}
+
+func (h *argon2idHasher) NeedsRehash(encoded string) bool {
+	params, salt, _, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory != h.Params.Memory || params.Iterations != h.Params.Iterations ||
+		params.Parallelism != h.Params.Parallelism || params.KeyLength != h.Params.KeyLength ||
+		uint32(len(salt)) != h.Params.SaltLength
+}
+
+func decodeArgon2id(encoded string) (*Argon2idParams, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, hash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	params := new(Argon2idParams)
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+type bcryptHasher struct {
+	Params BcryptParams
+}
+
+func NewBcryptHasher(params BcryptParams) Hasher {
+	if params.Cost == 0 {
+		params.Cost = bcrypt.DefaultCost
+	}
+
+	return &bcryptHasher{Params: params}
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Params.Cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+func (h *bcryptHasher) Verify(encoded string, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatchedPassword
+	}
+	if err != nil {
+		return ErrInvalidHash
+	}
+
+	return nil
+}
+
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.Params.Cost
+}
@@ -0,0 +1,165 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrTooShort             = errors.New("password is too short")
+	ErrTooLong              = errors.New("password is too long")
+	ErrMissingUppercase     = errors.New("password must contain an uppercase letter")
+	ErrMissingLowercase     = errors.New("password must contain a lowercase letter")
+	ErrMissingDigit         = errors.New("password must contain a digit")
+	ErrMissingSymbol        = errors.New("password must contain a symbol")
+	ErrTooWeak              = errors.New("password is too easy to guess")
+	ErrContainsPersonalInfo = errors.New("password must not contain the user's name or email")
+	ErrBreached             = errors.New("password has appeared in a data breach")
+	ErrReused               = errors.New("password has been used recently")
+)
+
+type PolicyConfig struct {
+	MinLength          int    `mapstructure:"min_length"`
+	MaxLength          int    `mapstructure:"max_length"`
+	RequireUppercase   bool   `mapstructure:"require_uppercase"`
+	RequireLowercase   bool   `mapstructure:"require_lowercase"`
+	RequireDigit       bool   `mapstructure:"require_digit"`
+	RequireSymbol      bool   `mapstructure:"require_symbol"`
+	MinStrength        int    `mapstructure:"min_strength"` // 0 - 4, seperti skor zxcvbn
+	RejectPersonalInfo bool   `mapstructure:"reject_personal_info"`
+	HistoryCount       int    `mapstructure:"history_count"`
+	MaxAgeDays         int    `mapstructure:"max_age_days"`
+	BreachedHashesFile string `mapstructure:"breached_hashes_file"`
+}
+
+type Policy struct {
+	Config   PolicyConfig
+	Breached *BreachedList
+}
+
+func NewPolicy(config PolicyConfig) (*Policy, error) {
+	if config.MinLength <= 0 {
+		config.MinLength = 8
+	}
+	if config.MaxLength <= 0 {
+		config.MaxLength = 100
+	}
+
+	policy := &Policy{Config: config}
+	if config.BreachedHashesFile != "" {
+		breached, err := NewBreachedList(config.BreachedHashesFile)
+		if err != nil {
+			return nil, err
+		}
+		policy.Breached = breached
+	}
+
+	return policy, nil
+}
+
+// Check mengembalikan pelanggaran pertama dari password terhadap policy. userInputs berisi data user (nama, email)
+// yang tidak boleh dipakai di dalam password dan ikut dihitung sebagai kata yang mudah ditebak
+func (p *Policy) Check(password string, userInputs ...string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.Config.MinLength {
+		return ErrTooShort
+	}
+	if length > p.Config.MaxLength {
+		return ErrTooLong
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	switch {
+	case p.Config.RequireUppercase && !hasUpper:
+		return ErrMissingUppercase
+	case p.Config.RequireLowercase && !hasLower:
+		return ErrMissingLowercase
+	case p.Config.RequireDigit && !hasDigit:
+		return ErrMissingDigit
+	case p.Config.RequireSymbol && !hasSymbol:
+		return ErrMissingSymbol
+	}
+
+	if p.Config.RejectPersonalInfo && containsPersonalInfo(password, userInputs) {
+		return ErrContainsPersonalInfo
+	}
+
+	if p.Config.MinStrength > 0 && Strength(password, userInputs...) < p.Config.MinStrength {
+		return ErrTooWeak
+	}
+
+	if p.Breached != nil {
+		breached, err := p.Breached.Contains(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			return ErrBreached
+		}
+	}
+
+	return nil
+}
+
+// Expired bernilai true jika password sudah lebih lama dari max_age_days, 0 berarti password tidak pernah kadaluarsa
+func (p *Policy) Expired(changedAt time.Time) bool {
+	return Expired(changedAt, p.Config.MaxAgeDays)
+}
+
+func Expired(changedAt time.Time, maxAgeDays int) bool {
+	if maxAgeDays <= 0 {
+		return false
+	}
+
+	return time.Since(changedAt) > time.Duration(maxAgeDays)*24*time.Hour
+}
+
+// containsPersonalInfo mengecek nama, bagian lokal email dan setiap kata dari nama (minimal 3 huruf) di dalam password
+func containsPersonalInfo(password string, userInputs []string) bool {
+	lower := strings.ToLower(password)
+	for _, token := range personalTokens(userInputs) {
+		if strings.Contains(lower, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func personalTokens(userInputs []string) []string {
+	var tokens []string
+	for _, input := range userInputs {
+		input = strings.ToLower(strings.TrimSpace(input))
+		if at := strings.Index(input, "@"); at >= 0 {
+			input = input[:at]
+		}
+
+		words := strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		words = append(words, strings.Join(words, ""))
+		for _, word := range words {
+			if utf8.RuneCountInString(word) >= 3 {
+				tokens = append(tokens, word)
+			}
+		}
+	}
+
+	return tokens
+}
@@ -0,0 +1,218 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// commonPasswords diurutkan dari yang paling sering dipakai, urutan dipakai sebagai perkiraan jumlah tebakan
+var commonPasswords = []string{
+	"password", "123456", "qwerty", "admin", "welcome", "letmein", "iloveyou", "monkey", "dragon", "football",
+	"baseball", "sunshine", "princess", "master", "login", "abc123", "starwars", "shadow", "superman", "trustno1",
+	"hello", "freedom", "whatever", "michael", "jesus", "ninja", "mustang", "access", "batman", "secret",
+	"passw0rd", "qazwsx", "zaq1", "charlie", "donald", "summer", "winter", "spring", "autumn", "flower",
+	"computer", "internet", "google", "samsung", "soccer", "hockey", "jordan", "harley", "ranger", "tigger",
+	"pepper", "ginger", "cookie", "cheese", "chocolate", "love", "angel", "lovely", "killer", "hunter",
+	"indonesia", "jakarta", "bandung", "surabaya", "rahasia", "sayang", "cinta", "bismillah", "kucing", "garuda",
+	"merdeka", "test", "guest", "root", "user", "default", "changeme", "pass", "sandi", "katasandi",
+}
+
+var keyboardRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm", "1234567890", "qazwsxedcrfvtgbyhnujmikolp"}
+
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i")
+
+// Strength memperkirakan kekuatan password dengan skor 0 - 4 seperti zxcvbn: password dipecah menjadi kata umum,
+// data user, pengulangan, urutan dan pola keyboard, lalu jumlah tebakan tiap bagian dikalikan
+func Strength(password string, userInputs ...string) int {
+	guesses := estimateGuesses(password, personalTokens(userInputs))
+
+	switch {
+	case guesses < 3:
+		return 0
+	case guesses < 6:
+		return 1
+	case guesses < 8:
+		return 2
+	case guesses < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// estimateGuesses mengembalikan log10 dari perkiraan jumlah tebakan
+func estimateGuesses(password string, userTokens []string) float64 {
+	original := []rune(password)
+	lower := []rune(strings.ToLower(password))
+	unleet := []rune(leetReplacer.Replace(strings.ToLower(password)))
+	if len(lower) != len(original) {
+		original = lower
+	}
+	if len(unleet) != len(lower) {
+		unleet = lower
+	}
+
+	dictionary := append(append([]string{}, userTokens...), commonPasswords...)
+
+	charset := math.Log10(float64(charsetSize(password)))
+
+	var total float64
+	for i := 0; i < len(lower); {
+		length, cost := 1, charset
+
+		consider := func(l int, c float64) {
+			if l > length || (l == length && c < cost) {
+				length, cost = l, c
+			}
+		}
+
+		// data user dianggap kata yang paling mudah ditebak
+		for rank, word := range dictionary {
+			w := []rune(word)
+			if len(w) < 3 {
+				continue
+			}
+
+			c := math.Log10(float64(rank + 1))
+			switch {
+			case hasRunePrefix(lower[i:], w):
+			case hasRunePrefix(unleet[i:], w):
+				c += math.Log10(2)
+			default:
+				continue
+			}
+			if hasUpper(original[i : i+len(w)]) {
+				c += math.Log10(2)
+			}
+			consider(len(w), c)
+		}
+
+		if l := repeatLength(lower[i:]); l >= 3 {
+			consider(l, charset+math.Log10(float64(l)))
+		}
+
+		if l := sequenceLength(lower[i:]); l >= 3 {
+			consider(l, math.Log10(float64(26*l*2)))
+		}
+
+		if l := keyboardLength(lower[i:]); l >= 3 {
+			consider(l, math.Log10(float64(len(keyboardRows)*l*2)))
+		}
+
+		total += cost
+		i += length
+	}
+
+	return total
+}
+
+func charsetSize(password string) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range password {
+		switch {
+		case r > unicode.MaxASCII:
+			other = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	size := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.present {
+			size += class.size
+		}
+	}
+
+	return max(size, 1)
+}
+
+func repeatLength(s []rune) int {
+	l := 1
+	for l < len(s) && s[l] == s[0] {
+		l++
+	}
+
+	return l
+}
+
+// sequenceLength menghitung panjang urutan naik atau turun seperti abcd, 4321
+func sequenceLength(s []rune) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+
+	delta := s[1] - s[0]
+	if delta != 1 && delta != -1 {
+		return 1
+	}
+
+	l := 2
+	for l < len(s) && s[l]-s[l-1] == delta {
+		l++
+	}
+
+	return l
+}
+
+// keyboardLength menghitung panjang pola yang berurutan di satu baris keyboard, maju atau mundur
+func keyboardLength(s []rune) int {
+	longest := 1
+	for _, row := range keyboardRows {
+		for _, r := range []string{row, reverse(row)} {
+			rowRunes := []rune(r)
+			for start := range rowRunes {
+				l := 0
+				for l < len(s) && start+l < len(rowRunes) && s[l] == rowRunes[start+l] {
+					l++
+				}
+				longest = max(longest, l)
+			}
+		}
+	}
+
+	return longest
+}
+
+func hasRunePrefix(s, prefix []rune) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+
+	for i := range prefix {
+		if s[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasUpper(s []rune) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+
+	return string(r)
+}
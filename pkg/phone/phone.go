@@ -0,0 +1,38 @@
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid phone number")
+
+// Normalize mengubah nomor telepon menjadi format E.164 (misalnya +6281234567890). Spasi, tanda hubung, titik dan kurung
+// diabaikan, awalan 00 dianggap kode internasional, dan nomor lokal yang diawali 0 diberi defaultCountryCode (tanpa +).
+// Hanya format yang dicek (8 - 15 digit dan kode negara tidak diawali 0), bukan apakah nomor benar-benar terdaftar
+func Normalize(raw string, defaultCountryCode string) (string, error) {
+	number := strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "").Replace(strings.TrimSpace(raw))
+
+	switch {
+	case strings.HasPrefix(number, "+"):
+		number = number[1:]
+	case strings.HasPrefix(number, "00"):
+		number = number[2:]
+	case strings.HasPrefix(number, "0"):
+		if defaultCountryCode == "" {
+			return "", ErrInvalid
+		}
+		number = strings.TrimPrefix(defaultCountryCode, "+") + number[1:]
+	}
+
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", ErrInvalid
+	}
+	for _, digit := range number {
+		if digit < '0' || digit > '9' {
+			return "", ErrInvalid
+		}
+	}
+
+	return "+" + number, nil
+}
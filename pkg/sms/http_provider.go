@@ -0,0 +1,126 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPConfig adalah konfigurasi gateway SMS generik. Nilai Params dan Headers boleh berisi placeholder {to} dan {message}
+// yang diganti dengan nomor tujuan dan isi pesan
+type HTTPConfig struct {
+	URL     string            `mapstructure:"url"`
+	Method  string            `mapstructure:"method"`  // default POST
+	Format  string            `mapstructure:"format"`  // json (default), form atau query
+	Headers map[string]string `mapstructure:"headers"` // misalnya Authorization
+	Params  []Param           `mapstructure:"params"`  // field request, misalnya [{"name": "to", "value": "{to}"}, {"name": "text", "value": "{message}"}]
+	Timeout int               `mapstructure:"timeout"` // dalam detik, default 10
+}
+
+// Param berbentuk list (bukan map) karena viper mengubah key map menjadi huruf kecil, sedangkan nama field gateway bisa case sensitive
+type Param struct {
+	Name  string `mapstructure:"name"`
+	Value string `mapstructure:"value"`
+}
+
+// HTTPProvider mengirim SMS lewat HTTP API gateway SMS, response dengan status 2xx dianggap berhasil
+type HTTPProvider struct {
+	Config HTTPConfig
+	Client *http.Client
+}
+
+func NewHTTPProvider(config HTTPConfig) (*HTTPProvider, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("sms http url is required")
+	}
+
+	switch config.Format {
+	case "":
+		config.Format = "json"
+	case "json", "form", "query":
+	default:
+		return nil, fmt.Errorf("unsupported sms http format %q", config.Format)
+	}
+
+	if config.Method == "" {
+		config.Method = http.MethodPost
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10
+	}
+
+	return &HTTPProvider{
+		Config: config,
+		Client: &http.Client{Timeout: time.Duration(config.Timeout) * time.Second},
+	}, nil
+}
+
+func (p *HTTPProvider) Send(ctx context.Context, to string, message string) error {
+	replacer := strings.NewReplacer("{to}", to, "{message}", message)
+	params := make(map[string]string, len(p.Config.Params))
+	for _, param := range p.Config.Params {
+		params[param.Name] = replacer.Replace(param.Value)
+	}
+
+	target := p.Config.URL
+	var body io.Reader
+	contentType := ""
+	switch p.Config.Format {
+	case "json":
+		payload, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal sms payload: %w", err)
+		}
+		body = bytes.NewReader(payload)
+		contentType = "application/json"
+	case "form":
+		body = strings.NewReader(toValues(params).Encode())
+		contentType = "application/x-www-form-urlencoded"
+	case "query":
+		separator := "?"
+		if strings.Contains(target, "?") {
+			separator = "&"
+		}
+		target += separator + toValues(params).Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, p.Config.Method, target, body)
+	if err != nil {
+		return fmt.Errorf("failed to create sms request: %w", err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for key, value := range p.Config.Headers {
+		req.Header.Set(key, replacer.Replace(value))
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sms gateway returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	return nil
+}
+
+func toValues(params map[string]string) url.Values {
+	values := url.Values{}
+	for key, value := range params {
+		values.Set(key, value)
+	}
+
+	return values
+}
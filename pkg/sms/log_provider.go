@@ -0,0 +1,21 @@
+package sms
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogProvider tidak mengirim SMS, isi pesan hanya ditulis ke log. Dipakai saat development
+type LogProvider struct {
+	Log *logrus.Entry
+}
+
+func NewLogProvider(log *logrus.Entry) *LogProvider {
+	return &LogProvider{Log: log}
+}
+
+func (p *LogProvider) Send(ctx context.Context, to string, message string) error {
+	p.Log.WithField("action", "send sms").WithField("to", to).Info("SMS (log driver): " + message)
+	return nil
+}
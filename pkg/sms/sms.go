@@ -0,0 +1,8 @@
+package sms
+
+import "context"
+
+// SMSProvider mengirim SMS ke nomor telepon dalam format E.164
+type SMSProvider interface {
+	Send(ctx context.Context, to string, message string) error
+}
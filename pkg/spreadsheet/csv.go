@@ -0,0 +1,53 @@
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+func readCSV(r io.Reader, limit int) ([][]string, error) {
+	reader := bufio.NewReader(r)
+
+	// Excel dengan locale Indonesia menyimpan CSV dengan pemisah titik koma, pemisah ditentukan dari baris header
+	header, err := reader.Peek(4096)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	header = bytes.TrimPrefix(header, []byte("\xef\xbb\xbf"))
+	if end := bytes.IndexByte(header, '\n'); end >= 0 {
+		header = header[:end]
+	}
+
+	if bom, _ := reader.Peek(3); bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		_, _ = reader.Discard(3)
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		csvReader.Comma = ';'
+	}
+
+	var rows [][]string
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// baris kosong di akhir file tetap diterima, yang ditolak hanya baris berisi data setelah limit
+		if len(rows) >= limit {
+			if !emptyRow(record) {
+				return nil, ErrTooManyRows
+			}
+			continue
+		}
+		rows = append(rows, record)
+	}
+}
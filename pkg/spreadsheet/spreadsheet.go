@@ -0,0 +1,68 @@
+package spreadsheet
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// maxRows dan maxColumns adalah batas worksheet Excel, nomor baris atau kolom di atasnya pasti tidak valid
+const (
+	maxRows    = 1048576
+	maxColumns = 16384
+)
+
+var (
+	ErrUnsupported = errors.New("unsupported file type, use csv or xlsx")
+	ErrTooManyRows = errors.New("file has too many rows")
+)
+
+// Read membaca semua baris file CSV atau XLSX (sheet pertama) berdasarkan ekstensi nama file. Setiap baris berisi
+// nilai sel sebagai string, baris dan sel kosong di akhir dibuang. limit membatasi nomor baris terakhir yang boleh
+// berisi data (termasuk header), lebih dari itu mengembalikan ErrTooManyRows sebelum baris dialokasikan.
+// limit <= 0 berarti batas baris Excel
+func Read(name string, r io.ReaderAt, size int64, limit int) ([][]string, error) {
+	var rows [][]string
+	var err error
+
+	if limit <= 0 || limit > maxRows {
+		limit = maxRows
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		rows, err = readCSV(io.NewSectionReader(r, 0, size), limit)
+	case ".xlsx":
+		rows, err = readXLSX(r, size, limit)
+	default:
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		end := len(row)
+		for end > 0 && strings.TrimSpace(row[end-1]) == "" {
+			end--
+		}
+		rows[i] = row[:end]
+	}
+	for len(rows) > 0 && len(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+
+	return rows, nil
+}
+
+// emptyRow mengembalikan true jika semua sel kosong atau hanya berisi spasi
+func emptyRow(values []string) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,140 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentTypes adalah content type untuk setiap format yang didukung NewWriter
+var ContentTypes = map[string]string{
+	"csv":    "text/csv; charset=utf-8",
+	"xlsx":   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	"ndjson": "application/x-ndjson",
+}
+
+// Writer menulis baris satu per satu tanpa menyimpan seluruh isi file di memory. Nilai boleh berupa nil, string, bool,
+// angka, time.Time atau fmt.Stringer
+type Writer interface {
+	Write(values []any) error
+	Close() error
+}
+
+// NewWriter membuat writer csv, xlsx atau ndjson dengan header berisi nama kolom. Untuk ndjson nama kolom dipakai
+// sebagai key setiap object
+func NewWriter(format string, w io.Writer, header []string) (Writer, error) {
+	switch format {
+	case "csv":
+		return newCSVWriter(w, header)
+	case "xlsx":
+		return newXLSXWriter(w, header)
+	case "ndjson":
+		return &ndjsonWriter{w: w, header: header}, nil
+	}
+
+	return nil, ErrUnsupported
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, header []string) (*csvWriter, error) {
+	writer := &csvWriter{writer: csv.NewWriter(w)}
+	if err := writer.writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+func (w *csvWriter) Write(values []any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = escapeFormula(formatValue(value))
+	}
+
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonWriter struct {
+	w      io.Writer
+	header []string
+}
+
+// Write menyusun object secara manual karena encoding/json mengurutkan key map, sedangkan urutan key harus sama dengan kolom
+func (w *ndjsonWriter) Write(values []any) error {
+	var builder strings.Builder
+	builder.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			builder.WriteByte(',')
+		}
+
+		key, err := json.Marshal(w.header[i])
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		builder.Write(key)
+		builder.WriteByte(':')
+		builder.Write(encoded)
+	}
+	builder.WriteString("}\n")
+
+	_, err := io.WriteString(w.w, builder.String())
+	return err
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	return fmt.Sprint(value)
+}
+
+// escapeFormula menambahkan tanda kutip di depan nilai yang akan dianggap rumus oleh Excel (CSV injection). Nilai
+// yang diawali + atau - tapi berupa angka, misalnya nomor telepon E.164, dibiarkan
+func escapeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+
+	switch value[0] {
+	case '=', '@', '\t', '\r':
+		return "'" + value
+	case '+', '-':
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return "'" + value
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,237 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxPartSize membatasi ukuran setiap file XML di dalam XLSX setelah di decompress, supaya zip bomb tidak menghabiskan memory
+const maxPartSize = 64 << 20
+
+// maxCells membatasi jumlah sel yang dialokasikan, termasuk sel kosong di antara sel yang terisi, supaya referensi
+// kolom yang jauh di setiap baris tidak menghabiskan memory
+const maxCells = 4 << 20
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxText adalah teks shared string atau inline string, rich text terdiri dari beberapa run
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+
+	var builder strings.Builder
+	for _, run := range t.Runs {
+		builder.WriteString(run.T)
+	}
+	return builder.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string   `xml:"r,attr"`
+			T      string   `xml:"t,attr"`
+			V      string   `xml:"v"`
+			Inline xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX membaca sheet pertama workbook, hanya nilai sel yang dibaca (format, rumus dan style diabaikan)
+func readXLSX(r io.ReaderAt, size int64, limit int) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx file: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedStrings xlsxSharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err = decodePart(file, &sharedStrings); err != nil {
+			return nil, err
+		}
+	}
+
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, errors.New("invalid xlsx file: worksheet not found")
+	}
+	var sheet xlsxSheet
+	if err = decodePart(file, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	var cells int
+	for _, row := range sheet.Rows {
+		if row.R < 0 || row.R > maxRows {
+			return nil, fmt.Errorf("invalid xlsx file: invalid row number %d", row.R)
+		}
+
+		// baris kosong tidak ditulis di XLSX, nomor baris dipakai supaya nomor baris hasil baca sama dengan di Excel
+		index := len(rows)
+		if row.R > 0 {
+			index = row.R - 1
+		}
+		// baris bernomor tanpa sel (mis. hanya berisi style) tidak perlu dialokasikan, baris lain setelah limit ditolak
+		// sebelum slice diperbesar sampai nomor baris tersebut
+		if row.R > 0 && len(row.Cells) == 0 {
+			continue
+		}
+		if index >= limit {
+			return nil, ErrTooManyRows
+		}
+		for len(rows) <= index {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for i, cell := range row.Cells {
+			column := i
+			if cell.R != "" {
+				if column, err = columnIndex(cell.R); err != nil {
+					return nil, err
+				}
+			}
+			if column >= maxColumns {
+				return nil, fmt.Errorf("invalid xlsx file: too many columns in row %d", index+1)
+			}
+			for len(values) <= column {
+				values = append(values, "")
+			}
+
+			switch cell.T {
+			case "s":
+				item, err := strconv.Atoi(cell.V)
+				if err != nil || item < 0 || item >= len(sharedStrings.Items) {
+					return nil, fmt.Errorf("invalid xlsx file: shared string %q not found", cell.V)
+				}
+				values[column] = sharedStrings.Items[item].String()
+			case "inlineStr":
+				values[column] = cell.Inline.String()
+			case "b":
+				values[column] = strconv.FormatBool(cell.V == "1")
+			default:
+				values[column] = cell.V
+			}
+		}
+
+		cells += len(values)
+		if cells > maxCells {
+			return nil, errors.New("invalid xlsx file: too many cells")
+		}
+		rows[index] = values
+	}
+
+	return rows, nil
+}
+
+// firstSheetPath mencari file worksheet pertama dari workbook.xml, urutan file di zip tidak selalu sama dengan urutan sheet
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("invalid xlsx file: workbook not found")
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodePart(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("invalid xlsx file: workbook has no sheet")
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+
+	var rels xlsxRelationships
+	if err := decodePart(relsFile, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+func decodePart(file *zip.File, v any) error {
+	reader, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("invalid xlsx file: %w", err)
+	}
+	defer reader.Close()
+
+	if err = xml.NewDecoder(io.LimitReader(reader, maxPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("invalid xlsx file %s: %w", file.Name, err)
+	}
+
+	return nil
+}
+
+// columnIndex mengubah referensi sel seperti "AB12" menjadi index kolom mulai dari 0, kolom setelah XFD ditolak
+func columnIndex(ref string) (int, error) {
+	column := 0
+	for i, char := range ref {
+		if char >= 'A' && char <= 'Z' {
+			column = column*26 + int(char-'A') + 1
+			if column > maxColumns {
+				break
+			}
+			continue
+		}
+		if i == 0 {
+			break
+		}
+		return column - 1, nil
+	}
+
+	return 0, fmt.Errorf("invalid xlsx file: invalid cell reference %q", ref)
+}
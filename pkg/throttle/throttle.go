@@ -0,0 +1,114 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Rule adalah batas percobaan untuk satu scope, semua durasi dalam detik
+type Rule struct {
+	MaxAttempts int `mapstructure:"max_attempts"` // jumlah percobaan gagal dalam Window sebelum dikunci
+	Window      int `mapstructure:"window"`       // rentang waktu penghitungan percobaan
+	Lockout     int `mapstructure:"lockout"`      // durasi kunci pertama, berlipat dua setiap kali terkunci lagi
+	MaxLockout  int `mapstructure:"max_lockout"`  // batas atas durasi kunci
+}
+
+// LockedError dikembalikan ketika subject sedang dikunci, error handler fiber mengubahnya menjadi 429 dengan header Retry-After
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("Too many attempts, please try again in %d seconds", e.Seconds())
+}
+
+func (e *LockedError) Seconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+type Throttle struct {
+	Redis *redis.Client
+	Rules map[string]Rule
+}
+
+func NewThrottle(redis *redis.Client, rules map[string]Rule) *Throttle {
+	return &Throttle{Redis: redis, Rules: rules}
+}
+
+// Check mengembalikan *LockedError jika subject pada scope tersebut sedang dikunci
+func (t *Throttle) Check(ctx context.Context, scope, subject string) error {
+	ttl, err := t.Redis.TTL(ctx, key(scope, subject, "lock")).Result()
+	if err != nil {
+		return err
+	}
+
+	if ttl > 0 {
+		return &LockedError{RetryAfter: ttl}
+	}
+
+	return nil
+}
+
+// Hit mencatat satu percobaan gagal, jika batas tercapai subject dikunci dan *LockedError dikembalikan.
+// Durasi kunci berlipat (progressive backoff) setiap kali subject terkunci lagi dalam 24 jam
+func (t *Throttle) Hit(ctx context.Context, scope, subject string) (*LockedError, error) {
+	rule, ok := t.Rules[scope]
+	if !ok || rule.MaxAttempts <= 0 {
+		return nil, nil
+	}
+
+	attemptsKey := key(scope, subject, "attempts")
+	pipe := t.Redis.TxPipeline()
+	attempts := pipe.Incr(ctx, attemptsKey)
+	pipe.ExpireNX(ctx, attemptsKey, time.Duration(rule.Window)*time.Second)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	if attempts.Val() < int64(rule.MaxAttempts) {
+		return nil, nil
+	}
+
+	strikesKey := key(scope, subject, "strikes")
+	pipe = t.Redis.TxPipeline()
+	strikes := pipe.Incr(ctx, strikesKey)
+	pipe.Expire(ctx, strikesKey, 24*time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	lockout := time.Duration(rule.Lockout) * time.Second
+	if lockout <= 0 {
+		lockout = time.Minute
+	}
+	maxLockout := time.Duration(rule.MaxLockout) * time.Second
+	for i := int64(1); i < strikes.Val(); i++ {
+		lockout *= 2
+		if maxLockout > 0 && lockout >= maxLockout {
+			lockout = maxLockout
+			break
+		}
+	}
+
+	pipe = t.Redis.TxPipeline()
+	pipe.Set(ctx, key(scope, subject, "lock"), strikes.Val(), lockout)
+	pipe.Del(ctx, attemptsKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return &LockedError{RetryAfter: lockout}, nil
+}
+
+// Clear menghapus kunci dan semua hitungan percobaan subject pada scope tersebut
+func (t *Throttle) Clear(ctx context.Context, scope, subject string) error {
+	return t.Redis.Del(ctx, key(scope, subject, "attempts"), key(scope, subject, "strikes"), key(scope, subject, "lock")).Err()
+}
+
+func key(scope, subject, suffix string) string {
+	return fmt.Sprintf("throttle:%s:%s:%s", scope, subject, suffix)
+}
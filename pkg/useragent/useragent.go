@@ -0,0 +1,153 @@
+package useragent
+
+import (
+	"strings"
+)
+
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// Info adalah hasil parsing User-Agent, field yang tidak dikenali berisi string kosong
+type Info struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	OSVersion      string
+	Device         string
+}
+
+// browsers diperiksa berurutan karena User-Agent browser turunan Chromium juga mengandung "Chrome/" dan "Safari/"
+var browsers = []struct {
+	name   string
+	tokens []string
+}{
+	{"Edge", []string{"Edg/", "EdgA/", "EdgiOS/", "Edge/"}},
+	{"Opera", []string{"OPR/", "OPiOS/", "Opera/"}},
+	{"Samsung Internet", []string{"SamsungBrowser/"}},
+	{"Yandex", []string{"YaBrowser/"}},
+	{"Vivaldi", []string{"Vivaldi/"}},
+	{"Firefox", []string{"Firefox/", "FxiOS/"}},
+	{"Chrome", []string{"CriOS/", "Chrome/"}},
+	{"Safari", []string{"Version/"}},
+	{"Internet Explorer", []string{"MSIE ", "rv:"}},
+	{"curl", []string{"curl/"}},
+	{"Postman", []string{"PostmanRuntime/"}},
+	{"okhttp", []string{"okhttp/"}},
+	{"Go HTTP client", []string{"Go-http-client/"}},
+	{"Python Requests", []string{"python-requests/"}},
+}
+
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.1":  "XP",
+}
+
+// Parse membaca browser, sistem operasi dan jenis perangkat dari header User-Agent
+func Parse(userAgent string) Info {
+	info := Info{Device: DeviceUnknown}
+	if userAgent == "" {
+		return info
+	}
+
+	info.OS, info.OSVersion = parseOS(userAgent)
+	info.Browser, info.BrowserVersion = parseBrowser(userAgent, info.OS)
+	info.Device = parseDevice(userAgent, info.OS)
+
+	return info
+}
+
+func parseBrowser(userAgent string, os string) (string, string) {
+	for _, browser := range browsers {
+		for _, token := range browser.tokens {
+			index := strings.Index(userAgent, token)
+			if index < 0 {
+				continue
+			}
+
+			switch {
+			// Version/ juga dipakai browser lain, Safari selalu menyertakan token Safari/
+			case browser.name == "Safari" && !strings.Contains(userAgent, "Safari/"):
+				continue
+			// rv: hanya menandakan IE 11 jika ada Trident/
+			case token == "rv:" && !strings.Contains(userAgent, "Trident/"):
+				continue
+			// WebView Android tidak dianggap Chrome biasa
+			case browser.name == "Chrome" && os == "Android" && strings.Contains(userAgent, "; wv)"):
+				return "Android WebView", version(userAgent[index+len(token):])
+			}
+
+			return browser.name, version(userAgent[index+len(token):])
+		}
+	}
+
+	return "", ""
+}
+
+func parseOS(userAgent string) (string, string) {
+	switch {
+	case strings.Contains(userAgent, "Windows Phone"):
+		return "Windows Phone", version(after(userAgent, "Windows Phone "))
+	case strings.Contains(userAgent, "Windows NT "):
+		nt := version(after(userAgent, "Windows NT "))
+		if name, ok := windowsVersions[nt]; ok {
+			return "Windows", name
+		}
+		return "Windows", nt
+	case strings.Contains(userAgent, "iPhone") || strings.Contains(userAgent, "iPad") || strings.Contains(userAgent, "iPod"):
+		return "iOS", version(strings.ReplaceAll(after(userAgent, "OS "), "_", "."))
+	case strings.Contains(userAgent, "Android"):
+		return "Android", version(after(userAgent, "Android "))
+	case strings.Contains(userAgent, "CrOS"):
+		return "Chrome OS", ""
+	case strings.Contains(userAgent, "Mac OS X"):
+		return "macOS", version(strings.ReplaceAll(after(userAgent, "Mac OS X "), "_", "."))
+	case strings.Contains(userAgent, "Linux"):
+		return "Linux", ""
+	}
+
+	return "", ""
+}
+
+func parseDevice(userAgent string, os string) string {
+	lower := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(lower, "bot") || strings.Contains(lower, "crawler") || strings.Contains(lower, "spider"):
+		return DeviceBot
+	case strings.Contains(userAgent, "iPad") || strings.Contains(lower, "tablet") || (os == "Android" && !strings.Contains(userAgent, "Mobile")):
+		return DeviceTablet
+	case strings.Contains(userAgent, "Mobi") || strings.Contains(userAgent, "iPhone") || strings.Contains(userAgent, "iPod") || os == "Windows Phone":
+		return DeviceMobile
+	case os == "":
+		return DeviceUnknown
+	}
+
+	return DeviceDesktop
+}
+
+func after(value string, token string) string {
+	index := strings.Index(value, token)
+	if index < 0 {
+		return ""
+	}
+
+	return value[index+len(token):]
+}
+
+// version mengambil angka versi di awal string, misalnya "120.0.6099.129 Safari/537.36" menjadi "120.0.6099.129"
+func version(value string) string {
+	end := 0
+	for end < len(value) && end < 32 && (value[end] == '.' || (value[end] >= '0' && value[end] <= '9')) {
+		end++
+	}
+
+	return strings.TrimRight(value[:end], ".")
+}
@@ -3,6 +3,7 @@ package validators
 import (
 	"github.com/go-playground/validator/v10"
 	"github.com/iancoleman/strcase"
+	"strings"
 )
 
 type ErrorMessage struct {
@@ -18,12 +19,16 @@ func GetCustomeMessage(fieldError validator.FieldError) string {
 		return strcase.ToSnake(fieldError.Field()) + " field must be true or false."
 	case "datetime":
 		return strcase.ToSnake(fieldError.Field()) + " field must match the format " + fieldError.Param() + "."
+	case "e164":
+		return strcase.ToSnake(fieldError.Field()) + " field must be a valid phone number, for example +6281234567890."
 	case "eqfield":
 		return strcase.ToSnake(fieldError.Field()) + " field must be equal to " + strcase.ToSnake(fieldError.Param()) + " field."
 	case "exists":
 		return strcase.ToSnake(fieldError.Field()) + " is invalid."
 	case "image":
 		return strcase.ToSnake(fieldError.Field()) + " field must be an image."
+	case "len":
+		return strcase.ToSnake(fieldError.Field()) + " field must be exactly " + fieldError.Param() + " characters."
 	case "match_password":
 		return strcase.ToSnake(fieldError.Field()) + " is incorrect."
 	case "max":
@@ -36,6 +41,12 @@ func GetCustomeMessage(fieldError validator.FieldError) string {
 		return strcase.ToSnake(fieldError.Field()) + " field must be a number"
 	case "oneof":
 		return strcase.ToSnake(fieldError.Field()) + " is invalid."
+	case "password_policy":
+		// param berisi alasan yang diawali "password", diganti dengan nama field (mis. new_password)
+		if fieldError.Param() == "" {
+			return strcase.ToSnake(fieldError.Field()) + " does not meet the password policy."
+		}
+		return strcase.ToSnake(fieldError.Field()) + strings.TrimPrefix(fieldError.Param(), "password") + "."
 	case "required":
 		return strcase.ToSnake(fieldError.Field()) + " is required."
 	case "size":
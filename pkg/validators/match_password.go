@@ -1,12 +1,12 @@
 package validators
 
 import (
+	"github.com/alfianyulianto/pds-service/pkg/password"
 	"github.com/go-playground/validator/v10"
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-func MatchPassword(db *gorm.DB) validator.Func {
+func MatchPassword(db *gorm.DB, hasher password.Hasher) validator.Func {
 	return func(fl validator.FieldLevel) bool {
 		param := fl.Param()
 		value := fl.Field().String()
@@ -26,7 +26,7 @@ func MatchPassword(db *gorm.DB) validator.Func {
 			return false
 		}
 
-		err := bcrypt.CompareHashAndPassword([]byte(user["password"].(string)), []byte(value))
+		err := hasher.Verify(user["password"].(string), value)
 		if err != nil {
 			return false
 		}
@@ -0,0 +1,112 @@
+package validators
+
+import (
+	"errors"
+	"fmt"
+	"github.com/alfianyulianto/pds-service/pkg/password"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"reflect"
+)
+
+// PasswordPolicy adalah validasi level struct untuk field dengan tag `password:"policy"`, didaftarkan dengan
+// RegisterStructValidation untuk setiap request yang memiliki field tersebut. Field Name dan Email pada struct yang sama
+// dipakai sebagai data user, jika struct memiliki field ID data user dan riwayat password diambil dari database.
+// Pelanggaran dilaporkan dengan tag password_policy dan param berisi alasannya, sehingga user tahu apa yang harus diperbaiki
+func PasswordPolicy(db *gorm.DB, policy *password.Policy, hasher password.Hasher) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		current := sl.Current()
+		for i := 0; i < current.NumField(); i++ {
+			field := current.Type().Field(i)
+			if field.Tag.Get("password") != "policy" || current.Field(i).Kind() != reflect.String {
+				continue
+			}
+
+			// password kosong ditangani required/omitempty pada field
+			value := current.Field(i).String()
+			if value == "" {
+				continue
+			}
+
+			if err := checkPasswordPolicy(db, policy, hasher, current, value); err != nil {
+				sl.ReportError(value, field.Name, field.Name, "password_policy", passwordPolicyReason(policy, err))
+			}
+		}
+	}
+}
+
+func checkPasswordPolicy(db *gorm.DB, policy *password.Policy, hasher password.Hasher, parent reflect.Value, value string) error {
+	var userInputs []string
+	for _, name := range []string{"Name", "Email"} {
+		field := parent.FieldByName(name)
+		if field.IsValid() && field.Kind() == reflect.String {
+			userInputs = append(userInputs, field.String())
+		}
+	}
+
+	var userID uuid.UUID
+	if field := parent.FieldByName("ID"); field.IsValid() {
+		userID, _ = field.Interface().(uuid.UUID)
+	}
+
+	// jika data user atau riwayat password gagal dibaca password ditolak, supaya validasi tidak lolos tanpa pengecekan
+	var hashes []string
+	if userID != uuid.Nil {
+		user := make(map[string]interface{})
+		if err := db.Table("users").Select("name, email, password").Where("id = ?", userID).Take(&user).Error; err != nil {
+			return err
+		}
+		for _, key := range []string{"name", "email"} {
+			if v, ok := user[key].(string); ok {
+				userInputs = append(userInputs, v)
+			}
+		}
+
+		if policy.Config.HistoryCount > 0 {
+			if v, ok := user["password"].(string); ok {
+				hashes = append(hashes, v)
+			}
+
+			var histories []string
+			err := db.Table("password_histories").Where("user_id = ?", userID).Order("created_at desc").
+				Limit(policy.Config.HistoryCount).Pluck("password", &histories).Error
+			if err != nil {
+				return err
+			}
+			hashes = append(hashes, histories...)
+		}
+	}
+
+	if err := policy.Check(value, userInputs...); err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		if hasher.Verify(hash, value) == nil {
+			return password.ErrReused
+		}
+	}
+
+	return nil
+}
+
+// passwordPolicyReason mengubah pelanggaran menjadi alasan yang diawali "password", lihat GetCustomeMessage
+func passwordPolicyReason(policy *password.Policy, err error) string {
+	switch {
+	case errors.Is(err, password.ErrTooShort):
+		return fmt.Sprintf("password must be at least %d characters", policy.Config.MinLength)
+	case errors.Is(err, password.ErrTooLong):
+		return fmt.Sprintf("password must not be greater than %d characters", policy.Config.MaxLength)
+	case errors.Is(err, password.ErrReused):
+		return fmt.Sprintf("password must not be one of your last %d passwords", policy.Config.HistoryCount)
+	case errors.Is(err, password.ErrMissingUppercase), errors.Is(err, password.ErrMissingLowercase),
+		errors.Is(err, password.ErrMissingDigit), errors.Is(err, password.ErrMissingSymbol),
+		errors.Is(err, password.ErrTooWeak), errors.Is(err, password.ErrContainsPersonalInfo),
+		errors.Is(err, password.ErrBreached):
+		return err.Error()
+	}
+
+	// error database atau file breached password, detailnya tidak ditampilkan ke user
+	return "password could not be checked against the password policy, please try again"
+}
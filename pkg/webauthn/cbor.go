@@ -0,0 +1,169 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var ErrInvalidCBOR = errors.New("invalid cbor data")
+
+// cborMaxDepth membatasi kedalaman array/map supaya input dari client tidak bisa menghabiskan stack
+const cborMaxDepth = 16
+
+// decodeCBOR membaca satu item CBOR (RFC 8949) dari awal data dan mengembalikan jumlah byte yang dibaca. Hanya bagian
+// yang dipakai WebAuthn yang didukung: integer, byte/text string, array, map, tag, false, true dan null. Integer
+// dikembalikan sebagai int64, map sebagai map[any]any dengan key int64 atau string
+func decodeCBOR(data []byte) (any, int, error) {
+	d := &cborDecoder{data: data}
+	value, err := d.decode(0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return value, d.pos, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) decode(depth int) (any, error) {
+	if depth > cborMaxDepth || d.pos >= len(d.data) {
+		return nil, ErrInvalidCBOR
+	}
+
+	initial := d.data[d.pos]
+	d.pos++
+	major, info := initial>>5, initial&0x1f
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		default:
+			return nil, ErrInvalidCBOR
+		}
+	}
+
+	n, err := d.argument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		if n > math.MaxInt64 {
+			return nil, ErrInvalidCBOR
+		}
+		return int64(n), nil
+	case 1:
+		if n > math.MaxInt64 {
+			return nil, ErrInvalidCBOR
+		}
+		return -1 - int64(n), nil
+	case 2:
+		return d.bytes(n)
+	case 3:
+		b, err := d.bytes(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case 4:
+		// setiap item minimal 1 byte, panjang yang lebih besar dari sisa data pasti tidak valid
+		if n > uint64(len(d.data)-d.pos) {
+			return nil, ErrInvalidCBOR
+		}
+		items := make([]any, 0, n)
+		for i := uint64(0); i < n; i++ {
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case 5:
+		if n > uint64(len(d.data)-d.pos)/2 {
+			return nil, ErrInvalidCBOR
+		}
+		items := make(map[any]any, n)
+		for i := uint64(0); i < n; i++ {
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, ErrInvalidCBOR
+			}
+
+			value, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items[key] = value
+		}
+		return items, nil
+	case 6:
+		// tag tidak dipakai WebAuthn, cukup ambil isinya
+		return d.decode(depth + 1)
+	}
+
+	return nil, ErrInvalidCBOR
+}
+
+// argument membaca nilai argument sesuai additional info, panjang indefinite (31) tidak didukung
+func (d *cborDecoder) argument(info byte) (uint64, error) {
+	var size int
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, ErrInvalidCBOR
+	}
+
+	if len(d.data)-d.pos < size {
+		return 0, ErrInvalidCBOR
+	}
+
+	b := d.data[d.pos : d.pos+size]
+	d.pos += size
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	default:
+		return binary.BigEndian.Uint64(b), nil
+	}
+}
+
+func (d *cborDecoder) bytes(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, ErrInvalidCBOR
+	}
+
+	b := make([]byte, n)
+	copy(b, d.data[d.pos:d.pos+int(n)])
+	d.pos += int(n)
+
+	return b, nil
+}
@@ -0,0 +1,150 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// cborMap adalah map CBOR dengan urutan key yang tetap, dipakai software authenticator di test
+type cborMap [][2]any
+
+// encodeCBOR adalah encoder CBOR minimal untuk test: integer, byte/text string, array dan map
+func encodeCBOR(value any) []byte {
+	switch v := value.(type) {
+	case int64:
+		if v < 0 {
+			return cborHeader(1, uint64(-1-v))
+		}
+		return cborHeader(0, uint64(v))
+	case []byte:
+		return append(cborHeader(2, uint64(len(v))), v...)
+	case string:
+		return append(cborHeader(3, uint64(len(v))), v...)
+	case []any:
+		data := cborHeader(4, uint64(len(v)))
+		for _, item := range v {
+			data = append(data, encodeCBOR(item)...)
+		}
+		return data
+	case cborMap:
+		data := cborHeader(5, uint64(len(v)))
+		for _, pair := range v {
+			data = append(data, encodeCBOR(pair[0])...)
+			data = append(data, encodeCBOR(pair[1])...)
+		}
+		return data
+	}
+
+	panic("unsupported cbor test value")
+}
+
+func cborHeader(major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n <= 0xff:
+		return []byte{major<<5 | 24, byte(n)}
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64([]byte{major<<5 | 27}, n)
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want any
+	}{
+		{name: "small unsigned", data: []byte{0x0a}, want: int64(10)},
+		{name: "uint16", data: []byte{0x19, 0x01, 0x00}, want: int64(256)},
+		{name: "negative", data: []byte{0x38, 0x63}, want: int64(-100)},
+		{name: "byte string", data: []byte{0x43, 1, 2, 3}, want: []byte{1, 2, 3}},
+		{name: "text string", data: []byte{0x63, 'f', 'm', 't'}, want: "fmt"},
+		{name: "array", data: []byte{0x82, 0x01, 0x20}, want: []any{int64(1), int64(-1)}},
+		{name: "map", data: []byte{0xa2, 0x01, 0x02, 0x61, 'a', 0xf5}, want: map[any]any{int64(1): int64(2), "a": true}},
+		{name: "tag", data: []byte{0xc1, 0x01}, want: int64(1)},
+		{name: "null", data: []byte{0xf6}, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// byte tambahan di akhir tidak ikut dibaca, size menunjukkan panjang item pertama
+			got, size, err := decodeCBOR(append(test.data, 0xff))
+			if err != nil {
+				t.Fatalf("decodeCBOR returned error: %v", err)
+			}
+			if size != len(test.data) {
+				t.Errorf("decodeCBOR size = %d, want %d", size, len(test.data))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("decodeCBOR = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCBORRejectsMalformed(t *testing.T) {
+	deep := make([]byte, cborMaxDepth+2)
+	for i := range deep {
+		deep[i] = 0x81 // array berisi satu item, bersarang
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "truncated uint16", data: []byte{0x19, 0x01}},
+		{name: "truncated byte string", data: []byte{0x45, 1, 2}},
+		{name: "truncated text string", data: []byte{0x63, 'f'}},
+		{name: "truncated array", data: []byte{0x82, 0x01}},
+		{name: "truncated map value", data: []byte{0xa1, 0x01}},
+		{name: "byte string length beyond data", data: []byte{0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{name: "array length beyond data", data: []byte{0x9a, 0xff, 0xff, 0xff, 0xff}},
+		{name: "map length beyond data", data: []byte{0xba, 0xff, 0xff, 0xff, 0xff}},
+		{name: "indefinite length", data: []byte{0x5f, 0x41, 0x01, 0xff}},
+		{name: "reserved additional info", data: []byte{0x1c}},
+		{name: "unsigned overflow", data: []byte{0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{name: "negative overflow", data: []byte{0x3b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{name: "array map key", data: []byte{0xa1, 0x80, 0x01}},
+		{name: "float", data: []byte{0xf9, 0x3c, 0x00}},
+		{name: "too deep", data: append(deep, 0x01)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if value, _, err := decodeCBOR(test.data); !errors.Is(err, ErrInvalidCBOR) {
+				t.Errorf("decodeCBOR = %#v, %v, want error %v", value, err, ErrInvalidCBOR)
+			}
+		})
+	}
+}
+
+func TestParsePublicKeyRejectsInvalidKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     []byte
+		wantErr error
+	}{
+		{name: "truncated", key: encodeCBOR(cborMap{{int64(1), int64(2)}, {int64(3), AlgES256}})[:3], wantErr: ErrInvalidPublicKey},
+		{name: "not a map", key: encodeCBOR([]any{int64(1)}), wantErr: ErrInvalidPublicKey},
+		{name: "unsupported algorithm", key: encodeCBOR(cborMap{{int64(1), int64(2)}, {int64(3), int64(-999)}}), wantErr: ErrUnsupportedAlgorithm},
+		{name: "ec point not on curve", key: encodeCBOR(cborMap{{int64(1), int64(2)}, {int64(3), AlgES256}, {int64(-1), int64(1)}, {int64(-2), make([]byte, 32)}, {int64(-3), make([]byte, 32)}}), wantErr: ErrInvalidPublicKey},
+		{name: "ec key type mismatch", key: encodeCBOR(cborMap{{int64(1), int64(1)}, {int64(3), AlgES256}}), wantErr: ErrInvalidPublicKey},
+		{name: "short ed25519 key", key: encodeCBOR(cborMap{{int64(1), int64(1)}, {int64(3), AlgEdDSA}, {int64(-1), int64(6)}, {int64(-2), make([]byte, 31)}}), wantErr: ErrInvalidPublicKey},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if key, err := parsePublicKey(test.key); !errors.Is(err, test.wantErr) {
+				t.Errorf("parsePublicKey = %+v, %v, want error %v", key, err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrInvalidPublicKey     = errors.New("invalid credential public key")
+	ErrUnsupportedAlgorithm = errors.New("unsupported credential algorithm")
+	ErrInvalidSignature     = errors.New("invalid assertion signature")
+)
+
+// algoritma COSE (RFC 9053) yang didukung, urutan ini juga dipakai sebagai pubKeyCredParams
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgES384 int64 = -35
+	AlgES512 int64 = -36
+	AlgRS256 int64 = -257
+)
+
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgES384, AlgES512, AlgRS256}
+
+const (
+	coseKeyType      int64 = 1
+	coseKeyAlgorithm int64 = 3
+
+	coseKeyTypeOKP int64 = 1
+	coseKeyTypeEC2 int64 = 2
+	coseKeyTypeRSA int64 = 3
+)
+
+type publicKey struct {
+	Algorithm int64
+	Key       crypto.PublicKey
+}
+
+// parsePublicKey membaca COSE_Key dari authenticator menjadi public key Go
+func parsePublicKey(data []byte) (*publicKey, error) {
+	value, _, err := decodeCBOR(data)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	key, ok := value.(map[any]any)
+	if !ok {
+		return nil, ErrInvalidPublicKey
+	}
+
+	kty, _ := key[coseKeyType].(int64)
+	alg, _ := key[coseKeyAlgorithm].(int64)
+	x, _ := key[int64(-2)].([]byte)
+
+	switch alg {
+	case AlgES256, AlgES384, AlgES512:
+		if kty != coseKeyTypeEC2 {
+			return nil, ErrInvalidPublicKey
+		}
+		crv, _ := key[int64(-1)].(int64)
+		y, _ := key[int64(-3)].([]byte)
+		pub, err := ecPublicKey(alg, crv, x, y)
+		if err != nil {
+			return nil, err
+		}
+		return &publicKey{Algorithm: alg, Key: pub}, nil
+	case AlgEdDSA:
+		crv, _ := key[int64(-1)].(int64)
+		if kty != coseKeyTypeOKP || crv != 6 || len(x) != ed25519.PublicKeySize {
+			return nil, ErrInvalidPublicKey
+		}
+		return &publicKey{Algorithm: alg, Key: ed25519.PublicKey(x)}, nil
+	case AlgRS256:
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if kty != coseKeyTypeRSA || len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, ErrInvalidPublicKey
+		}
+		exponent := new(big.Int).SetBytes(e)
+		return &publicKey{Algorithm: alg, Key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}}, nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
+
+func ecPublicKey(alg, crv int64, x, y []byte) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	var point ecdh.Curve
+	switch {
+	case alg == AlgES256 && crv == 1:
+		curve, point = elliptic.P256(), ecdh.P256()
+	case alg == AlgES384 && crv == 2:
+		curve, point = elliptic.P384(), ecdh.P384()
+	case alg == AlgES512 && crv == 3:
+		curve, point = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, ErrInvalidPublicKey
+	}
+
+	size := (curve.Params().BitSize + 7) / 8
+	if len(x) != size || len(y) != size {
+		return nil, ErrInvalidPublicKey
+	}
+
+	// ecdh memastikan titik berada di kurva
+	uncompressed := append(append([]byte{4}, x...), y...)
+	if _, err := point.NewPublicKey(uncompressed); err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+}
+
+// verify memeriksa signature assertion, signature ECDSA dalam format ASN.1 DER sesuai spesifikasi WebAuthn
+func (k *publicKey) verify(data, signature []byte) error {
+	var valid bool
+	switch pub := k.Key.(type) {
+	case *ecdsa.PublicKey:
+		var digest []byte
+		switch k.Algorithm {
+		case AlgES256:
+			sum := sha256.Sum256(data)
+			digest = sum[:]
+		case AlgES384:
+			sum := sha512.Sum384(data)
+			digest = sum[:]
+		default:
+			sum := sha512.Sum512(data)
+			digest = sum[:]
+		}
+		valid = ecdsa.VerifyASN1(pub, digest, signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(pub, data, signature)
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(data)
+		valid = rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], signature) == nil
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
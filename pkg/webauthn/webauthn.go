@@ -0,0 +1,444 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidResponse          = errors.New("invalid webauthn response")
+	ErrInvalidClientData        = errors.New("invalid client data")
+	ErrChallengeMismatch        = errors.New("challenge does not match")
+	ErrOriginMismatch           = errors.New("origin is not allowed")
+	ErrInvalidAuthenticatorData = errors.New("invalid authenticator data")
+	ErrRPIDMismatch             = errors.New("relying party id does not match")
+	ErrUserNotPresent           = errors.New("user presence flag is not set")
+	ErrUserNotVerified          = errors.New("user verification flag is not set")
+	ErrSignCountInvalid         = errors.New("sign counter did not increase, authenticator may be cloned")
+)
+
+// flag authenticator data (WebAuthn Level 2, 6.1)
+const (
+	flagUserPresent      byte = 0x01
+	flagUserVerified     byte = 0x04
+	flagAttestedCredData byte = 0x40
+	flagExtensionData    byte = 0x80
+)
+
+var encoding = base64.RawURLEncoding
+
+type Config struct {
+	RPID             string   `mapstructure:"rp_id"`   // domain aplikasi, misalnya alfian.my.id
+	RPName           string   `mapstructure:"rp_name"` // nama yang ditampilkan browser
+	Origins          []string `mapstructure:"origins"` // origin frontend yang boleh memakai passkey, misalnya https://alfian.my.id
+	Timeout          int      `mapstructure:"timeout"` // detik
+	UserVerification string   `mapstructure:"user_verification"`
+}
+
+type WebAuthn struct {
+	Config Config
+}
+
+func New(config Config) *WebAuthn {
+	if config.Timeout <= 0 {
+		config.Timeout = 300
+	}
+	if config.UserVerification == "" {
+		config.UserVerification = "preferred"
+	}
+
+	return &WebAuthn{Config: config}
+}
+
+type RelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type User struct {
+	ID          string `json:"id"` // base64url user handle
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type      string `json:"type"`
+	Algorithm int64  `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type       string   `json:"type"`
+	ID         string   `json:"id"` // base64url credential id
+	Transports []string `json:"transports,omitempty"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CreationOptions adalah PublicKeyCredentialCreationOptions untuk navigator.credentials.create, buffer dalam base64url
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     RelyingParty           `json:"rp"`
+	User                   User                   `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int                    `json:"timeout"` // milidetik
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions adalah PublicKeyCredentialRequestOptions untuk navigator.credentials.get, buffer dalam base64url
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int                    `json:"timeout"` // milidetik
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// AttestationResponse adalah hasil navigator.credentials.create yang dikirim frontend, buffer dalam base64url
+type AttestationResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports"`
+	} `json:"response"`
+}
+
+// AssertionResponse adalah hasil navigator.credentials.get yang dikirim frontend, buffer dalam base64url
+type AssertionResponse struct {
+	ID       string `json:"id"`
+	RawID    string `json:"rawId"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+// Credential adalah passkey yang sudah diverifikasi dan siap disimpan
+type Credential struct {
+	ID           []byte
+	PublicKey    []byte // COSE_Key
+	Algorithm    int64
+	SignCount    uint32
+	AAGUID       []byte
+	Transports   []string
+	UserVerified bool
+}
+
+// Assertion adalah hasil verifikasi login dengan passkey
+type Assertion struct {
+	SignCount    uint32
+	UserVerified bool
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type authenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	AAGUID       []byte
+	CredentialID []byte
+	PublicKey    []byte
+}
+
+// NewChallenge membuat challenge acak 32 byte dalam base64url
+func NewChallenge() (string, error) {
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(challenge), nil
+}
+
+// EncodeID dan DecodeID mengubah buffer (credential id, user handle) dari dan ke base64url
+func EncodeID(id []byte) string {
+	return encoding.EncodeToString(id)
+}
+
+func DecodeID(id string) ([]byte, error) {
+	return encoding.DecodeString(strings.TrimRight(id, "="))
+}
+
+func (w *WebAuthn) NewCreationOptions(user User, exclude []CredentialDescriptor) (*CreationOptions, error) {
+	challenge, err := NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]CredentialParameter, 0, len(SupportedAlgorithms))
+	for _, alg := range SupportedAlgorithms {
+		params = append(params, CredentialParameter{Type: "public-key", Algorithm: alg})
+	}
+
+	if exclude == nil {
+		exclude = []CredentialDescriptor{}
+	}
+
+	return &CreationOptions{
+		Challenge:          challenge,
+		RP:                 RelyingParty{ID: w.Config.RPID, Name: w.Config.RPName},
+		User:               user,
+		PubKeyCredParams:   params,
+		Timeout:            w.Config.Timeout * 1000,
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: w.Config.UserVerification,
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// NewRequestOptions membuat options login, allow kosong berarti browser menawarkan semua passkey untuk RP ini (discoverable credential)
+func (w *WebAuthn) NewRequestOptions(allow []CredentialDescriptor) (*RequestOptions, error) {
+	challenge, err := NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	if allow == nil {
+		allow = []CredentialDescriptor{}
+	}
+
+	return &RequestOptions{
+		Challenge:        challenge,
+		Timeout:          w.Config.Timeout * 1000,
+		RPID:             w.Config.RPID,
+		AllowCredentials: allow,
+		UserVerification: w.Config.UserVerification,
+	}, nil
+}
+
+// VerifyRegistration memverifikasi hasil navigator.credentials.create terhadap challenge yang diterbitkan. Attestation
+// statement tidak diverifikasi karena options meminta attestation "none"
+func (w *WebAuthn) VerifyRegistration(response *AttestationResponse, challenge string) (*Credential, error) {
+	if response.Type != "public-key" {
+		return nil, ErrInvalidResponse
+	}
+
+	if _, err := w.verifyClientData(response.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	rawAttestation, err := DecodeID(response.Response.AttestationObject)
+	if err != nil {
+		return nil, ErrInvalidResponse
+	}
+	value, _, err := decodeCBOR(rawAttestation)
+	if err != nil {
+		return nil, ErrInvalidResponse
+	}
+	attestation, ok := value.(map[any]any)
+	if !ok {
+		return nil, ErrInvalidResponse
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, ErrInvalidResponse
+	}
+
+	authData, err := w.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.CredentialID == nil {
+		return nil, ErrInvalidAuthenticatorData
+	}
+
+	rawID, err := DecodeID(response.RawID)
+	if err != nil || !bytes.Equal(rawID, authData.CredentialID) {
+		return nil, ErrInvalidResponse
+	}
+
+	key, err := parsePublicKey(authData.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:           authData.CredentialID,
+		PublicKey:    authData.PublicKey,
+		Algorithm:    key.Algorithm,
+		SignCount:    authData.SignCount,
+		AAGUID:       authData.AAGUID,
+		Transports:   response.Response.Transports,
+		UserVerified: authData.Flags&flagUserVerified != 0,
+	}, nil
+}
+
+// VerifyAssertion memverifikasi hasil navigator.credentials.get dengan public key dan sign counter yang tersimpan
+func (w *WebAuthn) VerifyAssertion(response *AssertionResponse, challenge string, credentialPublicKey []byte, signCount uint32) (*Assertion, error) {
+	if response.Type != "public-key" {
+		return nil, ErrInvalidResponse
+	}
+
+	rawClientData, err := w.verifyClientData(response.Response.ClientDataJSON, "webauthn.get", challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	rawAuthData, err := DecodeID(response.Response.AuthenticatorData)
+	if err != nil {
+		return nil, ErrInvalidResponse
+	}
+	authData, err := w.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := DecodeID(response.Response.Signature)
+	if err != nil {
+		return nil, ErrInvalidResponse
+	}
+
+	key, err := parsePublicKey(credentialPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	clientDataHash := sha256.Sum256(rawClientData)
+	signed := append(append([]byte{}, rawAuthData...), clientDataHash[:]...)
+	if err = key.verify(signed, signature); err != nil {
+		return nil, err
+	}
+
+	// authenticator yang tidak memakai counter selalu mengirim 0
+	if (authData.SignCount != 0 || signCount != 0) && authData.SignCount <= signCount {
+		return nil, ErrSignCountInvalid
+	}
+
+	return &Assertion{SignCount: authData.SignCount, UserVerified: authData.Flags&flagUserVerified != 0}, nil
+}
+
+// ClientDataChallenge mengambil challenge dari clientDataJSON, dipakai untuk mencari challenge login yang diterbitkan
+// sebelum response diverifikasi
+func ClientDataChallenge(clientDataJSON string) (string, error) {
+	raw, err := DecodeID(clientDataJSON)
+	if err != nil {
+		return "", ErrInvalidClientData
+	}
+
+	data := new(clientData)
+	if err = json.Unmarshal(raw, data); err != nil || data.Challenge == "" {
+		return "", ErrInvalidClientData
+	}
+
+	return data.Challenge, nil
+}
+
+func (w *WebAuthn) verifyClientData(clientDataJSON, ceremony, challenge string) ([]byte, error) {
+	raw, err := DecodeID(clientDataJSON)
+	if err != nil {
+		return nil, ErrInvalidClientData
+	}
+
+	data := new(clientData)
+	if err = json.Unmarshal(raw, data); err != nil {
+		return nil, ErrInvalidClientData
+	}
+
+	if data.Type != ceremony {
+		return nil, ErrInvalidClientData
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimRight(data.Challenge, "=")), []byte(challenge)) != 1 {
+		return nil, ErrChallengeMismatch
+	}
+	if !slices.Contains(w.Config.Origins, data.Origin) {
+		return nil, ErrOriginMismatch
+	}
+
+	return raw, nil
+}
+
+func (w *WebAuthn) verifyAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	data, err := parseAuthenticatorData(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	rpIDHash := sha256.Sum256([]byte(w.Config.RPID))
+	if subtle.ConstantTimeCompare(data.RPIDHash, rpIDHash[:]) != 1 {
+		return nil, ErrRPIDMismatch
+	}
+	if data.Flags&flagUserPresent == 0 {
+		return nil, ErrUserNotPresent
+	}
+	if w.Config.UserVerification == "required" && data.Flags&flagUserVerified == 0 {
+		return nil, ErrUserNotVerified
+	}
+
+	return data, nil
+}
+
+// parseAuthenticatorData membaca rpIdHash (32) | flags (1) | signCount (4) | attested credential data | extensions
+func parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, ErrInvalidAuthenticatorData
+	}
+
+	data := &authenticatorData{
+		RPIDHash:  raw[:32],
+		Flags:     raw[32],
+		SignCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	rest := raw[37:]
+
+	if data.Flags&flagAttestedCredData != 0 {
+		// aaguid (16) | credentialIdLength (2) | credentialId | credentialPublicKey (COSE_Key)
+		if len(rest) < 18 {
+			return nil, ErrInvalidAuthenticatorData
+		}
+		data.AAGUID = rest[:16]
+		length := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if length == 0 || length > 1023 || len(rest) < length {
+			return nil, ErrInvalidAuthenticatorData
+		}
+		data.CredentialID = rest[:length]
+		rest = rest[length:]
+
+		_, size, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, ErrInvalidAuthenticatorData
+		}
+		data.PublicKey = rest[:size]
+		rest = rest[size:]
+	}
+
+	if data.Flags&flagExtensionData != 0 {
+		_, size, err := decodeCBOR(rest)
+		if err != nil {
+			return nil, ErrInvalidAuthenticatorData
+		}
+		rest = rest[size:]
+	}
+
+	if len(rest) != 0 {
+		return nil, ErrInvalidAuthenticatorData
+	}
+
+	return data, nil
+}
@@ -0,0 +1,533 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+// softAuthenticator adalah authenticator software yang membuat attestationObject, authenticatorData dan signature
+// seperti authenticator sungguhan. Field RPID, Origin, Flags dan SignCount bisa diubah untuk mensimulasikan response
+// yang tidak valid
+type softAuthenticator struct {
+	t            *testing.T
+	algorithm    int64
+	signer       crypto.Signer
+	credentialID []byte
+
+	RPID      string
+	Origin    string
+	Flags     byte
+	SignCount uint32
+}
+
+func newSoftAuthenticator(t *testing.T, algorithm int64) *softAuthenticator {
+	t.Helper()
+
+	var signer crypto.Signer
+	var err error
+	switch algorithm {
+	case AlgES256:
+		signer, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgEdDSA:
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		t.Fatalf("unsupported test algorithm %d", algorithm)
+	}
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	credentialID := make([]byte, 16)
+	if _, err = rand.Read(credentialID); err != nil {
+		t.Fatalf("failed to generate credential id: %v", err)
+	}
+
+	return &softAuthenticator{
+		t:            t,
+		algorithm:    algorithm,
+		signer:       signer,
+		credentialID: credentialID,
+		RPID:         testRPID,
+		Origin:       testOrigin,
+		Flags:        flagUserPresent | flagUserVerified,
+	}
+}
+
+// coseKey mengubah public key menjadi COSE_Key (RFC 9053)
+func (a *softAuthenticator) coseKey() []byte {
+	switch pub := a.signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		x, y := make([]byte, 32), make([]byte, 32)
+		pub.X.FillBytes(x)
+		pub.Y.FillBytes(y)
+		return encodeCBOR(cborMap{{int64(1), int64(2)}, {int64(3), AlgES256}, {int64(-1), int64(1)}, {int64(-2), x}, {int64(-3), y}})
+	case ed25519.PublicKey:
+		return encodeCBOR(cborMap{{int64(1), int64(1)}, {int64(3), AlgEdDSA}, {int64(-1), int64(6)}, {int64(-2), []byte(pub)}})
+	}
+
+	a.t.Fatalf("unsupported public key %T", a.signer.Public())
+	return nil
+}
+
+// authenticatorData membuat rpIdHash | flags | signCount dan attested credential data jika attested
+func (a *softAuthenticator) authenticatorData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	data := append([]byte{}, rpIDHash[:]...)
+
+	flags := a.Flags
+	if attested {
+		flags |= flagAttestedCredData
+	}
+	data = append(data, flags)
+	data = binary.BigEndian.AppendUint32(data, a.SignCount)
+
+	if attested {
+		data = append(data, make([]byte, 16)...) // aaguid
+		data = binary.BigEndian.AppendUint16(data, uint16(len(a.credentialID)))
+		data = append(data, a.credentialID...)
+		data = append(data, a.coseKey()...)
+	}
+
+	return data
+}
+
+func (a *softAuthenticator) clientData(ceremony, challenge string) []byte {
+	data, err := json.Marshal(map[string]any{"type": ceremony, "challenge": challenge, "origin": a.Origin, "crossOrigin": false})
+	if err != nil {
+		a.t.Fatalf("failed to encode client data: %v", err)
+	}
+
+	return data
+}
+
+func (a *softAuthenticator) create(challenge string) *AttestationResponse {
+	attestation := encodeCBOR(cborMap{{"fmt", "none"}, {"attStmt", cborMap{}}, {"authData", a.authenticatorData(true)}})
+
+	response := &AttestationResponse{ID: EncodeID(a.credentialID), RawID: EncodeID(a.credentialID), Type: "public-key"}
+	response.Response.ClientDataJSON = EncodeID(a.clientData("webauthn.create", challenge))
+	response.Response.AttestationObject = EncodeID(attestation)
+	response.Response.Transports = []string{"internal"}
+
+	return response
+}
+
+func (a *softAuthenticator) get(challenge string) *AssertionResponse {
+	authData := a.authenticatorData(false)
+	clientData := a.clientData("webauthn.get", challenge)
+	clientDataHash := sha256.Sum256(clientData)
+
+	response := &AssertionResponse{ID: EncodeID(a.credentialID), RawID: EncodeID(a.credentialID), Type: "public-key"}
+	response.Response.ClientDataJSON = EncodeID(clientData)
+	response.Response.AuthenticatorData = EncodeID(authData)
+	response.Response.Signature = EncodeID(a.sign(append(authData, clientDataHash[:]...)))
+
+	return response
+}
+
+func (a *softAuthenticator) sign(data []byte) []byte {
+	var signature []byte
+	var err error
+	switch key := a.signer.(type) {
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256(data)
+		signature, err = ecdsa.SignASN1(rand.Reader, key, digest[:])
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, data)
+	}
+	if err != nil {
+		a.t.Fatalf("failed to sign: %v", err)
+	}
+
+	return signature
+}
+
+func newTestWebAuthn() *WebAuthn {
+	return New(Config{RPID: testRPID, RPName: "Test", Origins: []string{testOrigin}})
+}
+
+func newTestChallenge(t *testing.T) string {
+	t.Helper()
+
+	challenge, err := NewChallenge()
+	if err != nil {
+		t.Fatalf("failed to generate challenge: %v", err)
+	}
+
+	return challenge
+}
+
+var testAlgorithms = map[string]int64{"ES256": AlgES256, "Ed25519": AlgEdDSA}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	for name, algorithm := range testAlgorithms {
+		t.Run(name, func(t *testing.T) {
+			webAuthn := newTestWebAuthn()
+			authenticator := newSoftAuthenticator(t, algorithm)
+
+			challenge := newTestChallenge(t)
+			credential, err := webAuthn.VerifyRegistration(authenticator.create(challenge), challenge)
+			if err != nil {
+				t.Fatalf("VerifyRegistration returned error: %v", err)
+			}
+
+			if string(credential.ID) != string(authenticator.credentialID) {
+				t.Errorf("credential id = %x, want %x", credential.ID, authenticator.credentialID)
+			}
+			if credential.Algorithm != algorithm {
+				t.Errorf("credential algorithm = %d, want %d", credential.Algorithm, algorithm)
+			}
+			if !credential.UserVerified {
+				t.Error("credential is not user verified")
+			}
+			if len(credential.Transports) != 1 || credential.Transports[0] != "internal" {
+				t.Errorf("credential transports = %v, want [internal]", credential.Transports)
+			}
+
+			for _, signCount := range []uint32{1, 2, 10} {
+				authenticator.SignCount = signCount
+				challenge = newTestChallenge(t)
+
+				assertion, err := webAuthn.VerifyAssertion(authenticator.get(challenge), challenge, credential.PublicKey, credential.SignCount)
+				if err != nil {
+					t.Fatalf("VerifyAssertion with sign count %d returned error: %v", signCount, err)
+				}
+				if assertion.SignCount != signCount || !assertion.UserVerified {
+					t.Errorf("assertion = %+v, want sign count %d and user verified", assertion, signCount)
+				}
+				credential.SignCount = assertion.SignCount
+			}
+		})
+	}
+}
+
+func TestAssertionWithoutSignCounter(t *testing.T) {
+	webAuthn := newTestWebAuthn()
+	authenticator := newSoftAuthenticator(t, AlgEdDSA)
+
+	challenge := newTestChallenge(t)
+	credential, err := webAuthn.VerifyRegistration(authenticator.create(challenge), challenge)
+	if err != nil {
+		t.Fatalf("VerifyRegistration returned error: %v", err)
+	}
+
+	// authenticator yang tidak memakai counter selalu mengirim 0
+	for range 2 {
+		challenge = newTestChallenge(t)
+		if _, err = webAuthn.VerifyAssertion(authenticator.get(challenge), challenge, credential.PublicKey, 0); err != nil {
+			t.Fatalf("VerifyAssertion returned error: %v", err)
+		}
+	}
+}
+
+func TestVerifyRegistrationRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  func(config *Config)
+		modify  func(authenticator *softAuthenticator)
+		respond func(response *AttestationResponse, challenge string) string // mengubah response, mengembalikan challenge yang diharapkan
+		wantErr error
+	}{
+		{
+			name:    "wrong origin",
+			modify:  func(a *softAuthenticator) { a.Origin = "https://evil.example.com" },
+			wantErr: ErrOriginMismatch,
+		},
+		{
+			name:    "wrong rp id hash",
+			modify:  func(a *softAuthenticator) { a.RPID = "evil.example.com" },
+			wantErr: ErrRPIDMismatch,
+		},
+		{
+			name:    "wrong challenge",
+			respond: func(response *AttestationResponse, challenge string) string { return challenge + "x" },
+			wantErr: ErrChallengeMismatch,
+		},
+		{
+			name:    "missing user presence",
+			modify:  func(a *softAuthenticator) { a.Flags = flagUserVerified },
+			wantErr: ErrUserNotPresent,
+		},
+		{
+			name:    "missing required user verification",
+			config:  func(config *Config) { config.UserVerification = "required" },
+			modify:  func(a *softAuthenticator) { a.Flags = flagUserPresent },
+			wantErr: ErrUserNotVerified,
+		},
+		{
+			name: "assertion client data",
+			respond: func(response *AttestationResponse, challenge string) string {
+				raw, _ := DecodeID(response.Response.ClientDataJSON)
+				var data map[string]any
+				_ = json.Unmarshal(raw, &data)
+				data["type"] = "webauthn.get"
+				raw, _ = json.Marshal(data)
+				response.Response.ClientDataJSON = EncodeID(raw)
+				return challenge
+			},
+			wantErr: ErrInvalidClientData,
+		},
+		{
+			name: "raw id does not match credential",
+			respond: func(response *AttestationResponse, challenge string) string {
+				response.RawID = EncodeID([]byte("another-credential"))
+				return challenge
+			},
+			wantErr: ErrInvalidResponse,
+		},
+		{
+			name: "truncated attestation object",
+			respond: func(response *AttestationResponse, challenge string) string {
+				raw, _ := DecodeID(response.Response.AttestationObject)
+				response.Response.AttestationObject = EncodeID(raw[:len(raw)-10])
+				return challenge
+			},
+			wantErr: ErrInvalidResponse,
+		},
+		{
+			name: "attestation object is not a map",
+			respond: func(response *AttestationResponse, challenge string) string {
+				response.Response.AttestationObject = EncodeID(encodeCBOR([]any{"fmt", "none"}))
+				return challenge
+			},
+			wantErr: ErrInvalidResponse,
+		},
+		{
+			name: "truncated authenticator data",
+			respond: func(response *AttestationResponse, challenge string) string {
+				raw, _ := DecodeID(response.Response.AttestationObject)
+				value, _, _ := decodeCBOR(raw)
+				authData := value.(map[any]any)["authData"].([]byte)
+				response.Response.AttestationObject = EncodeID(encodeCBOR(cborMap{{"fmt", "none"}, {"attStmt", cborMap{}}, {"authData", authData[:len(authData)-5]}}))
+				return challenge
+			},
+			wantErr: ErrInvalidAuthenticatorData,
+		},
+		{
+			name: "trailing bytes after authenticator data",
+			respond: func(response *AttestationResponse, challenge string) string {
+				raw, _ := DecodeID(response.Response.AttestationObject)
+				value, _, _ := decodeCBOR(raw)
+				authData := value.(map[any]any)["authData"].([]byte)
+				response.Response.AttestationObject = EncodeID(encodeCBOR(cborMap{{"fmt", "none"}, {"attStmt", cborMap{}}, {"authData", append(authData, 0)}}))
+				return challenge
+			},
+			wantErr: ErrInvalidAuthenticatorData,
+		},
+		{
+			name: "malformed client data",
+			respond: func(response *AttestationResponse, challenge string) string {
+				response.Response.ClientDataJSON = EncodeID([]byte(`{"type":`))
+				return challenge
+			},
+			wantErr: ErrInvalidClientData,
+		},
+		{
+			name: "wrong credential type",
+			respond: func(response *AttestationResponse, challenge string) string {
+				response.Type = "password"
+				return challenge
+			},
+			wantErr: ErrInvalidResponse,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := Config{RPID: testRPID, RPName: "Test", Origins: []string{testOrigin}}
+			if test.config != nil {
+				test.config(&config)
+			}
+			webAuthn := New(config)
+
+			authenticator := newSoftAuthenticator(t, AlgES256)
+			if test.modify != nil {
+				test.modify(authenticator)
+			}
+
+			challenge := newTestChallenge(t)
+			response := authenticator.create(challenge)
+			if test.respond != nil {
+				challenge = test.respond(response, challenge)
+			}
+
+			credential, err := webAuthn.VerifyRegistration(response, challenge)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("VerifyRegistration = %+v, %v, want error %v", credential, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyAssertionRejects(t *testing.T) {
+	tests := []struct {
+		name            string
+		modify          func(authenticator *softAuthenticator)
+		respond         func(response *AssertionResponse, challenge string) string
+		storedSignCount uint32
+		wantErr         error
+	}{
+		{
+			name:    "wrong origin",
+			modify:  func(a *softAuthenticator) { a.Origin = "https://evil.example.com" },
+			wantErr: ErrOriginMismatch,
+		},
+		{
+			name:    "wrong rp id hash",
+			modify:  func(a *softAuthenticator) { a.RPID = "evil.example.com" },
+			wantErr: ErrRPIDMismatch,
+		},
+		{
+			name:    "wrong challenge",
+			respond: func(response *AssertionResponse, challenge string) string { return challenge + "x" },
+			wantErr: ErrChallengeMismatch,
+		},
+		{
+			name:    "missing user presence",
+			modify:  func(a *softAuthenticator) { a.Flags = flagUserVerified },
+			wantErr: ErrUserNotPresent,
+		},
+		{
+			name:            "sign count not increased",
+			modify:          func(a *softAuthenticator) { a.SignCount = 5 },
+			storedSignCount: 5,
+			wantErr:         ErrSignCountInvalid,
+		},
+		{
+			name:            "sign count decreased",
+			modify:          func(a *softAuthenticator) { a.SignCount = 3 },
+			storedSignCount: 5,
+			wantErr:         ErrSignCountInvalid,
+		},
+		{
+			name:            "sign count reset to zero",
+			modify:          func(a *softAuthenticator) { a.SignCount = 0 },
+			storedSignCount: 5,
+			wantErr:         ErrSignCountInvalid,
+		},
+		{
+			name: "signature over other data",
+			respond: func(response *AssertionResponse, challenge string) string {
+				raw, _ := DecodeID(response.Response.AuthenticatorData)
+				raw[33] ^= 0xff // sign count berubah setelah ditandatangani
+				response.Response.AuthenticatorData = EncodeID(raw)
+				return challenge
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "truncated signature",
+			respond: func(response *AssertionResponse, challenge string) string {
+				raw, _ := DecodeID(response.Response.Signature)
+				response.Response.Signature = EncodeID(raw[:len(raw)/2])
+				return challenge
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "truncated authenticator data",
+			respond: func(response *AssertionResponse, challenge string) string {
+				raw, _ := DecodeID(response.Response.AuthenticatorData)
+				response.Response.AuthenticatorData = EncodeID(raw[:36])
+				return challenge
+			},
+			wantErr: ErrInvalidAuthenticatorData,
+		},
+		{
+			name: "extension flag without extension data",
+			respond: func(response *AssertionResponse, challenge string) string {
+				raw, _ := DecodeID(response.Response.AuthenticatorData)
+				raw[32] |= flagExtensionData
+				response.Response.AuthenticatorData = EncodeID(raw)
+				return challenge
+			},
+			wantErr: ErrInvalidAuthenticatorData,
+		},
+		{
+			name: "registration client data",
+			respond: func(response *AssertionResponse, challenge string) string {
+				raw, _ := DecodeID(response.Response.ClientDataJSON)
+				var data map[string]any
+				_ = json.Unmarshal(raw, &data)
+				data["type"] = "webauthn.create"
+				raw, _ = json.Marshal(data)
+				response.Response.ClientDataJSON = EncodeID(raw)
+				return challenge
+			},
+			wantErr: ErrInvalidClientData,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			webAuthn := newTestWebAuthn()
+			authenticator := newSoftAuthenticator(t, AlgES256)
+
+			challenge := newTestChallenge(t)
+			credential, err := webAuthn.VerifyRegistration(authenticator.create(challenge), challenge)
+			if err != nil {
+				t.Fatalf("VerifyRegistration returned error: %v", err)
+			}
+
+			authenticator.SignCount = test.storedSignCount + 1
+			if test.modify != nil {
+				test.modify(authenticator)
+			}
+
+			challenge = newTestChallenge(t)
+			response := authenticator.get(challenge)
+			if test.respond != nil {
+				challenge = test.respond(response, challenge)
+			}
+
+			assertion, err := webAuthn.VerifyAssertion(response, challenge, credential.PublicKey, test.storedSignCount)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("VerifyAssertion = %+v, %v, want error %v", assertion, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyAssertionRejectsAnotherCredentialKey(t *testing.T) {
+	webAuthn := newTestWebAuthn()
+
+	registered := newSoftAuthenticator(t, AlgEdDSA)
+	challenge := newTestChallenge(t)
+	credential, err := webAuthn.VerifyRegistration(registered.create(challenge), challenge)
+	if err != nil {
+		t.Fatalf("VerifyRegistration returned error: %v", err)
+	}
+
+	another := newSoftAuthenticator(t, AlgEdDSA)
+	another.SignCount = 1
+	challenge = newTestChallenge(t)
+	if _, err = webAuthn.VerifyAssertion(another.get(challenge), challenge, credential.PublicKey, 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("VerifyAssertion error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestClientDataChallenge(t *testing.T) {
+	authenticator := newSoftAuthenticator(t, AlgES256)
+	challenge := newTestChallenge(t)
+
+	got, err := ClientDataChallenge(authenticator.get(challenge).Response.ClientDataJSON)
+	if err != nil || got != challenge {
+		t.Errorf("ClientDataChallenge = %q, %v, want %q", got, err, challenge)
+	}
+
+	if _, err = ClientDataChallenge(EncodeID([]byte(`{"type":"webauthn.get"}`))); !errors.Is(err, ErrInvalidClientData) {
+		t.Errorf("ClientDataChallenge without challenge error = %v, want %v", err, ErrInvalidClientData)
+	}
+}